@@ -0,0 +1,197 @@
+package change
+
+import "math"
+
+// ADWIN is the adaptive windowing detector of Bifet and Gavaldà (2007).  It
+// keeps a window of recent items whose length adapts to the data: whenever
+// two sub-windows have means that differ by more than is statistically
+// plausible, the older sub-window is dropped.  This removes the need to choose
+// a fixed window size up front.
+//
+// The window is stored compressed as an exponential histogram of buckets, so
+// memory and time per item grow only logarithmically with the window length.
+type ADWIN struct {
+	delta float64
+
+	items int
+
+	// buckets are ordered from oldest to newest, and hold a number of items
+	// which is a power of two and never increases from old to new
+	buckets []adwinBucket
+
+	n     int
+	total float64
+	m2    float64
+}
+
+// adwinBucket summarises a run of consecutive items
+type adwinBucket struct {
+	n     int
+	total float64
+	m2    float64
+}
+
+func (b adwinBucket) mean() float64 { return b.total / float64(b.n) }
+
+// merge combines two buckets, using the parallel variance formula of Chan et al.
+func (b adwinBucket) merge(o adwinBucket) adwinBucket {
+	if b.n == 0 {
+		return o
+	}
+	if o.n == 0 {
+		return b
+	}
+
+	d := b.mean() - o.mean()
+	n1, n2 := float64(b.n), float64(o.n)
+	return adwinBucket{
+		n:     b.n + o.n,
+		total: b.total + o.total,
+		m2:    b.m2 + o.m2 + d*d*n1*n2/(n1+n2),
+	}
+}
+
+func (b adwinBucket) stats() Stats {
+	s := Stats{n: b.n}
+	if b.n > 0 {
+		s.mean = b.mean()
+	}
+	if b.n > 1 {
+		s.variance = b.m2 / float64(b.n-1)
+	}
+	return s
+}
+
+const (
+	// adwinMaxBuckets is the number of buckets of each size kept before two are merged
+	adwinMaxBuckets = 5
+
+	// adwinMinLength is the minimum length of a sub-window to be tested
+	adwinMinLength = 5
+)
+
+// NewADWIN constructs an ADWIN detector.  Delta is the confidence parameter:
+// the probability of a false alarm is bounded by delta, so smaller values
+// (e.g. 0.002) make detection more conservative.
+func NewADWIN(delta float64) *ADWIN {
+	return &ADWIN{delta: delta}
+}
+
+// Push adds a float to the stream and returns a change point if the window
+// shrinks.  The change point's Index is the offset in the stream of the first
+// item still in the window, Before is the statistics of the items that were
+// dropped, and After is the statistics of the remaining window.
+func (a *ADWIN) Push(item float64) *ChangePoint {
+	a.items++
+	a.insert(adwinBucket{n: 1, total: item})
+
+	var dropped adwinBucket
+	for a.cut() {
+		dropped = dropped.merge(a.buckets[0])
+		a.remove()
+	}
+
+	if dropped.n == 0 {
+		return nil
+	}
+
+	before := dropped.stats()
+	after := a.window().stats()
+
+	return &ChangePoint{
+		Index:      a.items - a.n,
+		Difference: after.Mean() - before.Mean(),
+		Statistic:  math.Abs(after.Mean() - before.Mean()),
+		Before:     before,
+		After:      after,
+	}
+}
+
+// Width returns the number of items in the current window
+func (a *ADWIN) Width() int { return a.n }
+
+// Mean returns the mean of the current window
+func (a *ADWIN) Mean() float64 { return a.window().stats().Mean() }
+
+func (a *ADWIN) window() adwinBucket {
+	return adwinBucket{n: a.n, total: a.total, m2: a.m2}
+}
+
+func (a *ADWIN) insert(b adwinBucket) {
+	w := a.window().merge(b)
+	a.n, a.total, a.m2 = w.n, w.total, w.m2
+
+	a.buckets = append(a.buckets, b)
+
+	// Compress the histogram: whenever there are too many buckets of one
+	// size, merge the two oldest into a bucket of the next size.  Buckets of
+	// the same size are adjacent, so this is a single pass from new to old.
+	end := len(a.buckets)
+	for size := 1; ; size *= 2 {
+		start := end
+		for start > 0 && a.buckets[start-1].n == size {
+			start--
+		}
+
+		if end-start <= adwinMaxBuckets {
+			break
+		}
+
+		a.buckets[start] = a.buckets[start].merge(a.buckets[start+1])
+		a.buckets = append(a.buckets[:start+1], a.buckets[start+2:]...)
+		end = start + 1
+	}
+}
+
+// remove drops the oldest bucket from the window
+func (a *ADWIN) remove() {
+	b := a.buckets[0]
+	a.buckets = a.buckets[1:]
+
+	if a.n == b.n {
+		a.n, a.total, a.m2 = 0, 0, 0
+		return
+	}
+
+	// reverse of merge
+	rest := adwinBucket{n: a.n - b.n, total: a.total - b.total}
+	d := b.mean() - rest.mean()
+	n1, n2 := float64(b.n), float64(rest.n)
+	a.n, a.total = rest.n, rest.total
+	a.m2 = math.Max(0, a.m2-b.m2-d*d*n1*n2/(n1+n2))
+}
+
+// cut reports whether the window should be shrunk: whether some split of it
+// into an older and a newer sub-window has means that differ significantly
+func (a *ADWIN) cut() bool {
+	if a.n < 2*adwinMinLength {
+		return false
+	}
+
+	n := float64(a.n)
+	variance := a.m2 / n
+	dd := math.Log(2 * math.Log(n) / a.delta)
+
+	var old adwinBucket
+	for _, b := range a.buckets[:len(a.buckets)-1] {
+		old = old.merge(b)
+		if old.n < adwinMinLength {
+			continue
+		}
+
+		n0, n1 := float64(old.n), float64(a.n-old.n)
+		if n1 < adwinMinLength {
+			break
+		}
+
+		m := 1/(n0-adwinMinLength+1) + 1/(n1-adwinMinLength+1)
+		eps := math.Sqrt(2*m*variance*dd) + 2.0/3*dd*m
+
+		mean1 := (a.total - old.total) / n1
+		if math.Abs(old.mean()-mean1) > eps {
+			return true
+		}
+	}
+
+	return false
+}
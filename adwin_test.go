@@ -0,0 +1,63 @@
+package change
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestADWIN(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	a := NewADWIN(0.002)
+
+	detected := -1
+	for i, v := range steps(rnd, 1000, 0, 0, 3) {
+		r := a.Push(v)
+		if r != nil && detected == -1 {
+			detected = i
+			if r.Difference <= 0 {
+				t.Errorf("ADWIN difference=%f, wanted > 0", r.Difference)
+			}
+		}
+	}
+
+	if detected < 2000 || detected > 2050 {
+		t.Errorf("ADWIN detected change at %d, wanted shortly after 2000", detected)
+	}
+
+	// The window is cut at bucket boundaries so may still hold a few items
+	// from before the change, but it should have adapted to the new mean
+	if a.Width() > 1100 {
+		t.Errorf("ADWIN window width=%d, wanted ~1000", a.Width())
+	}
+
+	if m := a.Mean(); m < 2.8 || m > 3.2 {
+		t.Errorf("ADWIN mean=%f, wanted ~3", m)
+	}
+}
+
+func TestADWINBuckets(t *testing.T) {
+	a := NewADWIN(0.002)
+
+	for i := 0; i < 1000; i++ {
+		if r := a.Push(1); r != nil {
+			t.Fatalf("ADWIN found change in constant stream at %d", i)
+		}
+	}
+
+	var n int
+	for i, b := range a.buckets {
+		n += b.n
+		if i > 0 && b.n > a.buckets[i-1].n {
+			t.Fatalf("ADWIN bucket %d has %d items, more than the older bucket", i, b.n)
+		}
+	}
+
+	if n != 1000 || a.Width() != 1000 {
+		t.Errorf("ADWIN buckets hold %d items with width %d, wanted 1000", n, a.Width())
+	}
+
+	if len(a.buckets) > adwinMaxBuckets*10 {
+		t.Errorf("ADWIN has %d buckets, wanted logarithmic growth", len(a.buckets))
+	}
+}
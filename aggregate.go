@@ -0,0 +1,162 @@
+package change
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Aggregation summarises a bucket of items as a single item, for bucketing
+// high-frequency data to the granularity at which changes matter.
+type Aggregation int
+
+const (
+	// AggregateMean is the mean of the bucket
+	AggregateMean Aggregation = iota
+
+	// AggregateMedian is the median of the bucket
+	AggregateMedian
+
+	// AggregateMin is the smallest item in the bucket
+	AggregateMin
+
+	// AggregateMax is the largest item in the bucket
+	AggregateMax
+
+	// AggregateSum is the sum of the bucket, such as a count of events
+	AggregateSum
+
+	// AggregateP95 is the 95th percentile of the bucket
+	AggregateP95
+
+	// AggregateP99 is the 99th percentile of the bucket
+	AggregateP99
+)
+
+func (a Aggregation) String() string {
+	switch a {
+	case AggregateMean:
+		return "mean"
+	case AggregateMedian:
+		return "median"
+	case AggregateMin:
+		return "min"
+	case AggregateMax:
+		return "max"
+	case AggregateSum:
+		return "sum"
+	case AggregateP95:
+		return "p95"
+	case AggregateP99:
+		return "p99"
+	}
+	return "unknown"
+}
+
+// Of returns the aggregate of bucket.  The percentiles sort bucket in place.
+// The aggregate of an empty bucket is NaN.
+func (a Aggregation) Of(bucket []float64) float64 {
+	if len(bucket) == 0 {
+		return math.NaN()
+	}
+
+	switch a {
+	case AggregateMean:
+		return sampleStats(bucket).Mean()
+	case AggregateMin:
+		v := bucket[0]
+		for _, x := range bucket[1:] {
+			v = math.Min(v, x)
+		}
+		return v
+	case AggregateMax:
+		v := bucket[0]
+		for _, x := range bucket[1:] {
+			v = math.Max(v, x)
+		}
+		return v
+	case AggregateSum:
+		var sum kahan
+		for _, x := range bucket {
+			sum.add(x)
+		}
+		return sum.value()
+	}
+
+	q := 0.5
+	switch a {
+	case AggregateP95:
+		q = 0.95
+	case AggregateP99:
+		q = 0.99
+	}
+
+	sort.Float64s(bucket)
+	return quantile(bucket, q)
+}
+
+// Downsample returns the aggregate of each run of size items of series.  The
+// last bucket holds the remaining items, and may be smaller.
+func Downsample(series []float64, size int, agg Aggregation) []float64 {
+	if size < 1 {
+		size = 1
+	}
+
+	out := make([]float64, 0, (len(series)+size-1)/size)
+	buf := make([]float64, 0, size)
+	for i := 0; i < len(series); i += size {
+		j := i + size
+		if j > len(series) {
+			j = len(series)
+		}
+		buf = append(buf[:0], series[i:j]...)
+		out = append(out, agg.Of(buf))
+	}
+
+	return out
+}
+
+// Bucketer aggregates timestamped items into buckets of a fixed duration,
+// aligned to multiples of it since the zero time.  A bucket is complete once
+// an item arrives for a later bucket; buckets with no items are skipped.
+type Bucketer struct {
+	width time.Duration
+	agg   Aggregation
+
+	start time.Time
+	items []float64
+}
+
+// NewBucketer returns a bucketer aggregating items into buckets of the given width
+func NewBucketer(width time.Duration, agg Aggregation) *Bucketer {
+	return &Bucketer{width: width, agg: agg}
+}
+
+// Push adds an item to its bucket.  If it completes the previous bucket, that
+// bucket's start time and aggregate are returned with ok set, ready to push
+// to a Rolling or Timed detector.  The timestamps should not decrease.
+func (b *Bucketer) Push(t time.Time, item float64) (start time.Time, value float64, ok bool) {
+	bucket := t.Truncate(b.width)
+
+	if len(b.items) > 0 && !bucket.Equal(b.start) {
+		start, value, ok = b.Flush()
+	}
+
+	b.start = bucket
+	b.items = append(b.items, item)
+
+	return start, value, ok
+}
+
+// Flush returns the start time and aggregate of the current bucket, which may
+// be incomplete, and empties it.  ok is false if the bucket is empty.
+func (b *Bucketer) Flush() (start time.Time, value float64, ok bool) {
+	if len(b.items) == 0 {
+		return time.Time{}, 0, false
+	}
+
+	start, value = b.start, b.agg.Of(b.items)
+	b.items = b.items[:0]
+
+	return start, value, true
+}
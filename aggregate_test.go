@@ -0,0 +1,91 @@
+package change
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestAggregation(t *testing.T) {
+	bucket := []float64{5, 1, 4, 2, 3}
+
+	var tests = []struct {
+		agg  Aggregation
+		want float64
+	}{
+		{AggregateMean, 3},
+		{AggregateMedian, 3},
+		{AggregateMin, 1},
+		{AggregateMax, 5},
+		{AggregateSum, 15},
+		{AggregateP95, 4.8},
+		{AggregateP99, 4.96},
+	}
+
+	for _, tt := range tests {
+		b := append([]float64(nil), bucket...)
+		if got := tt.agg.Of(b); math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("%v.Of(%v)=%v, wanted %v", tt.agg, bucket, got, tt.want)
+		}
+	}
+
+	if got := AggregateMean.Of(nil); !math.IsNaN(got) {
+		t.Errorf("Of(nil)=%v, wanted NaN", got)
+	}
+}
+
+func TestDownsample(t *testing.T) {
+	got := Downsample([]float64{1, 2, 3, 4, 5, 6, 7}, 3, AggregateMax)
+	want := []float64{3, 6, 7}
+
+	if len(got) != len(want) {
+		t.Fatalf("Downsample()=%v, wanted %v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("Downsample()=%v, wanted %v", got, want)
+			break
+		}
+	}
+}
+
+func TestBucketer(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(secs int) time.Time { return start.Add(time.Duration(secs) * time.Second) }
+
+	b := NewBucketer(time.Minute, AggregateMean)
+
+	type bucket struct {
+		start time.Time
+		value float64
+	}
+
+	var got []bucket
+	for _, s := range []struct {
+		secs int
+		v    float64
+	}{{0, 1}, {30, 3}, {59, 5}, {60, 10}, {200, 7}, {201, 9}} {
+		if bt, v, ok := b.Push(at(s.secs), s.v); ok {
+			got = append(got, bucket{bt, v})
+		}
+	}
+	if bt, v, ok := b.Flush(); ok {
+		got = append(got, bucket{bt, v})
+	}
+
+	// the minute from 120s to 180s had no items
+	want := []bucket{{at(0), 3}, {at(60), 10}, {at(180), 8}}
+	if len(got) != len(want) {
+		t.Fatalf("Bucketer buckets=%v, wanted %v", got, want)
+	}
+	for i := range got {
+		if !got[i].start.Equal(want[i].start) || got[i].value != want[i].value {
+			t.Errorf("Bucketer buckets=%v, wanted %v", got, want)
+			break
+		}
+	}
+
+	if _, _, ok := b.Flush(); ok {
+		t.Errorf("Flush() of an empty bucket ok=true, wanted false")
+	}
+}
@@ -0,0 +1,141 @@
+package change
+
+import (
+	"math"
+	"sort"
+)
+
+// AndersonDarling is the k-sample Anderson-Darling test of Scholz and
+// Stephens (1987), applied to the two samples either side of the change
+// point.  It weights differences in the tails of the distribution more
+// heavily than the Kolmogorov-Smirnov or Cramér-von Mises tests, which makes
+// it well suited to validating changes in heavy-tailed data such as latency.
+//
+// The reported statistic is the standardised statistic T, using the version
+// for data with ties.  The confidence is interpolated from the table of
+// critical values of Scholz and Stephens.
+type AndersonDarling struct{}
+
+// Test implements the Test interface
+func (AndersonDarling) Test(before, after []float64) (float64, float64) {
+	t := andersonDarling(before, after)
+	return t, 1 - adPValue(t, 1)
+}
+
+// andersonDarling returns the standardised k-sample Anderson-Darling statistic of the samples
+func andersonDarling(samples ...[]float64) float64 {
+	k := float64(len(samples))
+
+	var pooled []float64
+	for _, s := range samples {
+		pooled = append(pooled, s...)
+	}
+	sort.Float64s(pooled)
+	n := float64(len(pooled))
+
+	// the distinct values and their multiplicities
+	var z, l []float64
+	for i := 0; i < len(pooled); {
+		j := i + 1
+		for j < len(pooled) && pooled[j] == pooled[i] {
+			j++
+		}
+		z = append(z, pooled[i])
+		l = append(l, float64(j-i))
+		i = j
+	}
+
+	var a2 float64
+	for _, s := range samples {
+		sorted := append([]float64(nil), s...)
+		sort.Float64s(sorted)
+		ni := float64(len(sorted))
+
+		var inner, b, m float64
+		var idx int
+		for j := range z {
+			// f is the number of items in this sample equal to z[j]
+			var f float64
+			for idx < len(sorted) && sorted[idx] == z[j] {
+				f++
+				idx++
+			}
+
+			b += l[j]
+			m += f
+			ba := b - l[j]/2
+			ma := m - f/2
+
+			den := ba*(n-ba) - n*l[j]/4
+			if den > 0 {
+				d := n*ma - ni*ba
+				inner += l[j] * d * d / den
+			}
+		}
+		a2 += inner / ni
+	}
+	a2 *= (n - 1) / (n * n)
+
+	// the variance of the statistic under the null hypothesis
+	var h, hs float64
+	for _, s := range samples {
+		hs += 1 / float64(len(s))
+	}
+	for i := 1.0; i < n; i++ {
+		h += 1 / i
+	}
+
+	g := adG(n, h)
+
+	a := (4*g-6)*(k-1) + (10-6*g)*hs
+	bb := (2*g-4)*k*k + 8*h*k + (2*g-14*h-4)*hs - 8*h + 4*g - 6
+	c := (6*h+2*g-2)*k*k + (4*h-4*g+6)*k + (2*h-6)*hs + 4*h
+	d := (2*h+6)*k*k - 4*h*k
+
+	variance := (a*n*n*n + bb*n*n + c*n + d) / ((n - 1) * (n - 2) * (n - 3))
+
+	return (a2 - (k - 1)) / math.Sqrt(variance)
+}
+
+// adG returns the sum over 1 <= i < j <= n-1 of 1/((n-i)*j), given h, the
+// harmonic number H(n-1).  The inner sum over j is H(n-1) - H(i), so it takes
+// linear time.
+func adG(n, h float64) float64 {
+	var g, hi float64
+	for i := 1.0; i <= n-2; i++ {
+		hi += 1 / i
+		g += (h - hi) / (n - i)
+	}
+	return g
+}
+
+// adPValue returns the p-value of the standardised Anderson-Darling statistic
+// t with m+1 samples, by fitting a quadratic in t to the logs of the
+// significance levels of the critical values of Scholz and Stephens
+func adPValue(t float64, m float64) float64 {
+	sig := []float64{0.25, 0.1, 0.05, 0.025, 0.01, 0.005, 0.001}
+	b0 := []float64{0.675, 1.281, 1.645, 1.96, 2.326, 2.573, 3.085}
+	b1 := []float64{-0.245, 0.25, 0.678, 1.149, 1.822, 2.364, 3.615}
+	b2 := []float64{-0.105, -0.305, -0.362, -0.391, -0.396, -0.345, -0.154}
+
+	// least squares fit of log(sig) = c0 + c1 x + c2 x^2 by the normal equations
+	ata := newMatrix(3)
+	atb := make([]float64, 3)
+	for i := range sig {
+		x := b0[i] + b1[i]/math.Sqrt(m) + b2[i]/m
+		row := []float64{1, x, x * x}
+		for a := 0; a < 3; a++ {
+			for b := 0; b < 3; b++ {
+				ata[a][b] += row[a] * row[b]
+			}
+			atb[a] += row[a] * math.Log(sig[i])
+		}
+	}
+
+	c, ok := solve(ata, atb)
+	if !ok {
+		return 1
+	}
+
+	return math.Max(0, math.Min(1, math.Exp(c[0]+c[1]*t+c[2]*t*t)))
+}
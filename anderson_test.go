@@ -0,0 +1,72 @@
+package change
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestAndersonDarlingStatistic(t *testing.T) {
+	// the smoothness measurements from four laboratories in Scholz and
+	// Stephens (1987), with published T=4.4798 and p=0.0022
+	samples := [][]float64{
+		{38.7, 41.5, 43.8, 44.5, 45.5, 46.0, 47.7, 58.0},
+		{39.2, 39.3, 39.7, 41.4, 41.8, 42.9, 43.3, 45.8},
+		{34.0, 35.0, 39.0, 40.0, 43.0, 43.0, 44.0, 45.0},
+		{34.0, 34.8, 34.8, 35.4, 37.2, 37.8, 41.2, 42.8},
+	}
+
+	got := andersonDarling(samples...)
+	if math.Abs(got-4.4798) > 1e-4 {
+		t.Errorf("andersonDarling()=%f, wanted 4.4798", got)
+	}
+
+	if p := adPValue(got, 3); math.Abs(p-0.0022) > 1e-4 {
+		t.Errorf("adPValue()=%f, wanted 0.0022", p)
+	}
+}
+
+func TestAndersonDarling(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2))
+
+	// heavy-tailed samples, where only the tail changes
+	var xs, ys, zs []float64
+	for i := 0; i < 100; i++ {
+		xs = append(xs, rnd.ExpFloat64())
+		ys = append(ys, rnd.ExpFloat64())
+
+		v := rnd.ExpFloat64()
+		if v > 1.5 {
+			v *= 3
+		}
+		zs = append(zs, v)
+	}
+
+	if _, conf := (AndersonDarling{}).Test(xs, ys); conf > 0.95 {
+		t.Errorf("AndersonDarling confidence=%f for the same distribution", conf)
+	}
+
+	if _, conf := (AndersonDarling{}).Test(xs, zs); conf < 0.95 {
+		t.Errorf("AndersonDarling confidence=%f for different tails", conf)
+	}
+}
+
+func TestADG(t *testing.T) {
+	for _, n := range []float64{3, 4, 10, 57, 500} {
+		var h float64
+		for i := 1.0; i < n; i++ {
+			h += 1 / i
+		}
+
+		var want float64
+		for i := 1.0; i <= n-2; i++ {
+			for j := i + 1; j <= n-1; j++ {
+				want += 1 / ((n - i) * j)
+			}
+		}
+
+		if got := adG(n, h); math.Abs(got-want) > 1e-12*want {
+			t.Errorf("adG(%v)=%v, wanted %v", n, got, want)
+		}
+	}
+}
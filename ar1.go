@@ -0,0 +1,76 @@
+package change
+
+import "math"
+
+// ar1MaxPhi bounds the estimated autocorrelation, so that a window which is
+// almost a random walk isn't differenced away entirely
+const ar1MaxPhi = 0.99
+
+// AR1 pre-whitens autocorrelated windows by filtering out a first-order
+// autoregressive process, x[i] - Phi*x[i-1].  Serial correlation makes
+// neighbouring items look like runs, which the t-test mistakes for changes
+// in the mean; the filtered items are closer to independent.  A shift in the
+// mean of the window becomes a shift (1-Phi) times as large, and the first
+// item is dropped.
+type AR1 struct {
+	// Phi is the lag-1 autocorrelation.  If zero, it is estimated from the
+	// window, after removing the means either side of its best split so
+	// that a change doesn't itself look like correlation.
+	Phi float64
+}
+
+// Apply implements the Transform interface
+func (a AR1) Apply(series []float64) []float64 {
+	if len(series) < 2 {
+		return nil
+	}
+
+	phi := a.Phi
+	if phi == 0 {
+		phi = lag1(series)
+	}
+
+	out := make([]float64, len(series)-1)
+	for i := range out {
+		out[i] = series[i+1] - phi*series[i]
+	}
+	return out
+}
+
+// lag1 returns the lag-1 autocorrelation of series, less the means either
+// side of its best split by the default detector, corrected for bias
+func lag1(series []float64) float64 {
+	before, after := sampleStats(series).Mean(), 0.0
+	s := (&Detector{}).scan(series)
+	if s.before.n > 0 {
+		before, after = s.before.Mean(), s.after.Mean()
+	} else {
+		s.index = len(series)
+	}
+
+	resid := make([]float64, len(series))
+	for i, v := range series {
+		if i < s.index {
+			resid[i] = v - before
+		} else {
+			resid[i] = v - after
+		}
+	}
+
+	var num, den float64
+	for i, v := range resid {
+		den += v * v
+		if i > 0 {
+			num += v * resid[i-1]
+		}
+	}
+	if den == 0 {
+		return 0
+	}
+
+	// the estimate is biased towards zero in short windows (Kendall 1954)
+	phi := num / den
+	phi += (1 + 3*phi) / float64(len(series))
+
+	return math.Max(-ar1MaxPhi, math.Min(ar1MaxPhi, phi))
+}
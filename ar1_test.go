@@ -0,0 +1,60 @@
+package change
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// ar1 returns n items of an AR(1) process with unit innovations
+func ar1(rnd *rand.Rand, n int, phi float64) []float64 {
+	w := make([]float64, n)
+	for i := range w {
+		w[i] = rnd.NormFloat64()
+		if i > 0 {
+			w[i] += phi * w[i-1]
+		}
+	}
+	return w
+}
+
+func TestAR1(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	plain := Detector{MinConfidence: 0.99}
+	white := Detector{MinConfidence: 0.99, Transform: AR1{}}
+
+	// correlated noise with no change
+	var plainFP, whiteFP int
+	for i := 0; i < 50; i++ {
+		w := ar1(rnd, 200, 0.7)
+		if plain.Check(w) != nil {
+			plainFP++
+		}
+		if white.Check(w) != nil {
+			whiteFP++
+		}
+	}
+
+	if plainFP < 20 || whiteFP > 10 {
+		t.Errorf("false positives without and with AR1=%d, %d, wanted many and few", plainFP, whiteFP)
+	}
+
+	// the estimate isn't confused by a change in the mean
+	w := ar1(rnd, 400, 0.5)
+	for i := 200; i < len(w); i++ {
+		w[i] += 3
+	}
+
+	if phi := lag1(w); math.Abs(phi-0.5) > 0.1 {
+		t.Errorf("lag1()=%f, wanted about 0.5", phi)
+	}
+
+	if r := white.Check(w); r == nil || r.Index < 195 || r.Index > 205 {
+		t.Errorf("Check(AR1)=%+v, wanted index 200", r)
+	}
+
+	if got := (AR1{Phi: 0.5}).Apply([]float64{2, 4, 4}); len(got) != 2 || got[0] != 3 || got[1] != 2 {
+		t.Errorf("AR1{0.5}.Apply(2, 4, 4)=%v, wanted [3 2]", got)
+	}
+}
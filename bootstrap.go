@@ -0,0 +1,153 @@
+package change
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// DefaultResamples is the number of bootstrap resamples used if none is given
+const DefaultResamples = 999
+
+// LocationInterval returns a bootstrap confidence interval at the given level,
+// such as 0.95, for the location of the change point cp found in window.  The
+// items before and after the change point are each resampled with
+// replacement, the change point of the resampled window is located again, and
+// the interval is the central range of the locations found.  A narrow
+// interval means the change is precisely localised.  The returned offsets are
+// into window, like cp.Index, but the resampled items are those of window
+// after the detector's Transform, Outliers and Missing policy, as it was
+// checked.
+//
+// If resamples is zero, DefaultResamples is used.  The resamples are drawn
+// using rnd; if it is nil, a fixed seed is used so results are reproducible.
+func (d *Detector) LocationInterval(window []float64, cp *ChangePoint, level float64, resamples int, rnd *rand.Rand) (lo, hi int) {
+	if resamples == 0 {
+		resamples = DefaultResamples
+	}
+	if rnd == nil {
+		rnd = rand.New(rand.NewSource(1))
+	}
+
+	p := d.prepare(window)
+	k := p.split(cp.Index)
+	before, after := p.series[:k], p.series[k:]
+
+	resampled := make([]float64, len(p.series))
+	locations := make([]float64, resamples)
+	for r := range locations {
+		for i := range before {
+			resampled[i] = before[rnd.Intn(len(before))]
+		}
+		for i := range after {
+			resampled[len(before)+i] = after[rnd.Intn(len(after))]
+		}
+		locations[r] = float64(p.index(d.locate(resampled)))
+	}
+
+	sort.Float64s(locations)
+
+	alpha := (1 - level) / 2
+	return int(math.Floor(quantile(locations, alpha))), int(math.Ceil(quantile(locations, 1-alpha)))
+}
+
+// locate returns the most likely change point in window, without testing it
+func (d *Detector) locate(window []float64) int {
+	if d.Homogeneity != nil {
+		idx, _, _ := d.Homogeneity.Locate(window)
+		return idx
+	}
+	return d.scan(window).index
+}
+
+// DifferenceInterval returns a bootstrap confidence interval at the given
+// level for the difference in means cp.Difference.  The items before and after
+// the change point found in window are each resampled with replacement, and
+// the interval is the central range of the differences of the resampled
+// means.  Unlike the t-based ChangePoint.DifferenceInterval, it does not
+// assume the means are normally distributed, which matters for skewed data
+// such as latencies.  Like LocationInterval, it resamples the window as it
+// was checked.
+//
+// If resamples is zero, DefaultResamples is used.  The resamples are drawn
+// using rnd; if it is nil, a fixed seed is used so results are reproducible.
+func (d *Detector) DifferenceInterval(window []float64, cp *ChangePoint, level float64, resamples int, rnd *rand.Rand) (lo, hi float64) {
+	if resamples == 0 {
+		resamples = DefaultResamples
+	}
+	if rnd == nil {
+		rnd = rand.New(rand.NewSource(1))
+	}
+
+	p := d.prepare(window)
+	k := p.split(cp.Index)
+	before, after := p.series[:k], p.series[k:]
+
+	resampledMean := func(xs []float64) float64 {
+		var sum float64
+		for range xs {
+			sum += xs[rnd.Intn(len(xs))]
+		}
+		return sum / float64(len(xs))
+	}
+
+	diffs := make([]float64, resamples)
+	for r := range diffs {
+		diffs[r] = resampledMean(after) - resampledMean(before)
+	}
+
+	sort.Float64s(diffs)
+
+	alpha := (1 - level) / 2
+	return quantile(diffs, alpha), quantile(diffs, 1-alpha)
+}
+
+// DifferenceInterval returns a confidence interval at the given level, such
+// as 0.95, for the difference in means, using Welch's t distribution.
+func (cp *ChangePoint) DifferenceInterval(level float64) (lo, hi float64) {
+	n1, n2 := float64(cp.Before.Len()), float64(cp.After.Len())
+	v1, v2 := cp.Before.Var()/n1, cp.After.Var()/n2
+
+	se := math.Sqrt(v1 + v2)
+	if se == 0 {
+		return cp.Difference, cp.Difference
+	}
+
+	df := (v1 + v2) * (v1 + v2) / (v1*v1/(n1-1) + v2*v2/(n2-1))
+	q := studentTQuantile(1-(1-level)/2, df)
+
+	return cp.Difference - q*se, cp.Difference + q*se
+}
+
+// blockBootstrapTest returns the fraction of moving-block bootstrap resamples
+// of window whose best split scores lower than observed.  The resamples keep
+// the serial dependence within each block.  They are of the window itself,
+// not the residuals from the means either side of its best split: those
+// would have no change, but understate the variation of the scores when
+// there is none, and give too many false positives.
+func (d *Detector) blockBootstrapTest(window []float64, observed float64, resamples int, rnd *rand.Rand) float64 {
+	n := len(window)
+
+	length := d.BlockLength
+	if length <= 0 {
+		length = int(math.Ceil(math.Cbrt(float64(n))))
+	}
+	if length > n {
+		length = n
+	}
+
+	resampled := make([]float64, n)
+
+	var exceed int
+	for r := 0; r < resamples; r++ {
+		for i := 0; i < n; i += length {
+			start := rnd.Intn(n - length + 1)
+			copy(resampled[i:], window[start:start+length])
+		}
+		if d.scan(resampled).sb >= observed {
+			exceed++
+		}
+	}
+
+	return 1 - float64(exceed+1)/float64(resamples+1)
+}
@@ -0,0 +1,130 @@
+package change
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestLocationInterval(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	var tests = []struct {
+		shift    float64
+		maxWidth int
+	}{
+		{3, 4},   // a large change is precisely located
+		{0.7, 0}, // a small one is not
+	}
+
+	d := Detector{MinConfidence: 0.99}
+
+	for _, tt := range tests {
+		w := steps(rnd, 100, 0, tt.shift)
+
+		cp := d.Check(w)
+		if cp == nil {
+			t.Errorf("Check(shift=%v) found no change point", tt.shift)
+			continue
+		}
+
+		lo, hi := d.LocationInterval(w, cp, 0.95, 0, nil)
+		if lo > cp.Index || hi < cp.Index || lo > 100 || hi < 100 {
+			t.Errorf("LocationInterval(shift=%v)=[%d,%d], wanted to contain %d and 100", tt.shift, lo, hi, cp.Index)
+		}
+
+		if tt.maxWidth > 0 && hi-lo > tt.maxWidth {
+			t.Errorf("LocationInterval(shift=%v)=[%d,%d], wanted width at most %d", tt.shift, lo, hi, tt.maxWidth)
+		}
+		if tt.maxWidth == 0 && hi-lo <= 4 {
+			t.Errorf("LocationInterval(shift=%v)=[%d,%d], wanted a wide interval", tt.shift, lo, hi)
+		}
+	}
+}
+
+func TestDifferenceInterval(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	d := Detector{MinConfidence: 0.99}
+
+	w := steps(rnd, 100, 10, 25)
+	cp := d.Check(w)
+	if cp == nil {
+		t.Fatalf("Check found no change point")
+	}
+
+	lo, hi := cp.DifferenceInterval(0.95)
+	if lo > 15 || hi < 15 || hi-lo > 1 {
+		t.Errorf("ChangePoint.DifferenceInterval()=[%f,%f], wanted a narrow interval around 15", lo, hi)
+	}
+
+	blo, bhi := d.DifferenceInterval(w, cp, 0.95, 0, nil)
+	if blo > 15 || bhi < 15 || bhi-blo > 1 {
+		t.Errorf("Detector.DifferenceInterval()=[%f,%f], wanted a narrow interval around 15", blo, bhi)
+	}
+
+	// for normal data the bootstrap interval is close to the t interval
+	if w := hi - lo; bhi-blo < 0.8*w || bhi-blo > 1.2*w {
+		t.Errorf("Detector.DifferenceInterval() width=%f, wanted close to %f", bhi-blo, w)
+	}
+}
+
+func TestIntervalsMissing(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// the same window with a few missing items, skipped by the detector
+	w := steps(rnd, 100, 0, 3)
+	var wn []float64
+	for i, v := range w {
+		if i%40 == 20 {
+			wn = append(wn, math.NaN())
+		}
+		wn = append(wn, v)
+	}
+
+	d := Detector{MinConfidence: 0.99, Missing: MissingSkip}
+	cp := d.Check(wn)
+	if cp == nil {
+		t.Fatalf("Check() found no change point")
+	}
+
+	lo, hi := d.LocationInterval(wn, cp, 0.95, 0, nil)
+	if lo > cp.Index || hi < cp.Index || hi-lo > 6 {
+		t.Errorf("LocationInterval() with missing items=[%d,%d], wanted a narrow interval around %d", lo, hi, cp.Index)
+	}
+
+	dlo, dhi := d.DifferenceInterval(wn, cp, 0.95, 0, nil)
+	if math.IsNaN(dlo) || math.IsNaN(dhi) || dlo > 3 || dhi < 3 {
+		t.Errorf("DifferenceInterval() with missing items=[%f,%f], wanted around 3", dlo, dhi)
+	}
+}
+
+func TestBlockBootstrap(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	d := Detector{MinConfidence: 0.95, BlockBootstrap: 99, BlockLength: 10}
+
+	// correlated noise with no change, which the t-test often mistakes for one
+	var fp int
+	for i := 0; i < 20; i++ {
+		if d.Check(ar1(rnd, 200, 0.7)) != nil {
+			fp++
+		}
+	}
+	if fp > 3 {
+		t.Errorf("BlockBootstrap false positives=%d of 20, wanted few", fp)
+	}
+
+	w := ar1(rnd, 200, 0.7)
+	for i := 100; i < len(w); i++ {
+		w[i] += 5
+	}
+
+	r := d.Check(w)
+	if r == nil || r.Index < 95 || r.Index > 105 {
+		t.Fatalf("Check(BlockBootstrap)=%+v, wanted index 100", r)
+	}
+	if r.Confidence != 0.99 || r.Statistic != r.Score {
+		t.Errorf("Check(BlockBootstrap) confidence=%f statistic=%f, wanted 0.99 and the score %f", r.Confidence, r.Statistic, r.Score)
+	}
+}
@@ -0,0 +1,100 @@
+package change
+
+import "math"
+
+// BuishandStatistic selects the statistic used by the Buishand test
+type BuishandStatistic int
+
+const (
+	// BuishandRange is the rescaled adjusted range R/sqrt(n): the range of
+	// the cumulative deviations from the mean, divided by the standard
+	// deviation
+	BuishandRange BuishandStatistic = iota
+
+	// BuishandU is the U statistic: the mean of the squared, scaled cumulative
+	// deviations, which is more powerful for shifts near the middle of the
+	// series
+	BuishandU
+)
+
+// Buishand is the Buishand (1982) test for a single shift in the mean, based
+// on the cumulative deviations from the mean of the series.  The change point
+// is where the cumulative deviation is largest.
+//
+// As with SNHT, the distribution of the statistic depends on the length of
+// the series, so the confidence is estimated by simulating series of normally
+// distributed noise of the same length, which replaces the usual table of
+// critical values.  The simulations for each length and statistic are done
+// once and cached.
+type Buishand struct {
+	// Statistic selects the test statistic
+	Statistic BuishandStatistic
+
+	// Simulations is the number of simulated series used to estimate the
+	// confidence, which is at most Simulations/(Simulations+1).  If zero,
+	// DefaultSimulations is used.
+	Simulations int
+}
+
+// Locate implements the Homogeneity interface
+func (b Buishand) Locate(window []float64) (int, float64, float64) {
+	idx, r, u := buishand(window)
+	if idx == 0 {
+		return 0, 0, 0
+	}
+
+	sims := b.Simulations
+	if sims == 0 {
+		sims = DefaultSimulations
+	}
+
+	stat, name := r, "buishand-range"
+	if b.Statistic == BuishandU {
+		stat, name = u, "buishand-u"
+	}
+
+	null := simulatedNull(name, len(window), sims, func(w []float64) float64 {
+		_, r, u := buishand(w)
+		if b.Statistic == BuishandU {
+			return u
+		}
+		return r
+	})
+
+	return idx, stat, nullConfidence(null, stat)
+}
+
+// buishand returns the split of window where the cumulative deviation from
+// the mean is largest, and the range and U statistics
+func buishand(window []float64) (idx int, r, u float64) {
+	n := len(window)
+	st := sampleStats(window)
+	if n < 2 || st.variance == 0 {
+		return 0, 0, 0
+	}
+
+	fn := float64(n)
+
+	// Buishand uses the population standard deviation
+	d := math.Sqrt(st.variance * (fn - 1) / fn)
+
+	var s, max, min, maxAbs float64
+	for k := 1; k < n; k++ {
+		s += window[k-1] - st.mean
+		if s > max {
+			max = s
+		}
+		if s < min {
+			min = s
+		}
+		if math.Abs(s) > maxAbs {
+			maxAbs, idx = math.Abs(s), k
+		}
+		u += (s / d) * (s / d)
+	}
+
+	r = (max - min) / d / math.Sqrt(fn)
+	u /= fn * (fn + 1)
+
+	return idx, r, u
+}
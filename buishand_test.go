@@ -0,0 +1,36 @@
+package change
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestBuishand(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	w := steps(rnd, 50, 0, 1.5)
+
+	for _, s := range []BuishandStatistic{BuishandRange, BuishandU} {
+		idx, stat, conf := Buishand{Statistic: s}.Locate(w)
+		if idx < 45 || idx > 55 || stat <= 0 || conf < 0.999 {
+			t.Errorf("Buishand(%d)=(%d, %f, %f), wanted change near 50", s, idx, stat, conf)
+		}
+
+		d := Detector{Homogeneity: Buishand{Statistic: s}, MinConfidence: 0.99}
+		if r := d.Check(steps(rnd, 100, 0)); r != nil {
+			t.Errorf("Check with Buishand(%d) found change %+v in stable window", s, r)
+		}
+	}
+}
+
+func TestBuishandCritical(t *testing.T) {
+	// the 95% critical value of R/sqrt(n) for n=100 from Buishand's (1982) table is 1.62
+	null := simulatedNull("buishand-range", 100, DefaultSimulations, func(w []float64) float64 {
+		_, r, _ := buishand(w)
+		return r
+	})
+
+	if c := quantile(null, 0.95); c < 1.55 || c > 1.7 {
+		t.Errorf("Buishand 95%% critical value for n=100=%f, wanted about 1.62", c)
+	}
+}
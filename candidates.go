@@ -0,0 +1,50 @@
+package change
+
+import "sort"
+
+// Candidates returns up to k of the best splits of window that are
+// significant at MinConfidence, in decreasing order of score.  Unlike Check,
+// which only tests the single best split, this finds the peaks of the split
+// scores, so a window containing more than one shift can report each of them.
+// Each candidate is tested on its own, as if it were the only change point in
+// the window, and candidates are at least MinSampleSize items apart so that a
+// single shift is not reported more than once.
+//
+// The detector's Homogeneity test is not used.
+func (d *Detector) Candidates(window []float64, k int) []*ChangePoint {
+	minSampleSize := d.minSampleSize()
+
+	scores := d.Scores(window)
+
+	idx := make([]int, 0, len(scores))
+	for i, s := range scores {
+		if s > 0 {
+			idx = append(idx, i)
+		}
+	}
+	sort.SliceStable(idx, func(a, b int) bool { return scores[idx[a]] > scores[idx[b]] })
+
+	var picked []int
+	var cps []*ChangePoint
+
+next:
+	for _, i := range idx {
+		if len(cps) == k {
+			break
+		}
+
+		for _, p := range picked {
+			if i-p < minSampleSize && p-i < minSampleSize {
+				continue next
+			}
+		}
+		picked = append(picked, i)
+
+		s := split{index: i, sb: scores[i], before: sampleStats(window[:i]), after: sampleStats(window[i:])}
+		if cp := d.test(window, s); cp != nil {
+			cps = append(cps, cp)
+		}
+	}
+
+	return cps
+}
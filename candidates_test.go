@@ -0,0 +1,45 @@
+package change
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestCandidates(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	w := steps(rnd, 100, 0, 3, 0)
+
+	d := New(30, 0.99)
+
+	cps := d.Candidates(w, 3)
+	if len(cps) < 2 {
+		t.Fatalf("Candidates()=%d change points, wanted at least 2", len(cps))
+	}
+
+	for _, want := range []int{100, 200} {
+		var found bool
+		for _, cp := range cps[:2] {
+			if cp.Index >= want-5 && cp.Index <= want+5 {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Candidates()[:2] missing change point %d", want)
+		}
+	}
+
+	for i := 1; i < len(cps); i++ {
+		if cps[i].Score > cps[i-1].Score {
+			t.Errorf("Candidates() not in decreasing order of score: %f > %f", cps[i].Score, cps[i-1].Score)
+		}
+	}
+
+	if cps := d.Candidates(w, 1); len(cps) != 1 {
+		t.Errorf("Candidates(k=1)=%d change points, wanted 1", len(cps))
+	}
+
+	if cps := d.Candidates(steps(rnd, 100, 0), 3); len(cps) != 0 {
+		t.Errorf("Candidates() for no change=%d change points, wanted 0", len(cps))
+	}
+}
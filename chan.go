@@ -0,0 +1,40 @@
+package change
+
+import "context"
+
+// Watch pushes the items received from in to r, and sends each change point
+// found on the returned channel.  The channel is closed once in is closed or
+// ctx is cancelled.  r must not be used by anything else until then.
+func (r *Rolling) Watch(ctx context.Context, in <-chan float64) <-chan *ChangePoint {
+	out := make(chan *ChangePoint)
+
+	go func() {
+		defer close(out)
+
+		for {
+			var v float64
+			var ok bool
+			select {
+			case v, ok = <-in:
+				if !ok {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+
+			cp := r.Push(v)
+			if cp == nil {
+				continue
+			}
+
+			select {
+			case out <- cp:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,41 @@
+package change
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+)
+
+func TestWatch(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	w := steps(rnd, 200, 0, 5)
+
+	in := make(chan float64)
+	go func() {
+		for _, v := range w {
+			in <- v
+		}
+		close(in)
+	}()
+
+	r := NewRolling(New(30, 0.99), 100)
+
+	var found bool
+	for cp := range r.Watch(context.Background(), in) {
+		if cp.Index == 200 {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Errorf("Watch() found no change at index 200")
+	}
+
+	// a cancelled context closes the output without waiting for items
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for cp := range NewRolling(New(30, 0.99), 100).Watch(ctx, make(chan float64)) {
+		t.Errorf("Watch() after cancel=%+v, wanted nothing", cp)
+	}
+}
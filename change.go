@@ -19,17 +19,24 @@ package change
 
 import (
 	"math"
-
-	"github.com/dgryski/go-onlinestats"
+	"math/rand"
+	"sort"
+	"time"
 )
 
-// Stats are some descriptive statistics for a block of items.  It implements the interface needed by the t-test method of onlinestats.
+// Stats are some descriptive statistics for a block of items.
 type Stats struct {
 	mean     float64
 	variance float64
 	n        int
 }
 
+// NewStats returns the statistics of a data set of n items with the given
+// mean and variance, such as those decoded from another format
+func NewStats(n int, mean, variance float64) Stats {
+	return Stats{mean: mean, variance: variance, n: n}
+}
+
 // Mean returns the mean of the data set
 func (s Stats) Mean() float64 { return s.mean }
 
@@ -42,6 +49,24 @@ func (s Stats) Len() int { return s.n }
 // Stddev returns the standard deviation of the sample
 func (s Stats) Stddev() float64 { return math.Sqrt(s.variance) }
 
+// sampleStats returns the statistics of xs
+func sampleStats(xs []float64) Stats {
+	var s Stats
+	var sqdiff float64
+	for _, x := range xs {
+		s.n++
+		delta := x - s.mean
+		s.mean += delta / float64(s.n)
+		sqdiff += delta * (x - s.mean)
+	}
+
+	if s.n > 1 {
+		s.variance = sqdiff / float64(s.n-1)
+	}
+
+	return s
+}
+
 // ChangePoint is a potential change point found by Check().
 type ChangePoint struct {
 	// Index is the offset into the data set of the suspected change point
@@ -50,46 +75,417 @@ type ChangePoint struct {
 	// Difference is the difference in distribution means found by the Student's t-test
 	Difference float64
 
-	// Confidence is the confidence returned by a Student's t-test
+	// Confidence is the confidence returned by a Student's t-test, or by the detector's Test or permutation test if one is set
 	Confidence float64
 
+	// Statistic is the test statistic: the t statistic for Welch's t-test, or the one returned by the detector's Test
+	Statistic float64
+
+	// DegreesOfFreedom is the Welch-Satterthwaite degrees of freedom of the
+	// t-test.  It is zero if the detector uses a different test.
+	DegreesOfFreedom float64
+
+	// Critical is the value of the t statistic the change point had to
+	// exceed, in absolute value, to be reported at MinConfidence.  It is zero
+	// if the detector uses a different test.
+	Critical float64
+
 	// Before is the statistics of the distribution before the change point
 	Before Stats
 
 	// After is the statistics of the distribution after the change point
 	After Stats
+
+	// Score is the split score of the change point: the between-class
+	// scatter, or the reduction in cost or score from the detector's Cost or
+	// Scorer.  It is zero if the detector uses a Homogeneity test.
+	Score float64
+
+	// Time is the time of the first item after the change point, if the
+	// items were pushed to a Stream or Rolling detector with PushAt
+	Time time.Time
+
+	// Kind is the kind of change, once it has been labelled by Classify
+	Kind Kind
 }
 
+// Significant reports whether a change point was found.  It is safe to call
+// on the nil ChangePoint returned when there is no significant change, so the
+// result of Check can be used directly:
+//
+//	if d.Check(window).Significant() {
+//		...
+//	}
+func (cp *ChangePoint) Significant() bool { return cp != nil }
+
 // DefaultMinSampleSize is the minimum sample size to consider from the window being checked
 const DefaultMinSampleSize = 30
 
+// Test is a two-sample significance test used to validate a suspected change point.
+type Test interface {
+	// Test returns the test statistic for the samples before and after the
+	// change point, and the confidence that they come from different
+	// distributions.
+	Test(before, after []float64) (statistic, confidence float64)
+}
+
 // Detector is a change detector.
 type Detector struct {
+	// MinSampleSize is the minimum number of items on either side of a change
+	// point.  It is also the minimum length of the segments found by the
+	// segmentation methods, such as Segment.
 	MinSampleSize int
+
+	// MinConfidence is the confidence a change point must exceed to be
+	// reported.  Any level may be used, such as 0.95 or 0.9999.
 	MinConfidence float64
+
+	// Test validates the change point.  If nil, Welch's t-test is used.
+	Test Test
+
+	// Cost scores the candidate splits of the window: the best split is the
+	// one which most reduces the total cost.  If nil, the between-class
+	// scatter is used, which is equivalent to L2.
+	Cost Cost
+
+	// Scorer scores the candidate splits of the window directly: the best
+	// split is the one with the highest score.  If set, it is used instead
+	// of Cost.
+	Scorer Scorer
+
+	// Homogeneity locates and tests the change point in a single step.  If
+	// set, it is used instead of the split scan and Test.
+	Homogeneity Homogeneity
+
+	// Permutations, if non-zero, replaces Test with a permutation test: the
+	// window is shuffled this many times, and the confidence is the fraction
+	// of shuffles whose best split scores lower than the real one.  This makes
+	// no assumption about the distribution of the data, at the cost of
+	// scanning the window once per shuffle.  The confidence is at most
+	// Permutations/(Permutations+1), and the reported statistic is the split
+	// score.
+	Permutations int
+
+	// BlockBootstrap, if non-zero and Permutations is not, replaces Test with
+	// a moving-block bootstrap test using this many resamples.  The window is
+	// resampled in blocks of BlockLength consecutive items, which keeps the
+	// serial dependence of the items within each block, and the confidence
+	// is the fraction of resamples whose best split scores lower than the
+	// real one.  Unlike the t-test and the permutation test, it doesn't
+	// assume the items are independent.  The reported statistic is the split
+	// score.
+	BlockBootstrap int
+
+	// BlockLength is the length of the blocks of the block bootstrap.  If
+	// zero, the cube root of the window's length is used.
+	BlockLength int
+
+	// Rand is the source of the shuffles for the permutation test, and the
+	// resamples of the block bootstrap.  If nil, a fixed seed is used so
+	// results are reproducible.
+	Rand *rand.Rand
+
+	// Transform preprocesses windows before they are checked, such as Log
+	// or a Chain of transforms.  If it drops items from the start of the
+	// window, change point indices are offset to match.  It is used by Check
+	// and CheckBuf.
+	Transform Transform
+
+	// Outliers clips or drops outlying items from windows, after Transform
+	// and before the Missing policy.  It is used by Check and CheckBuf.
+	Outliers Outliers
+
+	// Missing is the policy for NaN and infinite values in windows.
+	Missing Missing
+
+	// MaxChangePoints is the largest number of change points the
+	// segmentation methods return; if they find more, those with the highest
+	// scores are kept.  If zero, there is no limit.
+	MaxChangePoints int
+
+	// MinDifference is the smallest absolute difference in means a change
+	// point must have to be reported, so that changes which are significant
+	// but too small to matter are suppressed.
+	MinDifference float64
+
+	// MinPercentChange is the smallest absolute change in the mean, as a
+	// percentage of the mean before the change point, that a change point
+	// must have to be reported.
+	MinPercentChange float64
+
+	// Prune skips candidate splits which can't score higher than the best
+	// split found, using a cheap bound on the between-class scatter of blocks
+	// of splits.  It finds the same split, but is faster on long windows where
+	// most of the splits score poorly.  It is only used with the default split
+	// score, not by Scores, and takes precedence over Workers.
+	Prune bool
+
+	// Workers is the number of goroutines used to scan the candidate splits
+	// of large windows, such as runtime.NumCPU().  If zero or one, the scan is
+	// sequential.  With a Cost, each goroutine fits it to the window
+	// separately; a Scorer must be safe for concurrent use.
+	Workers int
+
+	// Direction restricts the detector to changes in the mean in one
+	// direction.  Only splits in that direction are considered, so a smaller
+	// change in the wanted direction is still found when there is a larger one
+	// in the other, and Welch's t-test is made one-sided, which gives it more
+	// power.
+	Direction Direction
+}
+
+// Direction is the direction of a change in the mean
+type Direction int
+
+const (
+	// AnyDirection is a change in either direction
+	AnyDirection Direction = iota
+
+	// Increase is a change to a higher mean
+	Increase
+
+	// Decrease is a change to a lower mean
+	Decrease
+)
+
+func (d Direction) String() string {
+	switch d {
+	case AnyDirection:
+		return "any"
+	case Increase:
+		return "increase"
+	case Decrease:
+		return "decrease"
+	}
+	return "unknown"
+}
+
+// Homogeneity is a classical homogeneity test for a single change point, such
+// as Pettitt's test, which both locates the change point and tests its
+// significance.
+type Homogeneity interface {
+	// Locate returns the offset in window of the first item after the most
+	// likely change point, the test statistic, and the confidence that there
+	// is a change.
+	Locate(window []float64) (index int, statistic, confidence float64)
+}
+
+// Scorer scores a candidate split of a window into the items before and after it.
+type Scorer interface {
+	// Score returns how dissimilar before and after are.  Scores must not be negative.
+	Score(before, after []float64) float64
+}
+
+// New returns a detector which can be reused to check many windows with the same configuration
+func New(minSampleSize int, minConfidence float64) *Detector {
+	return &Detector{
+		MinSampleSize: minSampleSize,
+		MinConfidence: minConfidence,
+	}
+}
+
+// minSampleSize returns MinSampleSize, or the default if it is not set
+func (d *Detector) minSampleSize() int {
+	if d.MinSampleSize == 0 {
+		return DefaultMinSampleSize
+	}
+	return d.MinSampleSize
 }
 
-// Check returns the index of a potential change point
+// Check returns the most likely change point in window, or nil if it is not
+// significant at MinConfidence
 func (d *Detector) Check(window []float64) *ChangePoint {
+	return d.CheckBuf(window, nil)
+}
 
-	n := len(window)
+// CheckBuf is Check, but uses the caller's scratch buffers for its working
+// space, so that checking many windows doesn't allocate.  Only a change
+// point that is found is allocated.  Check itself uses pooled buffers, which
+// is nearly as cheap, but CheckBuf avoids any contention on the pool.
+func (d *Detector) CheckBuf(window []float64, scratch *Scratch) *ChangePoint {
+	p := d.prepare(window)
+
+	cp := d.check(p.series, scratch)
+	if cp != nil {
+		cp.Index = p.index(cp.Index)
+	}
+
+	return cp
+}
+
+// prepared is a window after the detector's Transform, Outliers and Missing
+// policy, as it is checked
+type prepared struct {
+	series []float64
+
+	// dropped is the number of items the Transform dropped from the start
+	// of the window
+	dropped int
+
+	// offsets[i] is the index in the transformed window of series[i], or
+	// nil if it is i
+	offsets []int
+}
+
+// prepare applies the detector's Transform, Outliers and Missing policy to window
+func (d *Detector) prepare(window []float64) prepared {
+	var dropped int
+	if d.Transform != nil {
+		transformed := d.Transform.Apply(window)
+		dropped = len(window) - len(transformed)
+		window = transformed
+	}
+
+	policy := d.Missing
+	if d.Outliers != (Outliers{}) {
+		window = d.Outliers.apply(window)
+		if d.Outliers.Drop > 0 && policy == MissingReject {
+			policy = MissingSkip
+		}
+	}
+
+	cleaned, offsets := policy.clean(window)
+	return prepared{series: cleaned, dropped: dropped, offsets: offsets}
+}
+
+// index returns the offset in the original window of series[i].  If i is
+// len(series), it is the offset just after the last item.
+func (p prepared) index(i int) int {
+	if i > 0 && i == len(p.series) {
+		return p.index(i-1) + 1
+	}
+	if p.offsets != nil {
+		i = p.offsets[i]
+	}
+	return i + p.dropped
+}
+
+// split returns the index in series of the first item at or after offset idx
+// of the original window, so that series[:split(idx)] are the items before it
+func (p prepared) split(idx int) int {
+	idx -= p.dropped
+	if p.offsets != nil {
+		return sort.SearchInts(p.offsets, idx)
+	}
+	switch {
+	case idx < 0:
+		return 0
+	case idx > len(p.series):
+		return len(p.series)
+	}
+	return idx
+}
+
+func (d *Detector) check(window []float64, scratch *Scratch) *ChangePoint {
+	if d.Homogeneity != nil {
+		return d.homogeneity(window)
+	}
+	return d.test(window, d.scanScores(window, nil, scratch))
+}
+
+// homogeneity checks window with the detector's homogeneity test
+func (d *Detector) homogeneity(window []float64) *ChangePoint {
+	idx, stat, conf := d.Homogeneity.Locate(window)
+
+	// not above our threshold
+	m := d.minSampleSize()
+	if idx < m || idx > len(window)-m || conf <= d.MinConfidence {
+		return nil
+	}
+
+	before, after := sampleStats(window[:idx]), sampleStats(window[idx:])
+
+	cp := &ChangePoint{
+		Index:      idx,
+		Difference: after.Mean() - before.Mean(),
+		Confidence: conf,
+		Statistic:  stat,
+		Before:     before,
+		After:      after,
+	}
+
+	if !d.reportable(cp) {
+		return nil
+	}
+
+	return cp
+}
+
+// reportable reports whether the change point is at least the detector's
+// minimum size and in its direction
+func (d *Detector) reportable(cp *ChangePoint) bool {
+	if !d.Direction.matches(cp.Difference) {
+		return false
+	}
+
+	diff := math.Abs(cp.Difference)
+	return diff >= d.MinDifference && diff >= d.MinPercentChange/100*math.Abs(cp.Before.Mean())
+}
+
+// matches reports whether a change in the mean of diff is in the direction
+func (d Direction) matches(diff float64) bool {
+	switch d {
+	case Increase:
+		return diff > 0
+	case Decrease:
+		return diff < 0
+	}
+	return true
+}
+
+// split is a candidate change point found by scan
+type split struct {
+	index         int
+	sb            float64
+	before, after Stats
+}
+
+// Scores returns the split score of each index of window: scores[l] is the
+// score of splitting window into window[:l] and window[l:], as used to choose
+// the change point.  Indexes too close to either end of the window to be a
+// change point, or not in the detector's Direction, have a score of 0.  This
+// is useful for plotting, or for judging how prominent the best split is.
+func (d *Detector) Scores(window []float64) []float64 {
+	scores := make([]float64, len(window))
+	d.scanScores(window, scores, nil)
+	return scores
+}
+
+// scan returns the split of window with the largest between-class scatter
+func (d *Detector) scan(window []float64) split {
+	return d.scanScores(window, nil, nil)
+}
+
+// scanScores is scan, which also records the score of each split in scores if
+// it is not nil, and uses the scratch buffers if they are not nil
+func (d *Detector) scanScores(window []float64, scores []float64, scratch *Scratch) split {
+	if scratch == nil {
+		scratch = scratchPool.Get().(*Scratch)
+		defer scratchPool.Put(scratch)
+	}
 
 	// The paper provides recursive formulas for computing the means and
 	// standard deviations as we slide along the window.  This
 	// implementation uses alternate math based on cumulative sums.
 
-	// cumsum contains the cumulative sum of all elements <= i
-	// cumsumsq contains the cumulative sum of squares of all elements <= i
-	// TODO(dgryski): move this to a move numerically stable algorithm
-	cumsum := make([]float64, n)
-	cumsumsq := make([]float64, n)
+	// cumsum contains the cumulative sum of all elements <= i, less the
+	// origin.  The between-class scatter only depends on the difference in
+	// means, so shifting the items doesn't change it, but keeps the sums
+	// small.  The sums are also compensated, so they stay accurate for long
+	// windows.
+	shift := origin(window)
+	cumsum := scratch.floats(len(window))
+	cumsumShifted(cumsum, window, shift)
 
-	var sum, sumsq float64
-	for i, v := range window {
-		sum += v
-		sumsq += v * v
-		cumsum[i] = sum
-		cumsumsq[i] = sumsq
+	return d.scanSums(window, cumsum, 0, scores)
+}
+
+// scanSums is scan, given the cumulative sums of window: the sum of
+// window[:i+1], up to a constant shift, is cumsum[i]-base
+func (d *Detector) scanSums(window, cumsum []float64, base float64, scores []float64) split {
+	n := len(window)
+	if n == 0 {
+		return split{}
 	}
 
 	// sb is our between-class scatter, the degree of dissimilarity of the
@@ -103,45 +499,114 @@ func (d *Detector) Check(window []float64) *ChangePoint {
 	// finding the point that minimizes the ratio sw/sb.  However, it then
 	// proves that this is equivalent to maximizing sb.  The calculation of
 	// sb depends only on the means of the two samples, and not of the
-	// variances.  However, we calculate the variances so that we can pass
-	// them to the T test later on.
+	// variances.  The variances for the T test are calculated afterwards,
+	// for the best split only, with Welford's algorithm, which doesn't suffer
+	// from the cancellation of the sum of squares formula.
+
+	minSampleSize := d.minSampleSize()
+	lo, hi := minSampleSize, n-minSampleSize+1
+
+	switch workers := d.Workers; {
+	case d.Prune && scores == nil && d.Scorer == nil && d.Cost == nil:
+		maxsbIdx, maxsb = d.scanPruned(window, cumsum, base, lo, hi)
+	case workers > 1 && hi-lo >= parallelMinSplits:
+		maxsbIdx, maxsb = d.scanParallel(window, cumsum, base, lo, hi, scores, workers)
+	default:
+		maxsbIdx, maxsb = d.scanRange(window, cumsum, base, lo, hi, scores)
+	}
 
-	var before, after Stats
+	if maxsbIdx == 0 {
+		return split{}
+	}
 
-	// sane default
-	minSampleSize := d.MinSampleSize
-	if minSampleSize == 0 {
-		minSampleSize = DefaultMinSampleSize
+	return split{
+		index:  maxsbIdx,
+		sb:     maxsb,
+		before: sampleStats(window[:maxsbIdx]),
+		after:  sampleStats(window[maxsbIdx:]),
 	}
+}
+
+// scanRange returns the best split of window at an index in [lo, hi), and its
+// score.  The index is 0 if there is no split with a positive score.
+func (d *Detector) scanRange(window, cumsum []float64, base float64, lo, hi int, scores []float64) (int, float64) {
+	n := len(window)
+	sum := cumsum[n-1] - base
 
-	for l := minSampleSize; l < (n - minSampleSize + 1); l++ {
+	var maxsb float64
+	var maxsbIdx int
+
+	var cost func(i, j int) float64
+	var total float64
+	if d.Cost != nil && d.Scorer == nil {
+		cost = d.Cost.Fit(window)
+		total = cost(0, n)
+	}
+
+	for l := lo; l < hi; l++ {
 		lidx := l - 1
 		n1 := float64(l)
-		mean1 := cumsum[lidx] / n1
+		sum1 := cumsum[lidx] - base
+		mean1 := sum1 / n1
 
 		n2 := float64(n - l)
-		sum2 := (sum - cumsum[lidx])
+		sum2 := (sum - sum1)
 		mean2 := sum2 / n2
 
+		if !d.Direction.matches(mean2 - mean1) {
+			continue
+		}
+
 		sb := ((n1 * n2) / (n1 + n2)) * (mean1 - mean2) * (mean1 - mean2)
+		if d.Scorer != nil {
+			sb = d.Scorer.Score(window[:l], window[l:])
+		} else if cost != nil {
+			sb = total - cost(0, l) - cost(l, n)
+		}
+
+		if scores != nil {
+			scores[l] = sb
+		}
+
 		if maxsb < sb {
 			maxsb = sb
 			maxsbIdx = l
-
-			// The variances are calculated only if needed to
-			// reduce the math in the main loop
-			var1 := (cumsumsq[lidx] - (cumsum[lidx]*cumsum[lidx])/(n1)) / (n1 - 1)
-			var2 := ((sumsq - cumsumsq[lidx]) - (sum2*sum2)/(n2)) / (n2 - 1)
-
-			before.mean, before.variance, before.n = mean1, var1, l
-			after.mean, after.variance, after.n = mean2, var2, n-l
 		}
 	}
 
-	var conf float64
+	return maxsbIdx, maxsb
+}
+
+// test validates a candidate split of window, returning nil if it is not significant
+func (d *Detector) test(window []float64, s split) *ChangePoint {
+	before, after := s.before, s.after
+
+	var stat, df, conf float64
 	if before.n > 0 {
 		// we found a difference
-		conf = onlinestats.Welch(before, after)
+		rnd := d.Rand
+		if rnd == nil && (d.Permutations > 0 || d.BlockBootstrap > 0) {
+			rnd = rand.New(rand.NewSource(1))
+		}
+
+		switch {
+		case d.Permutations > 0:
+			stat, conf = s.sb, d.permutationTest(window, s.sb, d.Permutations, rnd)
+		case d.BlockBootstrap > 0:
+			stat, conf = s.sb, d.blockBootstrapTest(window, s.sb, d.BlockBootstrap, rnd)
+		case d.Test != nil:
+			stat, conf = d.Test.Test(window[:s.index], window[s.index:])
+		default:
+			stat, df, conf = welch(before, after)
+
+			// welch's t is positive for a decrease
+			switch d.Direction {
+			case Increase:
+				conf = studentTCDF(-stat, df)
+			case Decrease:
+				conf = studentTCDF(stat, df)
+			}
+		}
 	}
 
 	// not above our threshold
@@ -150,18 +615,38 @@ func (d *Detector) Check(window []float64) *ChangePoint {
 	}
 
 	cp := &ChangePoint{
-		Index:      maxsbIdx,
+		Index:      s.index,
 		Difference: after.Mean() - before.Mean(),
 		Confidence: conf,
+		Statistic:  stat,
 		Before:     before,
 		After:      after,
+		Score:      s.sb,
+	}
+
+	if df > 0 {
+		cp.DegreesOfFreedom = df
+		cp.Critical = studentTQuantile(1-(1-d.MinConfidence)/2, df)
+		if d.Direction != AnyDirection {
+			cp.Critical = studentTQuantile(d.MinConfidence, df)
+		}
+	}
+
+	if !d.reportable(cp) {
+		return nil
 	}
 
 	return cp
 }
 
-// Stream monitors a stream of floats for changes
+// Stream monitors a stream of floats for changes.  It keeps a bounded sliding
+// window of the most recent items and runs the detector each time a block of
+// new items has been added.
 type Stream struct {
+	// Suppression holds back change points during a warm-up, and after each
+	// one reported
+	Suppression
+
 	windowSize int
 	blockSize  int
 
@@ -172,7 +657,13 @@ type Stream struct {
 	buffer []float64
 	bufidx int
 
-	detector *Detector
+	// times and tbuffer hold the times of the items in data and buffer,
+	// once PushAt has been called
+	times   []time.Time
+	tbuffer []time.Time
+
+	detector   *Detector
+	suppressor suppressor
 }
 
 // NewStream constructs a new stream detector
@@ -183,10 +674,7 @@ func NewStream(windowSize int, minSample int, blockSize int, confidence float64)
 		data:       make([]float64, windowSize),
 		buffer:     make([]float64, blockSize),
 
-		detector: &Detector{
-			MinSampleSize: minSample,
-			MinConfidence: confidence,
-		},
+		detector: New(minSample, confidence),
 	}
 }
 
@@ -202,13 +690,45 @@ func (s *Stream) Push(item float64) *ChangePoint {
 
 	copy(s.data[0:], s.data[s.blockSize:])
 	copy(s.data[s.windowSize-s.blockSize:], s.buffer)
+	if s.times != nil {
+		copy(s.times[0:], s.times[s.blockSize:])
+		copy(s.times[s.windowSize-s.blockSize:], s.tbuffer)
+	}
 	s.bufidx = 0
 
 	if s.items < s.windowSize {
 		return nil
 	}
 
-	return s.detector.Check(s.data)
+	cp := s.detector.Check(s.data)
+	if cp == nil {
+		return nil
+	}
+
+	var latest time.Time
+	if s.times != nil {
+		cp.Time = s.times[cp.Index]
+		latest = s.times[s.windowSize-1]
+	}
+
+	if !s.suppressor.allow(s.Suppression, s.items, latest) {
+		return nil
+	}
+
+	return cp
+}
+
+// PushAt is Push for an item with a timestamp.  The change point's Time is
+// the time of the first item after the change.
+func (s *Stream) PushAt(t time.Time, item float64) *ChangePoint {
+	if s.times == nil {
+		s.times = make([]time.Time, s.windowSize)
+		s.tbuffer = make([]time.Time, s.blockSize)
+	}
+
+	s.tbuffer[s.bufidx] = t
+	s.suppressor.observe(t)
+	return s.Push(item)
 }
 
 // Window returns the current data window.  This should be treated as read-only
@@ -1,6 +1,12 @@
 package change
 
-import "testing"
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+)
 
 func TestDetectChange(t *testing.T) {
 
@@ -38,3 +44,308 @@ func TestDetectChange(t *testing.T) {
 		}
 	}
 }
+
+func TestStream(t *testing.T) {
+
+	s := NewStream(40, 10, 5, 0.95)
+
+	var found []int
+	for i := 0; i < 100; i++ {
+		v := 1.0
+		if i >= 60 {
+			v = 2.0
+		}
+		// a little noise so the variances are non-zero
+		v += float64(i%3) * 0.01
+
+		if r := s.Push(v); r != nil {
+			found = append(found, i-len(s.Window())+1+r.Index)
+		}
+	}
+
+	// while the change is near the edge of the window it can only be
+	// reported as close as MinSampleSize allows, but once it is well inside
+	// the window the exact location should be found
+	var ok bool
+	for _, idx := range found {
+		if idx == 60 {
+			ok = true
+		}
+	}
+
+	if !ok {
+		t.Errorf("Stream change points=%v, wanted 60", found)
+	}
+}
+
+func TestConfidenceLevel(t *testing.T) {
+
+	// a shift that is significant at 95% but not at 99.99%
+	w := []float64{1, 2, 1, 2, 1, 2, 1, 2, 1, 2, 2, 3, 2, 3, 2, 1, 2, 3, 2, 3}
+
+	if r := New(5, 0.95).Check(w); r == nil {
+		t.Errorf("Check at 0.95 found no change point")
+	}
+
+	if r := New(5, 0.9999).Check(w); r != nil {
+		t.Errorf("Check at 0.9999 found change point with confidence=%f", r.Confidence)
+	}
+}
+
+func TestPermutations(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	d := Detector{MinConfidence: 0.99, Permutations: 199}
+
+	if r := d.Check(steps(rnd, 100, 0, 1)); r == nil || r.Index < 95 || r.Index > 105 {
+		t.Errorf("Check with permutations=%+v, wanted index 100", r)
+	} else if r.Confidence != 0.995 {
+		t.Errorf("Check with permutations confidence=%f, wanted 0.995", r.Confidence)
+	}
+
+	if r := d.Check(steps(rnd, 200, 0)); r != nil {
+		t.Errorf("Check with permutations found change point with confidence=%f", r.Confidence)
+	}
+}
+
+func TestCritical(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	w := steps(rnd, 50, 0, 1)
+
+	r := New(30, 0.95).Check(w)
+	if r == nil {
+		t.Fatalf("Check found no change point")
+	}
+
+	if _, df, _ := welch(r.Before, r.After); r.DegreesOfFreedom != df {
+		t.Errorf("Check DegreesOfFreedom=%f, wanted %f", r.DegreesOfFreedom, df)
+	}
+
+	// with this many degrees of freedom the critical value is close to the normal's 1.96
+	if r.Critical < 1.96 || r.Critical > 2.0 {
+		t.Errorf("Check Critical=%f, wanted about 1.98", r.Critical)
+	}
+
+	if math.Abs(r.Statistic) <= r.Critical {
+		t.Errorf("Check Statistic=%f, wanted above Critical=%f", r.Statistic, r.Critical)
+	}
+
+	d := Detector{MinSampleSize: 30, MinConfidence: 0.95, Test: MannWhitney{}}
+	if r := d.Check(w); r == nil || r.DegreesOfFreedom != 0 || r.Critical != 0 {
+		t.Errorf("Check with MannWhitney=%+v, wanted no degrees of freedom or critical value", r)
+	}
+}
+
+func TestDirection(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	up := steps(rnd, 50, 0, 1)
+	down := steps(rnd, 50, 0, -1)
+
+	two := New(30, 0.9).Check(up)
+	if two == nil {
+		t.Fatalf("Check found no change point")
+	}
+
+	one := &Detector{MinSampleSize: 30, MinConfidence: 0.9, Direction: Increase}
+	r := one.Check(up)
+	if r == nil {
+		t.Fatalf("Check for an increase found no change point")
+	}
+
+	// the one-sided test has half the p-value
+	if got, want := 1-r.Confidence, (1-two.Confidence)/2; math.Abs(got-want) > 1e-12 {
+		t.Errorf("Check for an increase p=%f, wanted %f", got, want)
+	}
+
+	if r.Critical >= two.Critical {
+		t.Errorf("Check for an increase Critical=%f, wanted less than %f", r.Critical, two.Critical)
+	}
+
+	if r := one.Check(down); r != nil {
+		t.Errorf("Check for an increase found a decrease with confidence=%f", r.Confidence)
+	}
+
+	one.Direction = Decrease
+	if r := one.Check(down); r == nil {
+		t.Errorf("Check for a decrease found no change point")
+	}
+}
+
+func TestMinDifference(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// a highly significant change of 2, or 2% of the mean
+	w := steps(rnd, 200, 100, 102)
+
+	var tests = []struct {
+		d     Detector
+		found bool
+	}{
+		{Detector{MinConfidence: 0.99}, true},
+		{Detector{MinConfidence: 0.99, MinDifference: 1.5}, true},
+		{Detector{MinConfidence: 0.99, MinDifference: 2.5}, false},
+		{Detector{MinConfidence: 0.99, MinPercentChange: 1.5}, true},
+		{Detector{MinConfidence: 0.99, MinPercentChange: 2.5}, false},
+		{Detector{MinConfidence: 0.99, Homogeneity: Pettitt{}, MinDifference: 2.5}, false},
+	}
+
+	for _, tt := range tests {
+		if r := tt.d.Check(w); (r != nil) != tt.found {
+			t.Errorf("Check(%+v)=%+v, wanted found=%v", tt.d, r, tt.found)
+		}
+	}
+}
+
+func TestDirectionScan(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// an increase followed by a larger decrease, so that overall the increase
+	// only raises the mean from 0 to 1
+	w := steps(rnd, 100, 0, 3, -1)
+
+	var tests = []struct {
+		d   Detector
+		idx int
+	}{
+		{Detector{MinConfidence: 0.99}, 200},
+		{Detector{MinConfidence: 0.99, Direction: Decrease}, 200},
+		{Detector{MinConfidence: 0.99, Direction: Increase}, 100},
+		{Detector{MinConfidence: 0.99, Direction: Increase, Test: MannWhitney{}}, 100},
+	}
+
+	for _, tt := range tests {
+		r := tt.d.Check(w)
+		if r == nil || r.Index < tt.idx-5 || r.Index > tt.idx+5 {
+			t.Errorf("Check(%v)=%+v, wanted index %d", tt.d.Direction, r, tt.idx)
+		}
+	}
+
+	// a homogeneity test cannot be restricted, but its result is filtered
+	d := Detector{MinConfidence: 0.99, Homogeneity: Pettitt{}, Direction: Increase}
+	if r := d.Check(steps(rnd, 100, 0, -5)); r != nil {
+		t.Errorf("Check(Pettitt, increase) found a decrease=%+v", r)
+	}
+}
+
+func TestSignificant(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	d := New(30, 0.99)
+
+	if d.Check(steps(rnd, 100, 0)).Significant() {
+		t.Errorf("Significant()=true for no change")
+	}
+
+	if !d.Check(steps(rnd, 100, 0, 2)).Significant() {
+		t.Errorf("Significant()=false for a change")
+	}
+}
+
+func TestScores(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	w := steps(rnd, 100, 0, 2)
+
+	d := New(30, 0.99)
+	r := d.Check(w)
+	if r == nil {
+		t.Fatalf("Check found no change point")
+	}
+
+	scores := d.Scores(w)
+	if len(scores) != len(w) {
+		t.Fatalf("len(Scores())=%d, wanted %d", len(scores), len(w))
+	}
+
+	var best int
+	for i, s := range scores {
+		if s > scores[best] {
+			best = i
+		}
+		if (i < 30 || i > len(w)-30) && s != 0 {
+			t.Errorf("Scores()[%d]=%f, wanted 0 too close to the end of the window", i, s)
+		}
+	}
+
+	if best != r.Index || scores[best] != r.Score {
+		t.Errorf("Scores() peak=%d score=%f, wanted %d score=%f", best, scores[best], r.Index, r.Score)
+	}
+}
+
+func TestLargeOffset(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// unit-variance noise around a unix timestamp sized mean, whose variance
+	// is lost entirely by the sum of squares formula
+	w := steps(rnd, 100, 1.7e9, 1.7e9+2)
+
+	r := New(30, 0.99).Check(w)
+	if r == nil || r.Index < 95 || r.Index > 105 {
+		t.Fatalf("Check()=%+v, wanted index 100", r)
+	}
+
+	for _, s := range []Stats{r.Before, r.After} {
+		if s.Var() < 0.5 || s.Var() > 2 {
+			t.Errorf("Check() variance=%f, wanted about 1", s.Var())
+		}
+	}
+
+	cost := Normal{}.Fit(w)
+	if c := cost(0, 100); c < -50 || c > 50 {
+		t.Errorf("Normal cost=%f, wanted about 0 for unit variance", c)
+	}
+}
+
+func TestStreamPushAt(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	w := steps(rnd, 200, 0, 5)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s := NewStream(100, 30, 10, 0.99)
+
+	var found bool
+	for i, v := range w {
+		r := s.PushAt(start.Add(time.Duration(i)*time.Minute), v)
+		if r == nil {
+			continue
+		}
+
+		idx := i - len(s.Window()) + 1 + r.Index
+		if want := start.Add(time.Duration(idx) * time.Minute); !r.Time.Equal(want) {
+			t.Errorf("PushAt() Time=%v for item %d, wanted %v", r.Time, idx, want)
+		}
+		if idx == 200 {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Errorf("PushAt() found no change at item 200")
+	}
+}
+
+func TestPrepared(t *testing.T) {
+	nan := math.NaN()
+	w := []float64{1, 2, nan, 4, nan, 6, 7}
+
+	// Diff drops the first item, and MissingSkip the differences with a NaN
+	d := Detector{Transform: Diff{}, Missing: MissingSkip}
+	p := d.prepare(w)
+
+	if want := []float64{1, 1}; !reflect.DeepEqual(p.series, want) {
+		t.Fatalf("prepare()=%v, wanted %v", p.series, want)
+	}
+	for i, want := range []int{1, 6} {
+		if got := p.index(i); got != want {
+			t.Errorf("index(%d)=%d, wanted %d", i, got, want)
+		}
+	}
+	for idx, want := range []int{0, 0, 1, 1, 1, 1, 1, 2} {
+		if got := p.split(idx); got != want {
+			t.Errorf("split(%d)=%d, wanted %d", idx, got, want)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+// Package changealert is a change.Sink which raises Prometheus Alertmanager
+// alerts for change events, so that they are routed, silenced and
+// deduplicated like any other alert.
+package changealert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dgryski/go-change"
+)
+
+// Alerter posts an alert for each change event to the v2 API of an
+// Alertmanager.
+//
+// The alert's labels are alertname, the labels of the event's key if it was
+// made by change.LabelKey, or else a label "key" holding it, and Labels.
+// Its annotations are a summary, and the difference, confidence and means
+// either side of the change point.  It starts at the time of the change
+// point, or when it is posted if the change point has none.
+type Alerter struct {
+	// URL is the address of the Alertmanager, such as http://localhost:9093
+	URL string
+
+	// AlertName is the alertname label.  If empty, ChangeDetected is used.
+	AlertName string
+
+	// Labels are added to the labels of each alert, such as a severity
+	Labels map[string]string
+
+	// GeneratorURL links back to the source of the alerts
+	GeneratorURL string
+
+	// Duration, if set, ends each alert that long after it starts.  By
+	// default the Alertmanager resolves alerts which are not posted again
+	// after its resolve_timeout.
+	Duration time.Duration
+
+	// HTTPClient makes the requests.  If nil, http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+var _ change.Sink = (*Alerter)(nil)
+
+// Alert is an alert in the format of the Alertmanager v2 API
+type Alert struct {
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       *time.Time        `json:"endsAt,omitempty"`
+	GeneratorURL string            `json:"generatorURL,omitempty"`
+}
+
+// Alert returns the alert for ev
+func (a *Alerter) Alert(ev change.Event) Alert {
+	cp := ev.ChangePoint
+
+	labels := make(map[string]string)
+	if kl, err := change.ParseLabelKey(ev.Key); err == nil {
+		for k, v := range kl {
+			labels[k] = v
+		}
+	} else if ev.Key != "" {
+		labels["key"] = ev.Key
+	}
+	for k, v := range a.Labels {
+		labels[k] = v
+	}
+	labels["alertname"] = a.AlertName
+	if a.AlertName == "" {
+		labels["alertname"] = "ChangeDetected"
+	}
+
+	ftoa := func(v float64) string { return strconv.FormatFloat(v, 'g', 6, 64) }
+
+	summary := fmt.Sprintf("mean changed from %s to %s", ftoa(cp.Before.Mean()), ftoa(cp.After.Mean()))
+	if ev.Key != "" {
+		summary = ev.Key + ": " + summary
+	}
+
+	alert := Alert{
+		Labels: labels,
+		Annotations: map[string]string{
+			"summary":     summary,
+			"difference":  ftoa(cp.Difference),
+			"confidence":  ftoa(cp.Confidence),
+			"before_mean": ftoa(cp.Before.Mean()),
+			"after_mean":  ftoa(cp.After.Mean()),
+		},
+		StartsAt:     cp.Time,
+		GeneratorURL: a.GeneratorURL,
+	}
+	if cp.Kind != change.Unclassified {
+		alert.Annotations["kind"] = cp.Kind.String()
+	}
+
+	if alert.StartsAt.IsZero() {
+		alert.StartsAt = time.Now()
+	}
+	if a.Duration > 0 {
+		ends := alert.StartsAt.Add(a.Duration)
+		alert.EndsAt = &ends
+	}
+
+	return alert
+}
+
+// Notify posts the alert for ev
+func (a *Alerter) Notify(ctx context.Context, ev change.Event) error {
+	body, err := json.Marshal([]Alert{a.Alert(ev)})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(a.URL, "/")+"/api/v2/alerts", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	hc := a.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("changealert: %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+
+	return nil
+}
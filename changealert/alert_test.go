@@ -0,0 +1,88 @@
+package changealert
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dgryski/go-change"
+)
+
+func TestAlerter(t *testing.T) {
+	var got []map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v2/alerts" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	a := &Alerter{URL: srv.URL, Labels: map[string]string{"severity": "warning"}, Duration: time.Hour}
+
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	ev := change.Event{
+		Key: change.LabelKey(map[string]string{"host": "a", "metric": "cpu"}),
+		ChangePoint: &change.ChangePoint{
+			Difference: 0.5,
+			Confidence: 0.999,
+			Before:     change.NewStats(100, 0.25, 0.01),
+			After:      change.NewStats(100, 0.75, 0.01),
+			Time:       at,
+		},
+	}
+
+	if err := a.Notify(context.Background(), ev); err != nil {
+		t.Fatalf("Notify()=%v", err)
+	}
+
+	want := []map[string]interface{}{{
+		"labels": map[string]interface{}{"alertname": "ChangeDetected", "host": "a", "metric": "cpu", "severity": "warning"},
+		"annotations": map[string]interface{}{
+			"summary":     `{host="a",metric="cpu"}: mean changed from 0.25 to 0.75`,
+			"difference":  "0.5",
+			"confidence":  "0.999",
+			"before_mean": "0.25",
+			"after_mean":  "0.75",
+		},
+		"startsAt": "2024-03-01T12:00:00Z",
+		"endsAt":   "2024-03-01T13:00:00Z",
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Notify() posted %v, wanted %v", got, want)
+	}
+
+	a.URL = srv.URL + "/alertmanager"
+	if err := a.Notify(context.Background(), ev); err == nil {
+		t.Errorf("Notify(bad URL)=nil, wanted an error")
+	}
+}
+
+func TestAlertLabels(t *testing.T) {
+	a := &Alerter{AlertName: "LatencyShift"}
+	cp := &change.ChangePoint{Kind: change.Ramp}
+
+	var tests = []struct {
+		key  string
+		want map[string]string
+	}{
+		{"cpu", map[string]string{"alertname": "LatencyShift", "key": "cpu"}},
+		{"", map[string]string{"alertname": "LatencyShift"}},
+		{`{alertname="x",job="api"}`, map[string]string{"alertname": "LatencyShift", "job": "api"}},
+	}
+
+	for _, tt := range tests {
+		alert := a.Alert(change.Event{Key: tt.key, ChangePoint: cp})
+		if !reflect.DeepEqual(alert.Labels, tt.want) {
+			t.Errorf("Alert(%q).Labels=%v, wanted %v", tt.key, alert.Labels, tt.want)
+		}
+		if alert.Annotations["kind"] != "ramp" || alert.EndsAt != nil || alert.StartsAt.IsZero() {
+			t.Errorf("Alert(%q)=%+v, wanted kind ramp, starting now with no end", tt.key, alert)
+		}
+	}
+}
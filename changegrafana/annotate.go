@@ -0,0 +1,120 @@
+// Package changegrafana posts change points to Grafana as annotations, so
+// that they are marked on the graphs of a dashboard.
+package changegrafana
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dgryski/go-change"
+)
+
+// Annotator posts change events to the annotations API of a Grafana server.
+// An annotation is marked at the time of the change point, or the time it is
+// posted if the change point has none, and tagged with the event's key and
+// Tags.  With no DashboardUID, the annotations are organization wide.
+type Annotator struct {
+	// URL is the address of the server, such as http://localhost:3000
+	URL string
+
+	// Token is a service account token or API key, sent as a bearer token
+	Token string
+
+	// DashboardUID and PanelID are the dashboard and panel to annotate
+	DashboardUID string
+	PanelID      int
+
+	// Tags are added to the tags of each annotation
+	Tags []string
+
+	// HTTPClient makes the requests.  If nil, http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+var _ change.Sink = (*Annotator)(nil)
+
+type annotation struct {
+	DashboardUID string   `json:"dashboardUID,omitempty"`
+	PanelID      int      `json:"panelId,omitempty"`
+	Time         int64    `json:"time"`
+	Tags         []string `json:"tags"`
+	Text         string   `json:"text"`
+}
+
+// Notify posts an annotation for ev
+func (a *Annotator) Notify(ctx context.Context, ev change.Event) error {
+	cp := ev.ChangePoint
+
+	t := cp.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+
+	tags := append([]string{"change"}, a.Tags...)
+	if ev.Key != "" {
+		tags = append(tags, ev.Key)
+	}
+
+	body, err := json.Marshal(annotation{
+		DashboardUID: a.DashboardUID,
+		PanelID:      a.PanelID,
+		Time:         t.UnixMilli(),
+		Tags:         tags,
+		Text:         Text(ev),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(a.URL, "/")+"/api/annotations", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if a.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+a.Token)
+	}
+
+	hc := a.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("changegrafana: %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+
+	return nil
+}
+
+// Text returns the text of the annotation for ev, such as
+//
+//	change in cpu: mean 0.25 to 0.75 (difference 0.5, confidence 0.999)
+func Text(ev change.Event) string {
+	cp := ev.ChangePoint
+
+	var sb strings.Builder
+	sb.WriteString("change")
+	if ev.Key != "" {
+		sb.WriteString(" in " + ev.Key)
+	}
+	fmt.Fprintf(&sb, ": mean %.4g to %.4g (difference %.4g, confidence %.4g)", cp.Before.Mean(), cp.After.Mean(), cp.Difference, cp.Confidence)
+	if cp.Kind != change.Unclassified {
+		sb.WriteString(", " + cp.Kind.String())
+	}
+
+	return sb.String()
+}
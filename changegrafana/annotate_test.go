@@ -0,0 +1,63 @@
+package changegrafana
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dgryski/go-change"
+)
+
+func TestAnnotator(t *testing.T) {
+	var got annotation
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/annotations" {
+			http.Error(w, `{"message":"not found"}`, http.StatusNotFound)
+			return
+		}
+		auth = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"id":1,"message":"Annotation added"}`))
+	}))
+	defer srv.Close()
+
+	a := &Annotator{URL: srv.URL, Token: "secret", DashboardUID: "abc", PanelID: 2, Tags: []string{"prod"}}
+
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	ev := change.Event{
+		Key: "cpu",
+		ChangePoint: &change.ChangePoint{
+			Difference: 0.5,
+			Confidence: 0.999,
+			Before:     change.NewStats(100, 0.25, 0.01),
+			After:      change.NewStats(100, 0.75, 0.01),
+			Time:       at,
+			Kind:       change.Step,
+		},
+	}
+
+	if err := a.Notify(context.Background(), ev); err != nil {
+		t.Fatalf("Notify()=%v", err)
+	}
+
+	want := annotation{
+		DashboardUID: "abc",
+		PanelID:      2,
+		Time:         at.UnixMilli(),
+		Tags:         []string{"change", "prod", "cpu"},
+		Text:         "change in cpu: mean 0.25 to 0.75 (difference 0.5, confidence 0.999), step",
+	}
+	if !reflect.DeepEqual(got, want) || auth != "Bearer secret" {
+		t.Errorf("Notify() posted %+v with %q, wanted %+v", got, auth, want)
+	}
+
+	a.URL = srv.URL + "/grafana"
+	if err := a.Notify(context.Background(), ev); err == nil {
+		t.Errorf("Notify(bad URL)=nil, wanted an error")
+	}
+}
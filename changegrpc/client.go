@@ -0,0 +1,84 @@
+package changegrpc
+
+import (
+	"context"
+
+	"github.com/dgryski/go-change"
+	"github.com/dgryski/go-change/changepb"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Client calls a remote Detector service
+type Client struct {
+	c changepb.DetectorClient
+
+	// MinSampleSize and MinConfidence are sent with each call to Detect, and
+	// override the server's detector if they are set
+	MinSampleSize int
+	MinConfidence float64
+}
+
+// NewClient returns a client using the connection cc
+func NewClient(cc grpc.ClientConnInterface) *Client {
+	return &Client{c: changepb.NewDetectorClient(cc)}
+}
+
+// Detect checks window for a change point on the server.  It returns nil if
+// there is none.
+func (c *Client) Detect(ctx context.Context, window []float64) (*change.ChangePoint, error) {
+	req := &changepb.DetectRequest{
+		Window: window,
+		Options: &changepb.Options{
+			MinSampleSize: int32(c.MinSampleSize),
+			MinConfidence: c.MinConfidence,
+		},
+	}
+
+	resp, err := c.c.Detect(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return FromProto(resp.ChangePoint), nil
+}
+
+// Stream is a stream of samples sent to the server's rolling detectors, and
+// of the events they find
+type Stream struct {
+	s changepb.Detector_StreamClient
+}
+
+// Stream opens a stream to the server, which lasts until ctx is cancelled or
+// CloseSend is called and the remaining events are received
+func (c *Client) Stream(ctx context.Context) (*Stream, error) {
+	s, err := c.c.Stream(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Stream{s: s}, nil
+}
+
+// Send sends a sample.  A zero Time is not sent.
+func (s *Stream) Send(sample change.Sample) error {
+	pb := &changepb.Sample{Key: sample.Key, Value: sample.Value}
+	if !sample.Time.IsZero() {
+		pb.Time = timestamppb.New(sample.Time)
+	}
+	return s.s.Send(pb)
+}
+
+// Recv receives the next event.  It returns io.EOF once the server has
+// finished the stream.
+func (s *Stream) Recv() (change.Event, error) {
+	pb, err := s.s.Recv()
+	if err != nil {
+		return change.Event{}, err
+	}
+	return change.Event{Key: pb.Key, ChangePoint: FromProto(pb.ChangePoint)}, nil
+}
+
+// CloseSend tells the server that no more samples will be sent
+func (s *Stream) CloseSend() error {
+	return s.s.CloseSend()
+}
@@ -0,0 +1,70 @@
+package changegrpc
+
+import (
+	"github.com/dgryski/go-change"
+	"github.com/dgryski/go-change/changepb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ToProto converts a change point to its protocol buffer message.  A nil
+// change point gives a nil message.
+func ToProto(cp *change.ChangePoint) *changepb.ChangePoint {
+	if cp == nil {
+		return nil
+	}
+
+	pb := &changepb.ChangePoint{
+		Index:            int64(cp.Index),
+		Difference:       cp.Difference,
+		Confidence:       cp.Confidence,
+		Statistic:        cp.Statistic,
+		DegreesOfFreedom: cp.DegreesOfFreedom,
+		Critical:         cp.Critical,
+		Before:           statsToProto(cp.Before),
+		After:            statsToProto(cp.After),
+		Score:            cp.Score,
+		// the enum lists the kinds in the same order as change.Kind
+		Kind: changepb.Kind(cp.Kind),
+	}
+
+	if !cp.Time.IsZero() {
+		pb.Time = timestamppb.New(cp.Time)
+	}
+
+	return pb
+}
+
+// FromProto converts a protocol buffer message to a change point.  A nil
+// message gives a nil change point.
+func FromProto(pb *changepb.ChangePoint) *change.ChangePoint {
+	if pb == nil {
+		return nil
+	}
+
+	cp := &change.ChangePoint{
+		Index:            int(pb.Index),
+		Difference:       pb.Difference,
+		Confidence:       pb.Confidence,
+		Statistic:        pb.Statistic,
+		DegreesOfFreedom: pb.DegreesOfFreedom,
+		Critical:         pb.Critical,
+		Before:           statsFromProto(pb.Before),
+		After:            statsFromProto(pb.After),
+		Score:            pb.Score,
+		Kind:             change.Kind(pb.Kind),
+	}
+
+	if pb.Time != nil {
+		cp.Time = pb.Time.AsTime()
+	}
+
+	return cp
+}
+
+func statsToProto(s change.Stats) *changepb.Stats {
+	return &changepb.Stats{N: int64(s.Len()), Mean: s.Mean(), Variance: s.Var()}
+}
+
+func statsFromProto(pb *changepb.Stats) change.Stats {
+	return change.NewStats(int(pb.GetN()), pb.GetMean(), pb.GetVariance())
+}
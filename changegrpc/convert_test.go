@@ -0,0 +1,35 @@
+package changegrpc
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dgryski/go-change"
+)
+
+func TestProto(t *testing.T) {
+	var tests = []*change.ChangePoint{
+		nil,
+		{
+			Index:            100,
+			Difference:       2.5,
+			Confidence:       0.999,
+			Statistic:        12.25,
+			DegreesOfFreedom: 177.5,
+			Critical:         3.1,
+			Before:           change.NewStats(100, 1, 0.5),
+			After:            change.NewStats(80, 3.5, 0),
+			Score:            0.75,
+			Time:             time.Date(2024, 3, 1, 12, 30, 0, 500, time.UTC),
+			Kind:             change.Ramp,
+		},
+		{Index: 7, Before: change.NewStats(7, 0, 0), After: change.NewStats(9, 1, 1)},
+	}
+
+	for _, cp := range tests {
+		if got := FromProto(ToProto(cp)); !reflect.DeepEqual(got, cp) {
+			t.Errorf("FromProto(ToProto(%+v))=%+v", cp, got)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// Package changegrpc serves the change point detector over gRPC, using the
+// messages and service of package changepb, and provides a client for it.
+package changegrpc
+
+import (
+	"context"
+	"io"
+
+	"github.com/dgryski/go-change"
+	"github.com/dgryski/go-change/changepb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultMaxKeys is the most keys per stream if Server.MaxKeys is zero
+const DefaultMaxKeys = 1000
+
+// Server implements the Detector service.  Register it with
+// changepb.RegisterDetectorServer.
+type Server struct {
+	changepb.UnimplementedDetectorServer
+
+	// Detector is the detector used by Detect, with the options of each
+	// request applied to a copy, and by the rolling detectors of Stream
+	Detector change.Detector
+
+	// WindowSize is the window size of the rolling detectors of Stream.  If
+	// zero, change.DefaultWindowSize is used.
+	WindowSize int
+
+	// MaxKeys is the most keys a single stream may send samples for.  If
+	// zero, DefaultMaxKeys is used.
+	MaxKeys int
+
+	// Suppression is the suppression of the rolling detectors of Stream
+	Suppression change.Suppression
+}
+
+// Detect checks the window of the request for a change point.  A window or
+// options which Detector.Validate rejects are an InvalidArgument error.
+func (s *Server) Detect(ctx context.Context, req *changepb.DetectRequest) (*changepb.DetectResponse, error) {
+	d := s.Detector
+	if o := req.GetOptions(); o != nil {
+		if o.MinSampleSize != 0 {
+			d.MinSampleSize = int(o.MinSampleSize)
+		}
+		if o.MinConfidence != 0 {
+			d.MinConfidence = o.MinConfidence
+		}
+	}
+
+	cp, err := d.CheckValid(req.Window)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return &changepb.DetectResponse{ChangePoint: ToProto(cp)}, nil
+}
+
+// Stream runs a rolling detector for each key of the samples received, and
+// sends an event for each change point found.  The detectors belong to the
+// stream, and are discarded when it ends.  A sample for a key beyond the
+// first MaxKeys ends the stream with a ResourceExhausted error.
+func (s *Server) Stream(stream changepb.Detector_StreamServer) error {
+	maxKeys := s.MaxKeys
+	if maxKeys == 0 {
+		maxKeys = DefaultMaxKeys
+	}
+
+	d := s.Detector
+	keys := 0
+	m := change.NewManager(func(string) *change.Rolling {
+		keys++
+		r := change.NewRolling(&d, s.WindowSize)
+		r.Suppression = s.Suppression
+		return r
+	})
+
+	for {
+		sample, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if keys >= maxKeys && m.Detector(sample.Key) == nil {
+			return status.Errorf(codes.ResourceExhausted, "more than %d keys", maxKeys)
+		}
+
+		var ev *change.Event
+		if sample.Time != nil {
+			ev = m.PushAt(sample.Key, sample.Time.AsTime(), sample.Value)
+		} else {
+			ev = m.Push(sample.Key, sample.Value)
+		}
+		if ev == nil {
+			continue
+		}
+
+		if err := stream.Send(&changepb.Event{Key: ev.Key, ChangePoint: ToProto(ev.ChangePoint)}); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package changegrpc
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dgryski/go-change"
+	"github.com/dgryski/go-change/changepb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// dial starts s on an in-memory listener and returns a client for it
+func dial(t *testing.T, s *Server) *Client {
+	lis := bufconn.Listen(1 << 20)
+	gs := grpc.NewServer()
+	changepb.RegisterDetectorServer(gs, s)
+	go gs.Serve(lis)
+	t.Cleanup(gs.Stop)
+
+	cc, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { cc.Close() })
+
+	return NewClient(cc)
+}
+
+// steps returns length samples of each level in turn, with unit normal noise
+func steps(rnd *rand.Rand, length int, levels ...float64) []float64 {
+	var w []float64
+	for _, l := range levels {
+		for i := 0; i < length; i++ {
+			w = append(w, l+rnd.NormFloat64())
+		}
+	}
+	return w
+}
+
+func TestDetect(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	c := dial(t, &Server{Detector: change.Detector{MinConfidence: 0.99}})
+	ctx := context.Background()
+
+	w := steps(rnd, 100, 0, 5)
+
+	d := change.Detector{MinConfidence: 0.99}
+	want := d.Check(w)
+
+	got, err := c.Detect(ctx, w)
+	if err != nil {
+		t.Fatalf("Detect()=%v", err)
+	}
+	if got == nil || got.Index != want.Index || got.Confidence != want.Confidence || got.Before != want.Before {
+		t.Errorf("Detect()=%+v, wanted %+v", got, want)
+	}
+
+	if got, err := c.Detect(ctx, steps(rnd, 200, 0)); got != nil || err != nil {
+		t.Errorf("Detect(no change)=%+v, %v, wanted nil", got, err)
+	}
+
+	c.MinSampleSize = 150
+	if _, err := c.Detect(ctx, w); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Detect(short window)=%v, wanted InvalidArgument", err)
+	}
+}
+
+func TestStream(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	srv := &Server{
+		Detector:    change.Detector{MinSampleSize: 30, MinConfidence: 0.99999},
+		WindowSize:  100,
+		Suppression: change.Suppression{Cooldown: 1000},
+	}
+	c := dial(t, srv)
+
+	s, err := c.Stream(context.Background())
+	if err != nil {
+		t.Fatalf("Stream()=%v", err)
+	}
+
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	a, b := steps(rnd, 200, 0, 5), steps(rnd, 400, 0)
+
+	var samples []change.Sample
+	for i := range b {
+		if i < len(a) {
+			samples = append(samples, change.Sample{Key: "a", Time: start.Add(time.Duration(i) * time.Second), Value: a[i]})
+		}
+		samples = append(samples, change.Sample{Key: "b", Value: b[i]})
+	}
+
+	go func() {
+		for _, sample := range samples {
+			s.Send(sample)
+		}
+		s.CloseSend()
+	}()
+
+	var events []change.Event
+	for {
+		ev, err := s.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv()=%v", err)
+		}
+		events = append(events, ev)
+	}
+
+	// the same detectors run locally
+	d := srv.Detector
+	m := change.NewManager(func(string) *change.Rolling {
+		r := change.NewRolling(&d, srv.WindowSize)
+		r.Suppression = srv.Suppression
+		return r
+	})
+	var want []change.Event
+	for _, sample := range samples {
+		if ev := m.PushAt(sample.Key, sample.Time, sample.Value); ev != nil {
+			want = append(want, *ev)
+		}
+	}
+
+	if len(events) != 1 || !reflect.DeepEqual(events, want) {
+		t.Fatalf("Stream() events=%+v, wanted %+v", events, want)
+	}
+	if ev := events[0]; ev.Key != "a" || ev.ChangePoint.Index < 180 || ev.ChangePoint.Index > 210 {
+		t.Errorf("Stream()=%s %+v, wanted a change in a at 200", ev.Key, ev.ChangePoint)
+	}
+}
+
+func TestStreamMaxKeys(t *testing.T) {
+	// a zero WindowSize uses the default
+	c := dial(t, &Server{Detector: change.Detector{MinConfidence: 0.99}, MaxKeys: 2})
+
+	s, err := c.Stream(context.Background())
+	if err != nil {
+		t.Fatalf("Stream()=%v", err)
+	}
+
+	for _, key := range []string{"a", "b", "a", "b", "c"} {
+		if err := s.Send(change.Sample{Key: key, Value: 1}); err != nil {
+			break
+		}
+	}
+
+	if _, err := s.Recv(); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Recv() with too many keys=%v, wanted ResourceExhausted", err)
+	}
+}
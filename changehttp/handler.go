@@ -0,0 +1,113 @@
+// Package changehttp serves the change point detector over HTTP: Handler
+// checks single windows, and StreamHandler streams samples over a WebSocket.
+package changehttp
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/dgryski/go-change"
+)
+
+// defaultMaxBodySize is the largest request body read if MaxBodySize is not set
+const defaultMaxBodySize = 32 << 20
+
+// Handler checks windows of samples for a change point.  It is meant to be
+// registered for POST /detect:
+//
+//	http.Handle("/detect", &changehttp.Handler{Detector: change.Detector{MinConfidence: 0.99}})
+//
+// The request body is a JSON array of numbers, in which null is a missing
+// value, handled by the detector's Missing policy.  The query parameters
+// min_sample_size and min_confidence override the detector's fields of the
+// same names.  The response is the change point encoded as described by
+// change.JSONVersion, or null if there is none.  A body which can't be
+// decoded, or a window or parameters which Detector.Validate rejects, is a
+// 400 Bad Request, with the reason as the text of the response.
+type Handler struct {
+	// Detector is the detector, which is copied for each request
+	Detector change.Detector
+
+	// MaxBodySize is the largest request body read, in bytes.  Zero means 32MiB.
+	MaxBodySize int64
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	d := h.Detector
+	if err := params(&d, r); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	max := h.MaxBodySize
+	if max == 0 {
+		max = defaultMaxBodySize
+	}
+
+	var samples []*float64
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, max)).Decode(&samples); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "bad samples: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	window := make([]float64, len(samples))
+	for i, v := range samples {
+		if v == nil {
+			window[i] = math.NaN()
+		} else {
+			window[i] = *v
+		}
+	}
+
+	cp, err := d.CheckValid(window)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	b, err := json.Marshal(cp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(append(b, '\n'))
+}
+
+// params sets the fields of d given by the query parameters of r
+func params(d *change.Detector, r *http.Request) error {
+	q := r.URL.Query()
+
+	if s := q.Get("min_sample_size"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return errors.New("bad min_sample_size: " + err.Error())
+		}
+		d.MinSampleSize = n
+	}
+
+	if s := q.Get("min_confidence"); s != "" {
+		c, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return errors.New("bad min_confidence: " + err.Error())
+		}
+		d.MinConfidence = c
+	}
+
+	return nil
+}
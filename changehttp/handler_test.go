@@ -0,0 +1,102 @@
+package changehttp
+
+import (
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dgryski/go-change"
+)
+
+// steps returns a window of unit normal noise, length items about each level
+func steps(rnd *rand.Rand, length int, levels ...float64) []float64 {
+	var w []float64
+	for _, l := range levels {
+		for i := 0; i < length; i++ {
+			w = append(w, l+rnd.NormFloat64())
+		}
+	}
+	return w
+}
+
+func TestHandler(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	h := &Handler{Detector: change.Detector{MinConfidence: 0.99}, MaxBodySize: 1 << 16}
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	step, _ := json.Marshal(steps(rnd, 100, 0, 5))
+	flat, _ := json.Marshal(steps(rnd, 100, 0))
+
+	var tests = []struct {
+		method string
+		query  string
+		body   string
+		code   int
+		index  int // of the change point, or -1 for none
+	}{
+		{"POST", "", string(step), http.StatusOK, 100},
+		{"POST", "", string(flat), http.StatusOK, -1},
+		{"POST", "", "[1, 2, null, 4]", http.StatusBadRequest, 0},                // too short, and the null is rejected
+		{"POST", "?min_sample_size=150", string(step), http.StatusBadRequest, 0}, // too short
+		{"POST", "?min_confidence=x", string(step), http.StatusBadRequest, 0},
+		{"POST", "", `{"window": [1, 2]}`, http.StatusBadRequest, 0},
+		{"POST", "", "[" + strings.Repeat("1,", 1<<16) + "1]", http.StatusRequestEntityTooLarge, 0},
+		{"GET", "", "", http.StatusMethodNotAllowed, 0},
+	}
+
+	for _, tt := range tests {
+		req, _ := http.NewRequest(tt.method, srv.URL+"/detect"+tt.query, strings.NewReader(tt.body))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var cp *change.ChangePoint
+		err = json.NewDecoder(resp.Body).Decode(&cp)
+		resp.Body.Close()
+
+		if resp.StatusCode != tt.code {
+			t.Errorf("%s /detect%s=%d, wanted %d", tt.method, tt.query, resp.StatusCode, tt.code)
+			continue
+		}
+		if tt.code != http.StatusOK {
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s /detect%s: %v", tt.method, tt.query, err)
+			continue
+		}
+
+		switch {
+		case tt.index < 0 && cp != nil:
+			t.Errorf("%s /detect%s=%+v, wanted null", tt.method, tt.query, cp)
+		case tt.index >= 0 && (cp == nil || cp.Index < tt.index-5 || cp.Index > tt.index+5):
+			t.Errorf("%s /detect%s=%+v, wanted a change point at %d", tt.method, tt.query, cp, tt.index)
+		}
+	}
+}
+
+func TestHandlerMissing(t *testing.T) {
+	h := &Handler{Detector: change.Detector{MinSampleSize: 3, MinConfidence: 0.99, Missing: change.MissingSkip}}
+
+	body := "[0, 0.1, null, -0.1, 0, 0.1, 5, 5.1, null, 4.9, 5, 5.1]"
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "/detect", strings.NewReader(body)))
+
+	var cp change.ChangePoint
+	if err := json.Unmarshal(w.Body.Bytes(), &cp); w.Code != http.StatusOK || err != nil {
+		t.Fatalf("POST /detect=%d %s, wanted a change point", w.Code, w.Body)
+	}
+	if cp.Index != 6 || cp.Before.Len() != 5 || cp.After.Len() != 5 {
+		t.Errorf("POST /detect=%+v, wanted index 6 with the nulls skipped", cp)
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type=%q, wanted application/json", got)
+	}
+}
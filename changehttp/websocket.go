@@ -0,0 +1,117 @@
+package changehttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dgryski/go-change"
+	"golang.org/x/net/websocket"
+)
+
+// StreamHandler runs rolling detectors over the samples a WebSocket client
+// sends, and sends it each change point found, such as for a dashboard to
+// annotate live graphs.
+//
+// Each message from the client is a JSON sample
+//
+//	{"key": "cpu", "time": "2024-03-01T12:00:00Z", "value": 0.25}
+//
+// in which key and time may be left out, but value may not.  Each series,
+// identified by its key, has its own detector, which belongs to the
+// connection.  Each message to the client is an event
+//
+//	{"key": "cpu", "change_point": {...}}
+//
+// with the change point encoded as described by change.JSONVersion, or
+// {"error": "..."} for a message which couldn't be decoded, or whose key is
+// beyond the first MaxKeys of the connection, which is skipped.
+type StreamHandler struct {
+	// Detector is the detector of each series
+	Detector change.Detector
+
+	// WindowSize is the window size of each series' rolling detector.  If
+	// zero, change.DefaultWindowSize is used.
+	WindowSize int
+
+	// MaxKeys is the most series a single connection may send samples for.
+	// Zero means 1000.
+	MaxKeys int
+
+	// Suppression is the suppression of each series' rolling detector
+	Suppression change.Suppression
+}
+
+// defaultMaxKeys is the most series per connection if MaxKeys is not set
+const defaultMaxKeys = 1000
+
+type jsonSample struct {
+	Key   string    `json:"key"`
+	Time  time.Time `json:"time"`
+	Value *float64  `json:"value"`
+}
+
+type jsonEvent struct {
+	Key         string              `json:"key,omitempty"`
+	ChangePoint *change.ChangePoint `json:"change_point,omitempty"`
+	Error       string              `json:"error,omitempty"`
+}
+
+func (h *StreamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	websocket.Handler(h.serve).ServeHTTP(w, r)
+}
+
+func (h *StreamHandler) serve(ws *websocket.Conn) {
+	maxKeys := h.MaxKeys
+	if maxKeys == 0 {
+		maxKeys = defaultMaxKeys
+	}
+
+	d := h.Detector
+	keys := 0
+	m := change.NewManager(func(string) *change.Rolling {
+		keys++
+		r := change.NewRolling(&d, h.WindowSize)
+		r.Suppression = h.Suppression
+		return r
+	})
+
+	for {
+		var msg []byte
+		if websocket.Message.Receive(ws, &msg) != nil {
+			// the connection is closed when the handler returns
+			return
+		}
+
+		var s jsonSample
+		var bad string
+		if err := json.Unmarshal(msg, &s); err != nil {
+			bad = "bad sample: " + err.Error()
+		} else if s.Value == nil {
+			bad = "bad sample: no value"
+		} else if keys >= maxKeys && m.Detector(s.Key) == nil {
+			bad = "too many keys: the limit is " + strconv.Itoa(maxKeys)
+		}
+		if bad != "" {
+			if websocket.JSON.Send(ws, jsonEvent{Error: bad}) != nil {
+				return
+			}
+			continue
+		}
+
+		var ev *change.Event
+		if !s.Time.IsZero() {
+			ev = m.PushAt(s.Key, s.Time, *s.Value)
+		} else {
+			ev = m.Push(s.Key, *s.Value)
+		}
+		if ev == nil {
+			continue
+		}
+
+		if websocket.JSON.Send(ws, jsonEvent{Key: ev.Key, ChangePoint: ev.ChangePoint}) != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package changehttp
+
+import (
+	"math/rand"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dgryski/go-change"
+	"golang.org/x/net/websocket"
+)
+
+func TestStreamHandler(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	h := &StreamHandler{
+		Detector:    change.Detector{MinSampleSize: 30, MinConfidence: 0.99999},
+		WindowSize:  100,
+		Suppression: change.Suppression{Cooldown: 1000},
+	}
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	ws, err := websocket.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), "", srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	a, b := steps(rnd, 200, 0, 5), steps(rnd, 200, 0, 0)
+
+	go func() {
+		websocket.Message.Send(ws, `{"value": "x"}`)
+		for i := range a {
+			websocket.JSON.Send(ws, jsonSample{Key: "a", Time: start.Add(time.Duration(i) * time.Second), Value: &a[i]})
+			websocket.JSON.Send(ws, jsonSample{Key: "b", Value: &b[i]})
+		}
+	}()
+
+	var ev jsonEvent
+	if err := websocket.JSON.Receive(ws, &ev); err != nil || !strings.HasPrefix(ev.Error, "bad sample") {
+		t.Fatalf("Receive()=%+v, %v, wanted an error for the bad sample", ev, err)
+	}
+
+	ev = jsonEvent{}
+	if err := websocket.JSON.Receive(ws, &ev); err != nil {
+		t.Fatalf("Receive()=%v", err)
+	}
+	cp := ev.ChangePoint
+	if ev.Key != "a" || cp == nil || cp.Index < 180 || cp.Index > 210 {
+		t.Fatalf("Receive()=%s %+v, wanted a change in a at 200", ev.Key, cp)
+	}
+	if want := start.Add(time.Duration(cp.Index) * time.Second); !cp.Time.Equal(want) {
+		t.Errorf("Receive() time=%v, wanted %v", cp.Time, want)
+	}
+}
+
+func TestStreamHandlerLimits(t *testing.T) {
+	// a zero WindowSize uses the default
+	srv := httptest.NewServer(&StreamHandler{Detector: change.Detector{MinConfidence: 0.99}, MaxKeys: 2})
+	defer srv.Close()
+
+	ws, err := websocket.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), "", srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	var tests = []struct {
+		msg  string
+		want string
+	}{
+		{`{"key": "a"}`, "bad sample: no value"},
+		{`{"key": "c", "value": 1}`, "too many keys"},
+	}
+
+	for _, msg := range []string{`{"key": "a", "value": 1}`, `{"key": "b", "value": 1}`, `{"key": "a", "value": 0}`} {
+		websocket.Message.Send(ws, msg)
+	}
+
+	for _, tt := range tests {
+		websocket.Message.Send(ws, tt.msg)
+
+		var ev jsonEvent
+		if err := websocket.JSON.Receive(ws, &ev); err != nil || !strings.HasPrefix(ev.Error, tt.want) {
+			t.Errorf("Receive() after %s=%+v, %v, wanted %q", tt.msg, ev, err, tt.want)
+		}
+	}
+}
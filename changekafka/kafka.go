@@ -0,0 +1,112 @@
+// Package changekafka runs change point detectors over the messages of a
+// Kafka topic, and produces their change events to another.
+package changekafka
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/dgryski/go-change"
+	"github.com/segmentio/kafka-go"
+)
+
+// Reader reads messages from a topic, such as a *kafka.Reader in a consumer group
+type Reader interface {
+	FetchMessage(ctx context.Context) (kafka.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+// Writer writes messages to a topic, such as a *kafka.Writer
+type Writer interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+var (
+	_ Reader = (*kafka.Reader)(nil)
+	_ Writer = (*kafka.Writer)(nil)
+)
+
+// Adapter pushes the value of each message read to the detector of the
+// message's key, and writes each change event as a message with the same
+// key, holding the event encoded as JSON.  Messages are committed once they
+// have been handled, so a message whose event couldn't be written is read
+// again after a restart.
+//
+// A *kafka.Reader commits synchronously, with a round trip to the broker for
+// each message, unless its ReaderConfig.CommitInterval is set, when the
+// commits are batched and sent on that interval.  Set it for a busy topic.
+type Adapter struct {
+	// Manager holds the detector of each key
+	Manager *change.Manager
+
+	// Decode returns the value and time of a message.  If nil, Decode is
+	// used.
+	Decode func(msg kafka.Message) (float64, time.Time, error)
+
+	// DecodeError, if set, is called with each message which can't be
+	// decoded.  The message is skipped.
+	DecodeError func(msg kafka.Message, err error)
+}
+
+// Run handles the messages of r until ctx is done or r or w fails.  A nil w
+// writes no events, for a manager whose Sink reports them instead.
+func (a *Adapter) Run(ctx context.Context, r Reader, w Writer) error {
+	decode := a.Decode
+	if decode == nil {
+		decode = Decode
+	}
+
+	for {
+		msg, err := r.FetchMessage(ctx)
+		if err != nil {
+			return err
+		}
+
+		v, t, err := decode(msg)
+		if err != nil {
+			if a.DecodeError != nil {
+				a.DecodeError(msg, err)
+			}
+		} else if err := a.push(ctx, w, msg.Key, t, v); err != nil {
+			return err
+		}
+
+		if err := r.CommitMessages(ctx, msg); err != nil {
+			return err
+		}
+	}
+}
+
+// push pushes an item to the detector of key, and writes any event to w
+func (a *Adapter) push(ctx context.Context, w Writer, key []byte, t time.Time, v float64) error {
+	var ev *change.Event
+	if !t.IsZero() {
+		ev = a.Manager.PushAt(string(key), t, v)
+	} else {
+		ev = a.Manager.Push(string(key), v)
+	}
+	if ev == nil || w == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	return w.WriteMessages(ctx, kafka.Message{Key: key, Value: data})
+}
+
+// Decode decodes a message holding an item as described by change.ParseItem.
+// The time is the message's timestamp if the item has none.
+func Decode(msg kafka.Message) (float64, time.Time, error) {
+	v, t, err := change.ParseItem(msg.Value)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	if t.IsZero() {
+		t = msg.Time
+	}
+	return v, t, nil
+}
@@ -0,0 +1,116 @@
+package changekafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/dgryski/go-change"
+	"github.com/segmentio/kafka-go"
+)
+
+// topic is a Reader and Writer of an in-memory topic
+type topic struct {
+	msgs      []kafka.Message
+	next      int
+	committed int
+}
+
+func (tp *topic) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	if tp.next == len(tp.msgs) {
+		return kafka.Message{}, context.Canceled
+	}
+	tp.next++
+	return tp.msgs[tp.next-1], nil
+}
+
+func (tp *topic) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	tp.committed += len(msgs)
+	return nil
+}
+
+func (tp *topic) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	tp.msgs = append(tp.msgs, msgs...)
+	return nil
+}
+
+func TestAdapter(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	in := &topic{msgs: []kafka.Message{{Key: []byte("a"), Value: []byte("not a number")}}}
+	for i := 0; i < 400; i++ {
+		a := rnd.NormFloat64()
+		if i >= 200 {
+			a += 5
+		}
+		in.msgs = append(in.msgs,
+			kafka.Message{Key: []byte("a"), Value: []byte(fmt.Sprint(a)), Time: start.Add(time.Duration(i) * time.Second)},
+			kafka.Message{Key: []byte("b"), Value: []byte(fmt.Sprintf(`{"value": %v}`, rnd.NormFloat64()))},
+		)
+	}
+
+	var bad int
+	a := &Adapter{
+		Manager: change.NewManager(func(string) *change.Rolling {
+			r := change.NewRolling(change.New(30, 0.99999), 100)
+			r.Cooldown = 1000
+			return r
+		}),
+		DecodeError: func(kafka.Message, error) { bad++ },
+	}
+
+	out := &topic{}
+	if err := a.Run(context.Background(), in, out); !errors.Is(err, context.Canceled) {
+		t.Errorf("Run()=%v, wanted the reader's error", err)
+	}
+
+	if bad != 1 || in.committed != len(in.msgs) {
+		t.Errorf("Run() decode errors=%d committed=%d, wanted 1 and all %d", bad, in.committed, len(in.msgs))
+	}
+
+	if len(out.msgs) != 1 || string(out.msgs[0].Key) != "a" {
+		t.Fatalf("Run() wrote %d messages, wanted one for a", len(out.msgs))
+	}
+
+	var ev change.Event
+	if err := json.Unmarshal(out.msgs[0].Value, &ev); err != nil {
+		t.Fatalf("Run() wrote %s: %v", out.msgs[0].Value, err)
+	}
+	cp := ev.ChangePoint
+	if ev.Key != "a" || cp.Index < 180 || cp.Index > 210 || !cp.Time.Equal(start.Add(time.Duration(cp.Index)*time.Second)) {
+		t.Errorf("Run() wrote %s %+v, wanted a change in a at 200 with its time", ev.Key, cp)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	ts := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	var tests = []struct {
+		value string
+		v     float64
+		t     time.Time
+		ok    bool
+	}{
+		{"1.5", 1.5, ts, true},
+		{" -2e3\n", -2000, ts, true},
+		{`{"value": 3}`, 3, ts, true},
+		{`{"value": 3, "time": "2024-03-02T00:00:00Z"}`, 3, ts.Add(24 * time.Hour), true},
+		{`{"time": "2024-03-02T00:00:00Z"}`, 0, time.Time{}, false},
+		{`{"value": "3"}`, 0, time.Time{}, false},
+		{"", 0, time.Time{}, false},
+		{"x", 0, time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		v, got, err := Decode(kafka.Message{Value: []byte(tt.value), Time: ts})
+		if (err == nil) != tt.ok || v != tt.v || !got.Equal(tt.t) {
+			t.Errorf("Decode(%q)=%v, %v, %v, wanted %v, %v", tt.value, v, got, err, tt.v, tt.t)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Package changemqtt runs change point detectors over the messages of MQTT
+// topics, such as the readings of IoT sensors, and publishes their change
+// events back.
+package changemqtt
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/dgryski/go-change"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// Publisher publishes messages, such as an mqtt.Client
+type Publisher interface {
+	Publish(topic string, qos byte, retained bool, payload interface{}) mqtt.Token
+}
+
+var _ Publisher = mqtt.Client(nil)
+
+// Adapter pushes each reading received to the detector of the message's
+// topic, and publishes each change event, encoded as JSON, to the topic with
+// Prefix added.  Readings are parsed by change.ParseItem, and those without
+// a time are given the time they were received.  Messages on topics with
+// Prefix are the adapter's own events, and are ignored, so a wildcard
+// subscription such as # doesn't consume them.
+type Adapter struct {
+	// Manager holds the detector of each topic
+	Manager *change.Manager
+
+	// Publisher publishes the events, and is usually the mqtt.Client the
+	// adapter subscribes with.  Leave it nil to report events only through
+	// the manager's Sink.
+	Publisher Publisher
+
+	// Key, if set, names the detector of a topic.  If nil, the topic
+	// itself is the key.
+	Key func(topic string) string
+
+	// Prefix is added to a topic to give the topic of its events.  If empty,
+	// "change/" is used, so events for sensors/hall/temperature are
+	// published to change/sensors/hall/temperature.
+	Prefix string
+
+	// QoS is the quality of service of the events published
+	QoS byte
+
+	// Error, if set, is called with each message which can't be parsed,
+	// and with each error publishing an event.  It may be called from
+	// another goroutine.
+	Error func(msg mqtt.Message, err error)
+}
+
+// Subscribe subscribes to topic, which may have wildcards such as
+// sensors/+/temperature, with the given quality of service, and handles the
+// messages received.  The adapter may subscribe to several topics,
+// concurrently, and its events are published by its Publisher, not c.
+func (a *Adapter) Subscribe(c mqtt.Client, topic string, qos byte) mqtt.Token {
+	return c.Subscribe(topic, qos, a.Handle)
+}
+
+// Handle handles a message, and is the handler of Subscribe.  It doesn't
+// wait for events to be published, but it does wait for the manager's Sink,
+// so a slow sink holds up the client's other messages for as long as the
+// manager's SinkTimeout.
+func (a *Adapter) Handle(_ mqtt.Client, msg mqtt.Message) {
+	if strings.HasPrefix(msg.Topic(), a.prefix()) {
+		return
+	}
+
+	key := msg.Topic()
+	if a.Key != nil {
+		key = a.Key(key)
+	}
+
+	ev, err := a.Manager.PushMessage(key, msg.Payload(), time.Now())
+	if err != nil {
+		a.error(msg, err)
+		return
+	}
+	if ev == nil || a.Publisher == nil {
+		return
+	}
+
+	data, err := json.Marshal(ev)
+	if err != nil {
+		a.error(msg, err)
+		return
+	}
+
+	tok := a.Publisher.Publish(a.prefix()+msg.Topic(), a.QoS, false, data)
+	go func() {
+		<-tok.Done()
+		if err := tok.Error(); err != nil {
+			a.error(msg, err)
+		}
+	}()
+}
+
+// prefix returns the prefix of the topics of events
+func (a *Adapter) prefix() string {
+	if a.Prefix == "" {
+		return "change/"
+	}
+	return a.Prefix
+}
+
+func (a *Adapter) error(msg mqtt.Message, err error) {
+	if a.Error != nil {
+		a.Error(msg, err)
+	}
+}
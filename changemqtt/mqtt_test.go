@@ -0,0 +1,138 @@
+package changemqtt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dgryski/go-change"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// message is an mqtt.Message
+type message struct {
+	topic   string
+	payload []byte
+}
+
+func (m message) Duplicate() bool   { return false }
+func (m message) Qos() byte         { return 0 }
+func (m message) Retained() bool    { return false }
+func (m message) Topic() string     { return m.topic }
+func (m message) MessageID() uint16 { return 0 }
+func (m message) Payload() []byte   { return m.payload }
+func (m message) Ack()              {}
+
+// token is a completed mqtt.Token
+type token struct{ err error }
+
+func (t token) Wait() bool                     { return true }
+func (t token) WaitTimeout(time.Duration) bool { return true }
+func (t token) Done() <-chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}
+func (t token) Error() error { return t.err }
+
+type publisher struct {
+	topics   []string
+	payloads [][]byte
+	err      error
+}
+
+func (p *publisher) Publish(topic string, qos byte, retained bool, payload interface{}) mqtt.Token {
+	p.topics = append(p.topics, topic)
+	p.payloads = append(p.payloads, payload.([]byte))
+	return token{p.err}
+}
+
+func TestAdapter(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	var mu sync.Mutex
+	var errs []error
+
+	pub := &publisher{}
+	a := &Adapter{
+		Manager: change.NewManager(func(string) *change.Rolling {
+			r := change.NewRolling(change.New(30, 0.99999), 100)
+			r.Cooldown = 1000
+			return r
+		}),
+		Publisher: pub,
+		// one detector per room, whichever sensor reports it
+		Key: func(topic string) string { return strings.Split(topic, "/")[1] },
+		Error: func(msg mqtt.Message, err error) {
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+		},
+	}
+
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	a.Handle(nil, message{"sensors/hall/temperature", []byte("warm")})
+	for i := 0; i < 400; i++ {
+		v := 20 + rnd.NormFloat64()
+		if i >= 200 {
+			v += 5
+		}
+		topic := fmt.Sprintf("sensors/hall/temperature%d", i%2)
+		a.Handle(nil, message{topic, []byte(fmt.Sprintf(`{"value": %v, "time": %q}`, v, start.Add(time.Duration(i)*time.Second).Format(time.RFC3339)))})
+		a.Handle(nil, message{"sensors/kitchen/temperature", []byte(fmt.Sprint(20 + rnd.NormFloat64()))})
+	}
+
+	if len(pub.topics) != 1 || pub.topics[0] != "change/sensors/hall/temperature0" && pub.topics[0] != "change/sensors/hall/temperature1" {
+		t.Fatalf("Handle() published to %v, wanted one event for the hall", pub.topics)
+	}
+
+	var ev change.Event
+	if err := json.Unmarshal(pub.payloads[0], &ev); err != nil {
+		t.Fatalf("Handle() published %s: %v", pub.payloads[0], err)
+	}
+	cp := ev.ChangePoint
+	if ev.Key != "hall" || cp.Index < 180 || cp.Index > 210 || !cp.Time.Equal(start.Add(time.Duration(cp.Index)*time.Second)) {
+		t.Errorf("Handle() published %s %+v, wanted a change at 200 with its time", ev.Key, cp)
+	}
+
+	// the adapter's own events, such as with a subscription to #, are ignored
+	a.Handle(nil, message{pub.topics[0], pub.payloads[0]})
+
+	mu.Lock()
+	if len(errs) != 1 {
+		t.Errorf("Handle() errors=%v, wanted one for the reading which isn't a number", errs)
+	}
+	mu.Unlock()
+}
+
+func TestAdapterPublishError(t *testing.T) {
+	errPublish := errors.New("not connected")
+
+	done := make(chan error, 10)
+	a := &Adapter{
+		Manager: change.NewManager(func(string) *change.Rolling {
+			return change.NewRolling(change.New(3, 0.9), 10)
+		}),
+		Publisher: &publisher{err: errPublish},
+		Error:     func(msg mqtt.Message, err error) { done <- err },
+	}
+
+	for _, v := range []float64{0, 0.1, -0.1, 0, 0.1, 10, 10.1, 9.9, 10, 10.1} {
+		a.Handle(nil, message{"sensors/hall/vibration", []byte(fmt.Sprint(v))})
+	}
+
+	select {
+	case err := <-done:
+		if err != errPublish {
+			t.Errorf("Error()=%v, wanted %v", err, errPublish)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Error() was not called for the failed publish")
+	}
+}
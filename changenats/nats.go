@@ -0,0 +1,100 @@
+// Package changenats runs change point detectors over the messages of NATS
+// subjects, and publishes their change events back.
+package changenats
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/dgryski/go-change"
+	"github.com/nats-io/nats.go"
+)
+
+// Publisher publishes messages, such as a *nats.Conn
+type Publisher interface {
+	Publish(subject string, data []byte) error
+}
+
+var _ Publisher = (*nats.Conn)(nil)
+
+// Adapter pushes each item received to the detector of the message's
+// subject, and publishes each change event, encoded as JSON, to the subject
+// with Prefix added.  Items are parsed by change.ParseItem, and those
+// without a time are given the time they were received.  Messages on
+// subjects with Prefix are the adapter's own events, and are ignored, so a
+// wildcard subscription such as > doesn't consume them.
+type Adapter struct {
+	// Manager holds the detector of each subject
+	Manager *change.Manager
+
+	// Publisher publishes the events, and is usually the *nats.Conn the
+	// adapter subscribes with.  If nil, nothing is published, and the
+	// adapter only feeds the manager.
+	Publisher Publisher
+
+	// Key, if set, maps a subject to the key of its detector.  For
+	// example, it may drop the host from metrics.<host>.cpu, to watch the
+	// readings of every host as one series.
+	Key func(subject string) string
+
+	// Prefix is added to a subject to give the subject of its events.  If
+	// empty, "change." is used, so events for metrics.cpu are published
+	// to change.metrics.cpu.
+	Prefix string
+
+	// Error, if set, is called with each message which can't be parsed,
+	// and with each error publishing an event
+	Error func(msg *nats.Msg, err error)
+}
+
+// Subscribe subscribes to subject, which may be a pattern such as
+// metrics.>, and handles the messages received until the subscription is
+// drained or unsubscribed.  The adapter may subscribe to several subjects,
+// concurrently, and its events are published by its Publisher, not nc.
+func (a *Adapter) Subscribe(nc *nats.Conn, subject string) (*nats.Subscription, error) {
+	return nc.Subscribe(subject, a.Handle)
+}
+
+// Handle handles a message, and is the handler of Subscribe
+func (a *Adapter) Handle(msg *nats.Msg) {
+	if strings.HasPrefix(msg.Subject, a.prefix()) {
+		return
+	}
+
+	key := msg.Subject
+	if a.Key != nil {
+		key = a.Key(key)
+	}
+
+	ev, err := a.Manager.PushMessage(key, msg.Data, time.Now())
+	if err != nil {
+		a.error(msg, err)
+		return
+	}
+	if ev == nil || a.Publisher == nil {
+		return
+	}
+
+	data, err := json.Marshal(ev)
+	if err == nil {
+		err = a.Publisher.Publish(a.prefix()+msg.Subject, data)
+	}
+	if err != nil {
+		a.error(msg, err)
+	}
+}
+
+// prefix returns the prefix of the subjects of events
+func (a *Adapter) prefix() string {
+	if a.Prefix == "" {
+		return "change."
+	}
+	return a.Prefix
+}
+
+func (a *Adapter) error(msg *nats.Msg, err error) {
+	if a.Error != nil {
+		a.Error(msg, err)
+	}
+}
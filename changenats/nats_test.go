@@ -0,0 +1,100 @@
+package changenats
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dgryski/go-change"
+	"github.com/nats-io/nats.go"
+)
+
+type published struct {
+	subject string
+	data    []byte
+}
+
+type publisher []published
+
+func (p *publisher) Publish(subject string, data []byte) error {
+	*p = append(*p, published{subject, data})
+	return nil
+}
+
+func TestAdapter(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	var pub publisher
+	var bad []*nats.Msg
+	a := &Adapter{
+		Manager: change.NewManager(func(string) *change.Rolling {
+			r := change.NewRolling(change.New(30, 0.99999), 100)
+			r.Cooldown = 1000
+			return r
+		}),
+		Publisher: &pub,
+		Error:     func(msg *nats.Msg, err error) { bad = append(bad, msg) },
+	}
+
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	a.Handle(&nats.Msg{Subject: "metrics.cpu", Data: []byte("high")})
+	for i := 0; i < 400; i++ {
+		v := rnd.NormFloat64()
+		if i >= 200 {
+			v += 5
+		}
+		a.Handle(&nats.Msg{Subject: "metrics.cpu", Data: []byte(fmt.Sprintf(`{"value": %v, "time": %q}`, v, start.Add(time.Duration(i)*time.Second).Format(time.RFC3339)))})
+		a.Handle(&nats.Msg{Subject: "metrics.mem", Data: []byte(fmt.Sprint(rnd.NormFloat64()))})
+	}
+
+	if len(bad) != 1 || string(bad[0].Data) != "high" {
+		t.Errorf("Handle() errors for %v, wanted the one which isn't a number", bad)
+	}
+
+	if len(pub) != 1 || pub[0].subject != "change.metrics.cpu" {
+		t.Fatalf("Handle() published %v, wanted one event to change.metrics.cpu", pub)
+	}
+
+	var ev change.Event
+	if err := json.Unmarshal(pub[0].data, &ev); err != nil {
+		t.Fatalf("Handle() published %s: %v", pub[0].data, err)
+	}
+	cp := ev.ChangePoint
+	if ev.Key != "metrics.cpu" || cp.Index < 180 || cp.Index > 210 || !cp.Time.Equal(start.Add(time.Duration(cp.Index)*time.Second)) {
+		t.Errorf("Handle() published %s %+v, wanted a change at 200 with its time", ev.Key, cp)
+	}
+
+	// the adapter's own events, such as with a subscription to >, are ignored
+	a.Handle(&nats.Msg{Subject: "change.metrics.cpu", Data: pub[0].data})
+	if len(bad) != 1 || a.Manager.Detector("change.metrics.cpu") != nil {
+		t.Errorf("Handle() of an event made a detector, or errors for %v", bad)
+	}
+}
+
+func TestAdapterKey(t *testing.T) {
+	var pub publisher
+	a := &Adapter{
+		Manager: change.NewManager(func(string) *change.Rolling {
+			return change.NewRolling(change.New(3, 0.9), 10)
+		}),
+		Publisher: &pub,
+		// one detector per host, whichever metric reports it
+		Key:    func(subject string) string { return strings.Split(subject, ".")[1] },
+		Prefix: "alerts.",
+	}
+
+	for i, v := range []float64{0, 0.1, -0.1, 0, 0.1, 10, 10.1, 9.9, 10, 10.1} {
+		a.Handle(&nats.Msg{Subject: fmt.Sprintf("metrics.web1.cpu%d", i%2), Data: []byte(fmt.Sprint(v))})
+	}
+
+	if keys := a.Manager.Keys(); len(keys) != 1 || keys[0] != "web1" {
+		t.Errorf("Keys()=%v, wanted [web1]", keys)
+	}
+	if len(pub) != 1 || !strings.HasPrefix(pub[0].subject, "alerts.metrics.web1.cpu") {
+		t.Errorf("Handle() published %v, wanted one event for web1 under alerts.", pub)
+	}
+}
@@ -0,0 +1,122 @@
+// Package changeotel instruments a change point detector with OpenTelemetry
+// metrics and traces, so that its behaviour can be observed when it is
+// embedded in a larger service.
+package changeotel
+
+import (
+	"context"
+	"time"
+
+	"github.com/dgryski/go-change"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// scope is the instrumentation scope of the tracer and meter
+const scope = "github.com/dgryski/go-change/changeotel"
+
+// Detector is a detector whose checks are traced and measured.  Each check
+// is a span with the window's size and an event for each change point
+// found, and records the metrics
+//
+//	change.detections   the number of change points found
+//	change.duration     the time taken by each check, in seconds
+//	change.window.size  the number of items in each window checked
+//
+// with the attribute change.operation naming the method called.
+type Detector struct {
+	d *change.Detector
+
+	tracer     trace.Tracer
+	detections metric.Int64Counter
+	duration   metric.Float64Histogram
+	windowSize metric.Int64Histogram
+}
+
+// New returns an instrumented detector using d, with spans from tp and
+// metrics from mp.  Nil providers mean the global ones.
+func New(d *change.Detector, tp trace.TracerProvider, mp metric.MeterProvider) (*Detector, error) {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	if mp == nil {
+		mp = otel.GetMeterProvider()
+	}
+
+	meter := mp.Meter(scope)
+
+	detections, err := meter.Int64Counter("change.detections",
+		metric.WithDescription("Number of change points found."), metric.WithUnit("{change_point}"))
+	if err != nil {
+		return nil, err
+	}
+
+	duration, err := meter.Float64Histogram("change.duration",
+		metric.WithDescription("Time taken to check a window."), metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+
+	windowSize, err := meter.Int64Histogram("change.window.size",
+		metric.WithDescription("Number of items in a window checked."), metric.WithUnit("{item}"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Detector{
+		d:          d,
+		tracer:     tp.Tracer(scope),
+		detections: detections,
+		duration:   duration,
+		windowSize: windowSize,
+	}, nil
+}
+
+// Check is change.Detector.Check, traced as a child of any span in ctx
+func (d *Detector) Check(ctx context.Context, window []float64) *change.ChangePoint {
+	var cp *change.ChangePoint
+	d.observe(ctx, "check", window, func() []*change.ChangePoint {
+		cp = d.d.Check(window)
+		if cp == nil {
+			return nil
+		}
+		return []*change.ChangePoint{cp}
+	})
+	return cp
+}
+
+// Segment is change.Detector.Segment, traced as a child of any span in ctx
+func (d *Detector) Segment(ctx context.Context, series []float64) []*change.ChangePoint {
+	var cps []*change.ChangePoint
+	d.observe(ctx, "segment", series, func() []*change.ChangePoint {
+		cps = d.d.Segment(series)
+		return cps
+	})
+	return cps
+}
+
+// observe runs check in a span, and records its metrics
+func (d *Detector) observe(ctx context.Context, op string, window []float64, check func() []*change.ChangePoint) {
+	ctx, span := d.tracer.Start(ctx, "change."+op, trace.WithAttributes(attribute.Int("change.window.size", len(window))))
+	defer span.End()
+
+	start := time.Now()
+	cps := check()
+	elapsed := time.Since(start)
+
+	for _, cp := range cps {
+		span.AddEvent("change point", trace.WithAttributes(
+			attribute.Int("change.index", cp.Index),
+			attribute.Float64("change.difference", cp.Difference),
+			attribute.Float64("change.confidence", cp.Confidence),
+		))
+	}
+	span.SetAttributes(attribute.Int("change.points", len(cps)))
+
+	attrs := metric.WithAttributes(attribute.String("change.operation", op))
+	d.detections.Add(ctx, int64(len(cps)), attrs)
+	d.duration.Record(ctx, elapsed.Seconds(), attrs)
+	d.windowSize.Record(ctx, int64(len(window)), attrs)
+}
@@ -0,0 +1,92 @@
+package changeotel
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/dgryski/go-change"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// steps returns the levels in turn, each held for length items with unit
+// normal noise added
+func steps(rnd *rand.Rand, length int, levels ...float64) []float64 {
+	var w []float64
+	for _, l := range levels {
+		for i := 0; i < length; i++ {
+			w = append(w, l+rnd.NormFloat64())
+		}
+	}
+	return w
+}
+
+func TestDetector(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	spans := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	d, err := New(change.New(30, 0.99), tp, mp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if cp := d.Check(ctx, steps(rnd, 100, 0, 5)); cp == nil || cp.Index != 100 {
+		t.Errorf("Check()=%+v, wanted a change point at 100", cp)
+	}
+	if cp := d.Check(ctx, steps(rnd, 100, 0)); cp != nil {
+		t.Errorf("Check(no change)=%+v, wanted nil", cp)
+	}
+	if cps := d.Segment(ctx, steps(rnd, 100, 0, 5, 0)); len(cps) != 2 {
+		t.Errorf("Segment()=%d change points, wanted 2", len(cps))
+	}
+
+	ended := spans.Ended()
+	if len(ended) != 3 || ended[0].Name() != "change.check" || ended[2].Name() != "change.segment" {
+		t.Fatalf("spans=%v, wanted two checks and a segment", ended)
+	}
+	if events := ended[0].Events(); len(events) != 1 || len(ended[1].Events()) != 0 || len(ended[2].Events()) != 2 {
+		t.Errorf("span events=%v %v %v, wanted one for each change point", events, ended[1].Events(), ended[2].Events())
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatal(err)
+	}
+
+	counts := make(map[string]int64)
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		switch data := m.Data.(type) {
+		case metricdata.Sum[int64]:
+			for _, dp := range data.DataPoints {
+				counts[m.Name] += dp.Value
+			}
+		case metricdata.Histogram[float64]:
+			for _, dp := range data.DataPoints {
+				counts[m.Name] += int64(dp.Count)
+			}
+		case metricdata.Histogram[int64]:
+			for _, dp := range data.DataPoints {
+				counts[m.Name] += dp.Sum
+			}
+		}
+	}
+
+	want := map[string]int64{
+		"change.detections":  3,
+		"change.duration":    3,   // checks timed
+		"change.window.size": 600, // items checked
+	}
+	for name, n := range want {
+		if counts[name] != n {
+			t.Errorf("metric %s=%d, wanted %d", name, counts[name], n)
+		}
+	}
+}
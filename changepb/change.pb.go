@@ -0,0 +1,655 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: change.proto
+
+// Messages and a service for detecting change points over the network,
+// mirroring the types of github.com/dgryski/go-change.
+
+package changepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Kind is the kind of a change point.
+type Kind int32
+
+const (
+	Kind_KIND_UNCLASSIFIED    Kind = 0
+	Kind_KIND_STEP            Kind = 1
+	Kind_KIND_RAMP            Kind = 2
+	Kind_KIND_SPIKE           Kind = 3
+	Kind_KIND_VARIANCE_CHANGE Kind = 4
+)
+
+// Enum value maps for Kind.
+var (
+	Kind_name = map[int32]string{
+		0: "KIND_UNCLASSIFIED",
+		1: "KIND_STEP",
+		2: "KIND_RAMP",
+		3: "KIND_SPIKE",
+		4: "KIND_VARIANCE_CHANGE",
+	}
+	Kind_value = map[string]int32{
+		"KIND_UNCLASSIFIED":    0,
+		"KIND_STEP":            1,
+		"KIND_RAMP":            2,
+		"KIND_SPIKE":           3,
+		"KIND_VARIANCE_CHANGE": 4,
+	}
+)
+
+func (x Kind) Enum() *Kind {
+	p := new(Kind)
+	*p = x
+	return p
+}
+
+func (x Kind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Kind) Descriptor() protoreflect.EnumDescriptor {
+	return file_change_proto_enumTypes[0].Descriptor()
+}
+
+func (Kind) Type() protoreflect.EnumType {
+	return &file_change_proto_enumTypes[0]
+}
+
+func (x Kind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Kind.Descriptor instead.
+func (Kind) EnumDescriptor() ([]byte, []int) {
+	return file_change_proto_rawDescGZIP(), []int{0}
+}
+
+// Options configures the detector.  Zero values select the detector's defaults.
+type Options struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MinSampleSize int32                  `protobuf:"varint,1,opt,name=min_sample_size,json=minSampleSize,proto3" json:"min_sample_size,omitempty"`
+	MinConfidence float64                `protobuf:"fixed64,2,opt,name=min_confidence,json=minConfidence,proto3" json:"min_confidence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Options) Reset() {
+	*x = Options{}
+	mi := &file_change_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Options) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Options) ProtoMessage() {}
+
+func (x *Options) ProtoReflect() protoreflect.Message {
+	mi := &file_change_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Options.ProtoReflect.Descriptor instead.
+func (*Options) Descriptor() ([]byte, []int) {
+	return file_change_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Options) GetMinSampleSize() int32 {
+	if x != nil {
+		return x.MinSampleSize
+	}
+	return 0
+}
+
+func (x *Options) GetMinConfidence() float64 {
+	if x != nil {
+		return x.MinConfidence
+	}
+	return 0
+}
+
+// DetectRequest asks for the most likely change point in a window of items.
+type DetectRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Window        []float64              `protobuf:"fixed64,1,rep,packed,name=window,proto3" json:"window,omitempty"`
+	Options       *Options               `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DetectRequest) Reset() {
+	*x = DetectRequest{}
+	mi := &file_change_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DetectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DetectRequest) ProtoMessage() {}
+
+func (x *DetectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_change_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DetectRequest.ProtoReflect.Descriptor instead.
+func (*DetectRequest) Descriptor() ([]byte, []int) {
+	return file_change_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DetectRequest) GetWindow() []float64 {
+	if x != nil {
+		return x.Window
+	}
+	return nil
+}
+
+func (x *DetectRequest) GetOptions() *Options {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+// DetectResponse holds the change point found, which is unset if there is none.
+type DetectResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChangePoint   *ChangePoint           `protobuf:"bytes,1,opt,name=change_point,json=changePoint,proto3" json:"change_point,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DetectResponse) Reset() {
+	*x = DetectResponse{}
+	mi := &file_change_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DetectResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DetectResponse) ProtoMessage() {}
+
+func (x *DetectResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_change_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DetectResponse.ProtoReflect.Descriptor instead.
+func (*DetectResponse) Descriptor() ([]byte, []int) {
+	return file_change_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DetectResponse) GetChangePoint() *ChangePoint {
+	if x != nil {
+		return x.ChangePoint
+	}
+	return nil
+}
+
+// Stats are descriptive statistics for a block of items.
+type Stats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	N             int64                  `protobuf:"varint,1,opt,name=n,proto3" json:"n,omitempty"`
+	Mean          float64                `protobuf:"fixed64,2,opt,name=mean,proto3" json:"mean,omitempty"`
+	Variance      float64                `protobuf:"fixed64,3,opt,name=variance,proto3" json:"variance,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Stats) Reset() {
+	*x = Stats{}
+	mi := &file_change_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Stats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Stats) ProtoMessage() {}
+
+func (x *Stats) ProtoReflect() protoreflect.Message {
+	mi := &file_change_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Stats.ProtoReflect.Descriptor instead.
+func (*Stats) Descriptor() ([]byte, []int) {
+	return file_change_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Stats) GetN() int64 {
+	if x != nil {
+		return x.N
+	}
+	return 0
+}
+
+func (x *Stats) GetMean() float64 {
+	if x != nil {
+		return x.Mean
+	}
+	return 0
+}
+
+func (x *Stats) GetVariance() float64 {
+	if x != nil {
+		return x.Variance
+	}
+	return 0
+}
+
+// ChangePoint is a change point, with the fields of change.ChangePoint.
+type ChangePoint struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Index            int64                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Difference       float64                `protobuf:"fixed64,2,opt,name=difference,proto3" json:"difference,omitempty"`
+	Confidence       float64                `protobuf:"fixed64,3,opt,name=confidence,proto3" json:"confidence,omitempty"`
+	Statistic        float64                `protobuf:"fixed64,4,opt,name=statistic,proto3" json:"statistic,omitempty"`
+	DegreesOfFreedom float64                `protobuf:"fixed64,5,opt,name=degrees_of_freedom,json=degreesOfFreedom,proto3" json:"degrees_of_freedom,omitempty"`
+	Critical         float64                `protobuf:"fixed64,6,opt,name=critical,proto3" json:"critical,omitempty"`
+	Before           *Stats                 `protobuf:"bytes,7,opt,name=before,proto3" json:"before,omitempty"`
+	After            *Stats                 `protobuf:"bytes,8,opt,name=after,proto3" json:"after,omitempty"`
+	Score            float64                `protobuf:"fixed64,9,opt,name=score,proto3" json:"score,omitempty"`
+	Time             *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=time,proto3" json:"time,omitempty"`
+	Kind             Kind                   `protobuf:"varint,11,opt,name=kind,proto3,enum=change.v1.Kind" json:"kind,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ChangePoint) Reset() {
+	*x = ChangePoint{}
+	mi := &file_change_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangePoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePoint) ProtoMessage() {}
+
+func (x *ChangePoint) ProtoReflect() protoreflect.Message {
+	mi := &file_change_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangePoint.ProtoReflect.Descriptor instead.
+func (*ChangePoint) Descriptor() ([]byte, []int) {
+	return file_change_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ChangePoint) GetIndex() int64 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *ChangePoint) GetDifference() float64 {
+	if x != nil {
+		return x.Difference
+	}
+	return 0
+}
+
+func (x *ChangePoint) GetConfidence() float64 {
+	if x != nil {
+		return x.Confidence
+	}
+	return 0
+}
+
+func (x *ChangePoint) GetStatistic() float64 {
+	if x != nil {
+		return x.Statistic
+	}
+	return 0
+}
+
+func (x *ChangePoint) GetDegreesOfFreedom() float64 {
+	if x != nil {
+		return x.DegreesOfFreedom
+	}
+	return 0
+}
+
+func (x *ChangePoint) GetCritical() float64 {
+	if x != nil {
+		return x.Critical
+	}
+	return 0
+}
+
+func (x *ChangePoint) GetBefore() *Stats {
+	if x != nil {
+		return x.Before
+	}
+	return nil
+}
+
+func (x *ChangePoint) GetAfter() *Stats {
+	if x != nil {
+		return x.After
+	}
+	return nil
+}
+
+func (x *ChangePoint) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *ChangePoint) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *ChangePoint) GetKind() Kind {
+	if x != nil {
+		return x.Kind
+	}
+	return Kind_KIND_UNCLASSIFIED
+}
+
+// Sample is an item of one of many series, identified by key.
+type Sample struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Time          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	Value         float64                `protobuf:"fixed64,3,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Sample) Reset() {
+	*x = Sample{}
+	mi := &file_change_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Sample) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Sample) ProtoMessage() {}
+
+func (x *Sample) ProtoReflect() protoreflect.Message {
+	mi := &file_change_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Sample.ProtoReflect.Descriptor instead.
+func (*Sample) Descriptor() ([]byte, []int) {
+	return file_change_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Sample) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *Sample) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *Sample) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+// Event is a change point found in the series identified by key.
+type Event struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	ChangePoint   *ChangePoint           `protobuf:"bytes,2,opt,name=change_point,json=changePoint,proto3" json:"change_point,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_change_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_change_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_change_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Event) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *Event) GetChangePoint() *ChangePoint {
+	if x != nil {
+		return x.ChangePoint
+	}
+	return nil
+}
+
+var File_change_proto protoreflect.FileDescriptor
+
+const file_change_proto_rawDesc = "" +
+	"\n" +
+	"\fchange.proto\x12\tchange.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"X\n" +
+	"\aOptions\x12&\n" +
+	"\x0fmin_sample_size\x18\x01 \x01(\x05R\rminSampleSize\x12%\n" +
+	"\x0emin_confidence\x18\x02 \x01(\x01R\rminConfidence\"U\n" +
+	"\rDetectRequest\x12\x16\n" +
+	"\x06window\x18\x01 \x03(\x01R\x06window\x12,\n" +
+	"\aoptions\x18\x02 \x01(\v2\x12.change.v1.OptionsR\aoptions\"K\n" +
+	"\x0eDetectResponse\x129\n" +
+	"\fchange_point\x18\x01 \x01(\v2\x16.change.v1.ChangePointR\vchangePoint\"E\n" +
+	"\x05Stats\x12\f\n" +
+	"\x01n\x18\x01 \x01(\x03R\x01n\x12\x12\n" +
+	"\x04mean\x18\x02 \x01(\x01R\x04mean\x12\x1a\n" +
+	"\bvariance\x18\x03 \x01(\x01R\bvariance\"\x88\x03\n" +
+	"\vChangePoint\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x03R\x05index\x12\x1e\n" +
+	"\n" +
+	"difference\x18\x02 \x01(\x01R\n" +
+	"difference\x12\x1e\n" +
+	"\n" +
+	"confidence\x18\x03 \x01(\x01R\n" +
+	"confidence\x12\x1c\n" +
+	"\tstatistic\x18\x04 \x01(\x01R\tstatistic\x12,\n" +
+	"\x12degrees_of_freedom\x18\x05 \x01(\x01R\x10degreesOfFreedom\x12\x1a\n" +
+	"\bcritical\x18\x06 \x01(\x01R\bcritical\x12(\n" +
+	"\x06before\x18\a \x01(\v2\x10.change.v1.StatsR\x06before\x12&\n" +
+	"\x05after\x18\b \x01(\v2\x10.change.v1.StatsR\x05after\x12\x14\n" +
+	"\x05score\x18\t \x01(\x01R\x05score\x12.\n" +
+	"\x04time\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12#\n" +
+	"\x04kind\x18\v \x01(\x0e2\x0f.change.v1.KindR\x04kind\"`\n" +
+	"\x06Sample\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12.\n" +
+	"\x04time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\x01R\x05value\"T\n" +
+	"\x05Event\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x129\n" +
+	"\fchange_point\x18\x02 \x01(\v2\x16.change.v1.ChangePointR\vchangePoint*e\n" +
+	"\x04Kind\x12\x15\n" +
+	"\x11KIND_UNCLASSIFIED\x10\x00\x12\r\n" +
+	"\tKIND_STEP\x10\x01\x12\r\n" +
+	"\tKIND_RAMP\x10\x02\x12\x0e\n" +
+	"\n" +
+	"KIND_SPIKE\x10\x03\x12\x18\n" +
+	"\x14KIND_VARIANCE_CHANGE\x10\x042|\n" +
+	"\bDetector\x12=\n" +
+	"\x06Detect\x12\x18.change.v1.DetectRequest\x1a\x19.change.v1.DetectResponse\x121\n" +
+	"\x06Stream\x12\x11.change.v1.Sample\x1a\x10.change.v1.Event(\x010\x01B'Z%github.com/dgryski/go-change/changepbb\x06proto3"
+
+var (
+	file_change_proto_rawDescOnce sync.Once
+	file_change_proto_rawDescData []byte
+)
+
+func file_change_proto_rawDescGZIP() []byte {
+	file_change_proto_rawDescOnce.Do(func() {
+		file_change_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_change_proto_rawDesc), len(file_change_proto_rawDesc)))
+	})
+	return file_change_proto_rawDescData
+}
+
+var file_change_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_change_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_change_proto_goTypes = []any{
+	(Kind)(0),                     // 0: change.v1.Kind
+	(*Options)(nil),               // 1: change.v1.Options
+	(*DetectRequest)(nil),         // 2: change.v1.DetectRequest
+	(*DetectResponse)(nil),        // 3: change.v1.DetectResponse
+	(*Stats)(nil),                 // 4: change.v1.Stats
+	(*ChangePoint)(nil),           // 5: change.v1.ChangePoint
+	(*Sample)(nil),                // 6: change.v1.Sample
+	(*Event)(nil),                 // 7: change.v1.Event
+	(*timestamppb.Timestamp)(nil), // 8: google.protobuf.Timestamp
+}
+var file_change_proto_depIdxs = []int32{
+	1,  // 0: change.v1.DetectRequest.options:type_name -> change.v1.Options
+	5,  // 1: change.v1.DetectResponse.change_point:type_name -> change.v1.ChangePoint
+	4,  // 2: change.v1.ChangePoint.before:type_name -> change.v1.Stats
+	4,  // 3: change.v1.ChangePoint.after:type_name -> change.v1.Stats
+	8,  // 4: change.v1.ChangePoint.time:type_name -> google.protobuf.Timestamp
+	0,  // 5: change.v1.ChangePoint.kind:type_name -> change.v1.Kind
+	8,  // 6: change.v1.Sample.time:type_name -> google.protobuf.Timestamp
+	5,  // 7: change.v1.Event.change_point:type_name -> change.v1.ChangePoint
+	2,  // 8: change.v1.Detector.Detect:input_type -> change.v1.DetectRequest
+	6,  // 9: change.v1.Detector.Stream:input_type -> change.v1.Sample
+	3,  // 10: change.v1.Detector.Detect:output_type -> change.v1.DetectResponse
+	7,  // 11: change.v1.Detector.Stream:output_type -> change.v1.Event
+	10, // [10:12] is the sub-list for method output_type
+	8,  // [8:10] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_change_proto_init() }
+func file_change_proto_init() {
+	if File_change_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_change_proto_rawDesc), len(file_change_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_change_proto_goTypes,
+		DependencyIndexes: file_change_proto_depIdxs,
+		EnumInfos:         file_change_proto_enumTypes,
+		MessageInfos:      file_change_proto_msgTypes,
+	}.Build()
+	File_change_proto = out.File
+	file_change_proto_goTypes = nil
+	file_change_proto_depIdxs = nil
+}
@@ -0,0 +1,5 @@
+// Package changepb holds the protocol buffer messages and gRPC service of
+// the change point detector, generated from change.proto.
+package changepb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative change.proto
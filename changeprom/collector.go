@@ -0,0 +1,142 @@
+// Package changeprom connects the change point detector to Prometheus: it
+// exports the state of a Manager's detectors as metrics, and finds change
+// points in the series returned by range queries.
+package changeprom
+
+import (
+	"sync"
+	"time"
+
+	"github.com/dgryski/go-change"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Collector is a prometheus.Collector exposing, for each series of a
+// Manager, the number of change points found, and the means either side of
+// and the time of the latest one.  Items must be pushed through the
+// collector, or their events passed to Observe, for their change points to
+// be counted.
+//
+// The metrics, labelled by the series' key, are
+//
+//	<namespace>_change_points_total          the number of change points found
+//	<namespace>_change_before_mean           the mean before the latest change point
+//	<namespace>_change_after_mean            the mean after the latest change point
+//	<namespace>_change_last_timestamp_seconds the time of the latest change point
+//
+// where the time is that of the first item after the change, or the time it
+// was found for items pushed without one.  Only the count is exposed for a
+// series with no change points.
+type Collector struct {
+	m *change.Manager
+
+	mu     sync.Mutex
+	series map[string]*series
+
+	count, before, after, last *prometheus.Desc
+}
+
+// series is the latest change point of a series, and the number found
+type series struct {
+	count  int
+	before float64
+	after  float64
+	last   time.Time
+}
+
+// NewCollector returns a collector for the series of m, with metric names
+// prefixed by namespace, if it is not empty
+func NewCollector(m *change.Manager, namespace string) *Collector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "change", name), help, []string{"key"}, nil)
+	}
+
+	return &Collector{
+		m:      m,
+		series: make(map[string]*series),
+		count:  desc("points_total", "Number of change points found."),
+		before: desc("before_mean", "Mean of the items before the latest change point."),
+		after:  desc("after_mean", "Mean of the items after the latest change point."),
+		last:   desc("last_timestamp_seconds", "Time of the latest change point."),
+	}
+}
+
+// Push is Manager.Push, recording any event
+func (c *Collector) Push(key string, item float64) *change.Event {
+	ev := c.m.Push(key, item)
+	c.Observe(ev)
+	return ev
+}
+
+// PushAt is Manager.PushAt, recording any event
+func (c *Collector) PushAt(key string, t time.Time, item float64) *change.Event {
+	ev := c.m.PushAt(key, t, item)
+	c.Observe(ev)
+	return ev
+}
+
+// Observe records an event found by the manager, such as one received from
+// Manager.Watch.  A nil event is ignored.
+func (c *Collector) Observe(ev *change.Event) {
+	if ev == nil {
+		return
+	}
+
+	cp := ev.ChangePoint
+	last := cp.Time
+	if last.IsZero() {
+		last = time.Now()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := c.series[ev.Key]
+	if s == nil {
+		s = &series{}
+		c.series[ev.Key] = s
+	}
+	s.count++
+	s.before = cp.Before.Mean()
+	s.after = cp.After.Mean()
+	s.last = last
+}
+
+// Describe implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.count
+	ch <- c.before
+	ch <- c.after
+	ch <- c.last
+}
+
+// Collect implements prometheus.Collector.  Series removed from the manager
+// are no longer exposed.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	keys := c.m.Keys()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	live := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		live[key] = true
+
+		s := c.series[key]
+		if s == nil {
+			ch <- prometheus.MustNewConstMetric(c.count, prometheus.CounterValue, 0, key)
+			continue
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.count, prometheus.CounterValue, float64(s.count), key)
+		ch <- prometheus.MustNewConstMetric(c.before, prometheus.GaugeValue, s.before, key)
+		ch <- prometheus.MustNewConstMetric(c.after, prometheus.GaugeValue, s.after, key)
+		ch <- prometheus.MustNewConstMetric(c.last, prometheus.GaugeValue, float64(s.last.UnixNano())/1e9, key)
+	}
+
+	for key := range c.series {
+		if !live[key] {
+			delete(c.series, key)
+		}
+	}
+}
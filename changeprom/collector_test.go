@@ -0,0 +1,70 @@
+package changeprom
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dgryski/go-change"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCollector(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	m := change.NewManager(func(string) *change.Rolling {
+		r := change.NewRolling(change.New(30, 0.99999), 100)
+		r.Cooldown = 1000
+		return r
+	})
+	c := NewCollector(m, "test")
+
+	start := time.Unix(1700000000, 0)
+	var found *change.Event
+	for i := 0; i < 400; i++ {
+		step := rnd.NormFloat64()
+		if i >= 200 {
+			step += 5
+		}
+		if ev := c.PushAt("step", start.Add(time.Duration(i)*time.Second), step); ev != nil {
+			found = ev
+		}
+		c.Push("flat", rnd.NormFloat64())
+	}
+
+	if found == nil {
+		t.Fatalf("Push found no change point")
+	}
+	cp := found.ChangePoint
+
+	want := `
+# HELP test_change_after_mean Mean of the items after the latest change point.
+# TYPE test_change_after_mean gauge
+test_change_after_mean{key="step"} ` + ftoa(cp.After.Mean()) + `
+# HELP test_change_before_mean Mean of the items before the latest change point.
+# TYPE test_change_before_mean gauge
+test_change_before_mean{key="step"} ` + ftoa(cp.Before.Mean()) + `
+# HELP test_change_last_timestamp_seconds Time of the latest change point.
+# TYPE test_change_last_timestamp_seconds gauge
+test_change_last_timestamp_seconds{key="step"} ` + ftoa(float64(cp.Time.Unix())) + `
+# HELP test_change_points_total Number of change points found.
+# TYPE test_change_points_total counter
+test_change_points_total{key="flat"} 0
+test_change_points_total{key="step"} 1
+`
+	if err := testutil.CollectAndCompare(c, strings.NewReader(want)); err != nil {
+		t.Errorf("Collect: %v", err)
+	}
+
+	m.Remove("step")
+	if n := testutil.CollectAndCount(c); n != 1 {
+		t.Errorf("Collect after Remove(step)=%d metrics, wanted 1", n)
+	}
+}
+
+// ftoa formats v as the Prometheus text format does
+func ftoa(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,153 @@
+package changeprom
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dgryski/go-change"
+)
+
+// Client queries the HTTP API of a Prometheus server
+type Client struct {
+	// URL is the address of the server, such as http://localhost:9090
+	URL string
+
+	// HTTPClient makes the requests.  If nil, http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+// Series is one series of the result of a range query, and the change
+// points found in it
+type Series struct {
+	Labels map[string]string
+	Times  []time.Time
+	Values []float64
+
+	// ChangePoints are the change points found by segmenting Values, with
+	// Time set to the time of the first sample after the change
+	ChangePoints []*change.ChangePoint
+}
+
+// Detect runs the PromQL range query from start to end with the given step,
+// and segments each series of the result with d.  Stale or NaN samples are
+// handled by d's Missing policy.
+func (c *Client) Detect(ctx context.Context, d *change.Detector, query string, start, end time.Time, step time.Duration) ([]Series, error) {
+	series, err := c.QueryRange(ctx, query, start, end, step)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range series {
+		s := &series[i]
+		s.ChangePoints = d.Segment(s.Values)
+		for _, cp := range s.ChangePoints {
+			cp.Time = s.Times[cp.Index]
+		}
+	}
+
+	return series, nil
+}
+
+type apiResponse struct {
+	Status    string `json:"status"`
+	ErrorType string `json:"errorType"`
+	Error     string `json:"error"`
+	Data      struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Metric map[string]string    `json:"metric"`
+			Values [][2]json.RawMessage `json:"values"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// QueryRange runs the PromQL range query from start to end with the given step
+func (c *Client) QueryRange(ctx context.Context, query string, start, end time.Time, step time.Duration) ([]Series, error) {
+	params := url.Values{
+		"query": {query},
+		"start": {formatTime(start)},
+		"end":   {formatTime(end)},
+		"step":  {strconv.FormatFloat(step.Seconds(), 'f', -1, 64)},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(c.URL, "/")+"/api/v1/query_range", strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var r apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, fmt.Errorf("changeprom: %s: bad response: %w", resp.Status, err)
+	}
+	if r.Status != "success" {
+		return nil, fmt.Errorf("changeprom: query failed: %s: %s", r.ErrorType, r.Error)
+	}
+	if r.Data.ResultType != "matrix" {
+		return nil, fmt.Errorf("changeprom: unexpected result type %q", r.Data.ResultType)
+	}
+
+	series := make([]Series, len(r.Data.Result))
+	for i, res := range r.Data.Result {
+		s := Series{
+			Labels: res.Metric,
+			Times:  make([]time.Time, len(res.Values)),
+			Values: make([]float64, len(res.Values)),
+		}
+
+		for j, v := range res.Values {
+			t, err := parseTime(v[0])
+			if err != nil {
+				return nil, err
+			}
+
+			// sample values are strings, so as to hold NaN and Inf
+			var str string
+			if err := json.Unmarshal(v[1], &str); err != nil {
+				return nil, fmt.Errorf("changeprom: bad sample value %s", v[1])
+			}
+			f, err := strconv.ParseFloat(str, 64)
+			if err != nil {
+				return nil, fmt.Errorf("changeprom: bad sample value %q", str)
+			}
+
+			s.Times[j], s.Values[j] = t, f
+		}
+
+		series[i] = s
+	}
+
+	return series, nil
+}
+
+// formatTime returns t as Unix seconds
+func formatTime(t time.Time) string {
+	return strconv.FormatFloat(float64(t.UnixNano())/1e9, 'f', -1, 64)
+}
+
+// parseTime parses a sample time, which is a number of Unix seconds
+func parseTime(data json.RawMessage) (time.Time, error) {
+	var secs float64
+	if err := json.Unmarshal(data, &secs); err != nil {
+		return time.Time{}, fmt.Errorf("changeprom: bad sample time %s", data)
+	}
+	return time.UnixMilli(int64(math.Round(secs * 1000))), nil
+}
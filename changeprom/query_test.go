@@ -0,0 +1,62 @@
+package changeprom
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dgryski/go-change"
+)
+
+func TestDetect(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	start := time.Unix(1700000000, 0)
+	step := 15 * time.Second
+
+	// a step in latency at the 100th sample
+	var values []string
+	for i := 0; i < 200; i++ {
+		v := 0.1 + 0.01*rnd.NormFloat64()
+		if i >= 100 {
+			v += 0.05
+		}
+		values = append(values, fmt.Sprintf(`[%.3f,"%v"]`, float64(start.Add(time.Duration(i)*step).UnixMilli())/1000, v))
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/query_range" || r.FormValue("step") != "15" || r.FormValue("start") != "1700000000" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"status":"error","errorType":"bad_data","error":"unexpected %s %v"}`, r.URL.Path, r.Form)
+			return
+		}
+		fmt.Fprintf(w, `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"job":"api"},"values":[%s]}]}}`, strings.Join(values, ","))
+	}))
+	defer srv.Close()
+
+	c := &Client{URL: srv.URL + "/"}
+	d := change.New(30, 0.999)
+
+	series, err := c.Detect(context.Background(), d, "latency", start, start.Add(199*step), step)
+	if err != nil {
+		t.Fatalf("Detect()=%v", err)
+	}
+
+	if len(series) != 1 || series[0].Labels["job"] != "api" || len(series[0].Values) != 200 {
+		t.Fatalf("Detect()=%+v, wanted one series of 200 samples", series)
+	}
+
+	cps := series[0].ChangePoints
+	if len(cps) != 1 || cps[0].Index != 100 || !cps[0].Time.Equal(start.Add(100*step)) {
+		t.Errorf("Detect() change points=%+v, wanted one at 100", cps)
+	}
+
+	if _, err := c.Detect(context.Background(), d, "latency", start.Add(time.Second), start, step); err == nil || !strings.Contains(err.Error(), "bad_data") {
+		t.Errorf("Detect(bad request)=%v, wanted the server's error", err)
+	}
+}
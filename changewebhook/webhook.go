@@ -0,0 +1,112 @@
+// Package changewebhook is a change.Sink which posts change events as JSON
+// to a webhook.
+package changewebhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/dgryski/go-change"
+)
+
+// Webhook posts each change event to URL as a JSON object
+//
+//	{"key": "cpu", "change_point": {...}}
+//
+// with the change point encoded as described by change.JSONVersion.
+// Requests which fail with a network error, a 5xx status or 429 Too Many
+// Requests are retried, with the delay doubling after each attempt; other
+// statuses outside 2xx are errors.
+type Webhook struct {
+	// URL is the address posted to
+	URL string
+
+	// Header holds extra headers for each request, such as Authorization
+	Header http.Header
+
+	// Attempts is the number of times each request is tried.  Zero means 3.
+	Attempts int
+
+	// Backoff is the delay before the first retry.  Zero means one second.
+	Backoff time.Duration
+
+	// HTTPClient makes the requests.  If nil, http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+var _ change.Sink = (*Webhook)(nil)
+
+type payload struct {
+	Key         string              `json:"key"`
+	ChangePoint *change.ChangePoint `json:"change_point"`
+}
+
+// Notify posts ev, retrying as described for Webhook.  It gives up when ctx
+// is done.
+func (w *Webhook) Notify(ctx context.Context, ev change.Event) error {
+	body, err := json.Marshal(payload{Key: ev.Key, ChangePoint: ev.ChangePoint})
+	if err != nil {
+		return err
+	}
+
+	attempts := w.Attempts
+	if attempts == 0 {
+		attempts = 3
+	}
+
+	backoff := w.Backoff
+	if backoff == 0 {
+		backoff = time.Second
+	}
+
+	for i := 1; ; i++ {
+		retry, err := w.post(ctx, body)
+		if err == nil || !retry || i >= attempts {
+			return err
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return fmt.Errorf("%w (after %v)", ctx.Err(), err)
+		}
+		backoff *= 2
+	}
+}
+
+// post makes one request, and reports whether it is worth retrying if it fails
+func (w *Webhook) post(ctx context.Context, body []byte) (retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	for k, v := range w.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	hc := w.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 == 2 {
+		io.Copy(io.Discard, resp.Body)
+		return false, nil
+	}
+
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	retry = resp.StatusCode/100 == 5 || resp.StatusCode == http.StatusTooManyRequests
+	return retry, fmt.Errorf("changewebhook: %s: %s", resp.Status, bytes.TrimSpace(msg))
+}
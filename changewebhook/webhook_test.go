@@ -0,0 +1,69 @@
+package changewebhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dgryski/go-change"
+)
+
+func TestWebhook(t *testing.T) {
+	var tests = []struct {
+		statuses []int // of each attempt
+		attempts int
+		ok       bool
+	}{
+		{[]int{200}, 1, true},
+		{[]int{503, 429, 204}, 3, true},
+		{[]int{500, 500, 500, 200}, 3, false},
+		{[]int{400, 200}, 1, false},
+	}
+
+	ev := change.Event{
+		Key: "cpu",
+		ChangePoint: &change.ChangePoint{
+			Index:  100,
+			Before: change.NewStats(100, 0.25, 0.01),
+			After:  change.NewStats(100, 0.75, 0.01),
+		},
+	}
+
+	for _, tt := range tests {
+		var attempts int
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var got payload
+			if err := json.NewDecoder(r.Body).Decode(&got); err != nil || got.Key != "cpu" || got.ChangePoint.Index != 100 || r.Header.Get("X-Token") != "t" {
+				t.Errorf("Notify() posted %+v with %v, wanted the event: %v", got, r.Header, err)
+			}
+			w.WriteHeader(tt.statuses[attempts])
+			attempts++
+		}))
+
+		wh := &Webhook{URL: srv.URL, Header: http.Header{"X-Token": {"t"}}, Backoff: time.Millisecond}
+		err := wh.Notify(context.Background(), ev)
+		srv.Close()
+
+		if (err == nil) != tt.ok || attempts != tt.attempts {
+			t.Errorf("Notify(%v)=%v after %d attempts, wanted ok=%v after %d", tt.statuses, err, attempts, tt.ok, tt.attempts)
+		}
+	}
+}
+
+func TestWebhookCancel(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	wh := &Webhook{URL: srv.URL, Attempts: 100, Backoff: time.Hour}
+	if err := wh.Notify(ctx, change.Event{ChangePoint: &change.ChangePoint{}}); err == nil || ctx.Err() == nil {
+		t.Errorf("Notify()=%v, wanted to give up when the context is done", err)
+	}
+}
@@ -0,0 +1,88 @@
+package change
+
+import "math"
+
+// gammainc returns the regularized lower incomplete gamma function P(a, x)
+func gammainc(a, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if math.IsInf(x, 1) {
+		return 1
+	}
+
+	lga, _ := math.Lgamma(a)
+
+	if x < a+1 {
+		// series representation
+		ap := a
+		sum := 1 / a
+		del := sum
+		for i := 0; i < 1000; i++ {
+			ap++
+			del *= x / ap
+			sum += del
+			if math.Abs(del) < math.Abs(sum)*3e-16 {
+				break
+			}
+		}
+		return sum * math.Exp(-x+a*math.Log(x)-lga)
+	}
+
+	// continued fraction for the upper function, by the modified Lentz's method
+	const fpmin = 1e-300
+	b := x + 1 - a
+	c := 1 / fpmin
+	d := 1 / b
+	h := d
+	for i := 1; i < 1000; i++ {
+		an := -float64(i) * (float64(i) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < fpmin {
+			d = fpmin
+		}
+		c = b + an/c
+		if math.Abs(c) < fpmin {
+			c = fpmin
+		}
+		d = 1 / d
+		del := d * c
+		h *= del
+		if math.Abs(del-1) < 3e-16 {
+			break
+		}
+	}
+	return 1 - math.Exp(-x+a*math.Log(x)-lga)*h
+}
+
+// chiSquareCDF returns the cumulative distribution function of the chi-square distribution with df degrees of freedom
+func chiSquareCDF(x, df float64) float64 {
+	return gammainc(df/2, x/2)
+}
+
+// chiSquareQuantile returns the value x such that chiSquareCDF(x, df) == p
+func chiSquareQuantile(p, df float64) float64 {
+	if p <= 0 {
+		return 0
+	}
+	if p >= 1 {
+		return math.Inf(1)
+	}
+
+	lo, hi := 0.0, df+1
+	for chiSquareCDF(hi, df) < p {
+		lo, hi = hi, hi*2
+	}
+
+	for i := 0; i < 200 && hi-lo > 1e-12*hi; i++ {
+		mid := (lo + hi) / 2
+		if chiSquareCDF(mid, df) < p {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+
+	return (lo + hi) / 2
+}
@@ -0,0 +1,166 @@
+package change
+
+import "math"
+
+// Kind is the kind of a change, as labelled by Classify
+type Kind int
+
+const (
+	// Unclassified is a change point which has not been classified
+	Unclassified Kind = iota
+
+	// Step is a persistent shift in the level
+	Step
+
+	// Ramp is a change in the trend, a gradual rather than sudden shift
+	Ramp
+
+	// Spike is a transient excursion, after which the level returns
+	Spike
+
+	// VarianceChange is a change in the spread of the items, but not their level
+	VarianceChange
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Unclassified:
+		return "unclassified"
+	case Step:
+		return "step"
+	case Ramp:
+		return "ramp"
+	case Spike:
+		return "spike"
+	case VarianceChange:
+		return "variance"
+	}
+	return "unknown"
+}
+
+// Classify labels the change point cp found in window as a Step, Ramp, Spike
+// or VarianceChange, and sets its Kind.  A simple normal model of each kind of
+// change is fitted to the window, and the one with the lowest BIC is chosen:
+//
+//   - a step is a different mean either side of the change point
+//   - a ramp is a line through the window, or a level which starts to trend
+//     part way through it
+//   - a spike is an excursion from a common level either side of it, which
+//     ends at the best split of the rest of the window on one side of the
+//     change point
+//   - a variance change is a common mean with a different variance either
+//     side of the change point
+//
+// Alerts can then treat a transient spike differently from a level shift.
+func (d *Detector) Classify(window []float64, cp *ChangePoint) Kind {
+	n := len(window)
+	c := cp.Index
+	if c <= 0 || c >= n {
+		return Unclassified
+	}
+
+	nf := float64(n)
+	cumsum, cumsumsq := prefixSums(window, origin(window))
+
+	// sse returns the sum of squared deviations of the items in the ranges from their common mean
+	sse := func(ranges ...[2]int) float64 {
+		var sum, sumsq, m float64
+		for _, r := range ranges {
+			sum += cumsum[r[1]] - cumsum[r[0]]
+			sumsq += cumsumsq[r[1]] - cumsumsq[r[0]]
+			m += float64(r[1] - r[0])
+		}
+		return math.Max(0, sumsq-sum*sum/m)
+	}
+
+	// bic returns the BIC of a normal model with a common variance and k
+	// parameters other than it, with the given residual sum of squares
+	bic := func(rss float64, k int) float64 {
+		return nf*math.Log(math.Max(rss, normalMinVariance)/nf) + float64(k+1)*math.Log(nf)
+	}
+
+	// the means and the change point
+	best, kind := bic(sse([2]int{0, c})+sse([2]int{c, n}), 3), Step
+
+	// the intercept and slope, or the level, slope, and the start of the
+	// trend, which needn't be at the change point: the best split of a ramp
+	// is part way along it
+	ramp := bic(Linear{}.Fit(window)(0, n), 2)
+	for h, step := 0, classifyHingeStep(n); h < n; h += step {
+		ramp = math.Min(ramp, bic(hingeRSS(window, h), 3))
+	}
+	if ramp < best {
+		best, kind = ramp, Ramp
+	}
+
+	// the two levels and the two ends of the excursion
+	for _, other := range []int{c + d.scan(window[c:]).index, d.scan(window[:c]).index} {
+		if other == c || other == 0 {
+			continue
+		}
+
+		lo, hi := c, other
+		if other < c {
+			lo, hi = other, c
+		}
+
+		spike := bic(sse([2]int{0, lo}, [2]int{hi, n})+sse([2]int{lo, hi}), 4)
+		if spike < best {
+			best, kind = spike, Spike
+		}
+	}
+
+	// the common mean, the two variances and the change point, which isn't
+	// the common variance model used by bic
+	mean := (cumsum[n] - cumsum[0]) / nf
+	dev := func(i, j int) float64 {
+		m := float64(j - i)
+		sum := cumsum[j] - cumsum[i] - m*mean
+		return math.Max(sse([2]int{i, j})+sum*sum/m, normalMinVariance*m) / m
+	}
+	n1, n2 := float64(c), float64(n-c)
+	variance := n1*math.Log(dev(0, c)) + n2*math.Log(dev(c, n)) + 4*math.Log(nf)
+	if variance < best {
+		kind = VarianceChange
+	}
+
+	cp.Kind = kind
+	return kind
+}
+
+// classifyHingeStep returns the spacing of the starts of trends tried by
+// Classify, so that at most about 64 are tried
+func classifyHingeStep(n int) int {
+	if n <= 64 {
+		return 1
+	}
+	return n / 64
+}
+
+// hingeRSS returns the residual sum of squares of the least-squares fit to
+// series of a level which starts to change linearly at offset c
+func hingeRSS(series []float64, c int) float64 {
+	shift := origin(series)
+
+	var sz, szz, sy, syy, szy float64
+	for k, y := range series {
+		y -= shift
+		z := math.Max(0, float64(k-c))
+		sz += z
+		szz += z * z
+		sy += y
+		syy += y * y
+		szy += z * y
+	}
+
+	m := float64(len(series))
+	czz := szz - sz*sz/m
+	cyy := syy - sy*sy/m
+	czy := szy - sz*sy/m
+
+	if czz <= 0 {
+		return cyy
+	}
+
+	return math.Max(0, cyy-czy*czy/czz)
+}
@@ -0,0 +1,54 @@
+package change
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	step := steps(rnd, 100, 0, 3)
+
+	ramp := steps(rnd, 100, 0, 0)
+	for i := 100; i < len(ramp); i++ {
+		ramp[i] += 0.05 * float64(i-100)
+	}
+
+	spike := steps(rnd, 100, 0, 0)
+	for i := 100; i < 110; i++ {
+		spike[i] += 8
+	}
+
+	variance := steps(rnd, 100, 0, 0)
+	for i := 100; i < len(variance); i++ {
+		variance[i] *= 4
+	}
+
+	var tests = []struct {
+		w    []float64
+		d    *Detector
+		want Kind
+	}{
+		{step, New(30, 0.99), Step},
+		{ramp, New(30, 0.99), Ramp},
+		{spike, New(5, 0.99), Spike},
+		{variance, &Detector{MinSampleSize: 30, MinConfidence: 0.99, Test: KolmogorovSmirnov{}, Cost: Normal{}}, VarianceChange},
+	}
+
+	for _, tt := range tests {
+		cp := tt.d.Check(tt.w)
+		if cp == nil {
+			t.Errorf("Check(%v) found no change point", tt.want)
+			continue
+		}
+
+		if got := tt.d.Classify(tt.w, cp); got != tt.want || cp.Kind != tt.want {
+			t.Errorf("Classify(%v at %d)=%v, Kind=%v", tt.want, cp.Index, got, cp.Kind)
+		}
+	}
+
+	if got := New(30, 0.99).Classify(step, &ChangePoint{}); got != Unclassified {
+		t.Errorf("Classify(index 0)=%v, wanted %v", got, Unclassified)
+	}
+}
@@ -0,0 +1,223 @@
+// Command change finds change points in a series of numbers.
+//
+// The series is read from a CSV file, or from standard input, one number per
+// line or one column of CSV records:
+//
+//	change -c 0.999 -col 2 -header latency.csv
+//	seq 1 100 | change -w 50
+//
+// By default the whole series is segmented by binary segmentation.  With -w,
+// a window of that many items slides along the series, and each change is
+// reported once.  Each change point is printed with the statistics of the
+// items either side of it, or as JSON with -json.
+//
+// With -follow, the window slides along standard input until it is closed,
+// and each change is printed as soon as it is found, with the time the first
+// item after it was read:
+//
+//	tail -f requests.log | awk '{ print $5 }' | change -follow -w 300
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dgryski/go-change"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("change: ")
+
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+		log.Fatal(err)
+	}
+}
+
+// run runs the command with the given arguments, reading the series from
+// stdin if no file is named
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("change", flag.ContinueOnError)
+	windowSize := fs.Int("w", 0, "window size; 0 segments the whole series")
+	minSample := fs.Int("ms", 0, "min sample size (0 for the detector's default)")
+	confidence := fs.Float64("c", 0.99, "minimum confidence level")
+	column := fs.Int("col", 0, "CSV column holding the series, from 0")
+	header := fs.Bool("header", false, "skip the first CSV record")
+	asJSON := fs.Bool("json", false, "print change points as JSON, one per line")
+	follow := fs.Bool("follow", false, "print changes as they are found in a window of -w items, timestamped with the time items are read")
+
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: change [flags] [file]\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	in := stdin
+	switch fs.NArg() {
+	case 0:
+	case 1:
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	default:
+		fs.Usage()
+		return flag.ErrHelp
+	}
+
+	dec := change.NewDecoder(in, change.CSV)
+	dec.Column = *column
+	dec.Header = *header
+
+	d := change.New(*minSample, *confidence)
+	if err := d.Validate(nil); errors.Is(err, change.ErrConfig) {
+		return err
+	}
+
+	if *follow {
+		if *windowSize == 0 {
+			return errors.New("-follow needs a window size, -w")
+		}
+		if err := d.Validate(make([]float64, *windowSize)); err != nil {
+			return err
+		}
+		return followRolling(d, *windowSize, dec, stdout, *asJSON)
+	}
+
+	p := newPrinter(stdout, *asJSON)
+
+	if *windowSize > 0 {
+		// a window of zeros is too short exactly when the real ones would be
+		if err := d.Validate(make([]float64, *windowSize)); err != nil {
+			return err
+		}
+
+		r := change.NewRolling(d, *windowSize)
+		r.Cooldown = *windowSize
+
+		var perr error
+		err := r.Feed(dec, func(cp *change.ChangePoint) {
+			if perr == nil {
+				perr = p.print(cp)
+			}
+		})
+		if err != nil {
+			return err
+		}
+		if perr != nil {
+			return perr
+		}
+		return p.flush()
+	}
+
+	var series []float64
+	for {
+		v, err := dec.Decode()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		series = append(series, v)
+	}
+
+	for _, cp := range d.Segment(series) {
+		if err := p.print(cp); err != nil {
+			return err
+		}
+	}
+	return p.flush()
+}
+
+// now returns the time an item is read
+var now = time.Now
+
+// followRolling pushes the items of dec to a rolling detector as they are
+// read, and writes each change point as soon as it is found.  Records which
+// don't hold an item are logged and skipped.
+func followRolling(d *change.Detector, windowSize int, dec *change.Decoder, w io.Writer, asJSON bool) error {
+	r := change.NewRolling(d, windowSize)
+	r.Cooldown = windowSize
+
+	enc := json.NewEncoder(w)
+
+	for {
+		v, err := dec.Decode()
+		if err == io.EOF {
+			return nil
+		}
+		if errors.Is(err, change.ErrBadRecord) {
+			log.Print(err)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		cp := r.PushAt(now(), v)
+		if cp == nil {
+			continue
+		}
+
+		if asJSON {
+			err = enc.Encode(cp)
+		} else {
+			_, err = fmt.Fprintf(w, "%s change at %d: difference=%.4g confidence=%.4f mean %.4g -> %.4g\n",
+				cp.Time.Format(time.RFC3339), cp.Index, cp.Difference, cp.Confidence, cp.Before.Mean(), cp.After.Mean())
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// printer writes change points as aligned columns or JSON lines
+type printer struct {
+	tw  *tabwriter.Writer
+	enc *json.Encoder
+}
+
+func newPrinter(w io.Writer, asJSON bool) *printer {
+	if asJSON {
+		return &printer{enc: json.NewEncoder(w)}
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "index\tdifference\tconfidence\tstatistic\tn before\tmean before\tsd before\tn after\tmean after\tsd after\t")
+	return &printer{tw: tw}
+}
+
+func (p *printer) print(cp *change.ChangePoint) error {
+	if p.enc != nil {
+		return p.enc.Encode(cp)
+	}
+
+	_, err := fmt.Fprintf(p.tw, "%d\t%.4g\t%.4f\t%.4g\t%d\t%.4g\t%.4g\t%d\t%.4g\t%.4g\t\n",
+		cp.Index, cp.Difference, cp.Confidence, cp.Statistic,
+		cp.Before.Len(), cp.Before.Mean(), cp.Before.Stddev(),
+		cp.After.Len(), cp.After.Mean(), cp.After.Stddev())
+	return err
+}
+
+func (p *printer) flush() error {
+	if p.tw == nil {
+		return nil
+	}
+	return p.tw.Flush()
+}
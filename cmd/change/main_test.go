@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dgryski/go-change"
+)
+
+// series returns lines of normal noise, 200 items around 0 then 200 around 5
+func series(rnd *rand.Rand, format string) string {
+	var sb strings.Builder
+	for i := 0; i < 400; i++ {
+		v := rnd.NormFloat64()
+		if i >= 200 {
+			v += 5
+		}
+		fmt.Fprintf(&sb, format, i, v)
+	}
+	return sb.String()
+}
+
+func TestRun(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	lines := series(rnd, "%[2]v\n")
+	csv := "i,v\n" + series(rnd, "%d,%v\n")
+
+	var tests = []struct {
+		args  []string
+		input string
+	}{
+		{nil, lines},
+		{[]string{"-w", "100", "-c", "0.99999"}, lines},
+		{[]string{"-col", "1", "-header"}, csv},
+	}
+
+	for _, tt := range tests {
+		var out strings.Builder
+		if err := run(append(tt.args, "-json"), strings.NewReader(tt.input), &out); err != nil {
+			t.Errorf("run(%q)=%v", tt.args, err)
+			continue
+		}
+
+		var cps []change.ChangePoint
+		for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+			var cp change.ChangePoint
+			if err := json.Unmarshal([]byte(line), &cp); err != nil {
+				t.Fatalf("run(%q) printed %q: %v", tt.args, line, err)
+			}
+			cps = append(cps, cp)
+		}
+
+		if len(cps) != 1 || cps[0].Index < 180 || cps[0].Index > 210 {
+			t.Errorf("run(%q)=%+v, wanted one change point at 200", tt.args, cps)
+		}
+	}
+}
+
+func TestRunTable(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	var out strings.Builder
+	if err := run(nil, strings.NewReader(series(rnd, "%[2]v\n")), &out); err != nil {
+		t.Fatalf("run()=%v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "mean before") || !strings.HasPrefix(strings.TrimSpace(lines[1]), "20") {
+		t.Errorf("run()=\n%s\nwanted a header and one change point", out.String())
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	var tests = [][]string{
+		{"-w", "10", "-ms", "30"}, // window too short
+		{"-c", "1"},
+		{"-col", "3"},
+		{"a", "b"},
+		{"no-such-file"},
+	}
+
+	for _, args := range tests {
+		if err := run(args, strings.NewReader("1,2\n3,4\n"), io.Discard); err == nil {
+			t.Errorf("run(%q)=nil, wanted an error", args)
+		}
+	}
+}
+
+func TestRunFollow(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	var items int
+	now = func() time.Time {
+		items++
+		return start.Add(time.Duration(items-1) * time.Second)
+	}
+	defer func() { now = time.Now }()
+
+	// lines which aren't a number, are missing the column or have a stray
+	// quote are skipped
+	input := "0,x\n1\n2,3\"\n" + series(rnd, "%v,%v\n")
+
+	pr, pw := io.Pipe()
+	go func() {
+		io.WriteString(pw, input)
+		pw.Close()
+	}()
+
+	var out strings.Builder
+	if err := run([]string{"-follow", "-col", "1", "-w", "100", "-ms", "30", "-c", "0.99999"}, pr, &out); err != nil {
+		t.Fatalf("run(-follow)=%v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("run(-follow)=%q, wanted one change", out.String())
+	}
+
+	var stamp string
+	var index int
+	if _, err := fmt.Sscanf(lines[0], "%s change at %d:", &stamp, &index); err != nil {
+		t.Fatalf("run(-follow)=%q: %v", lines[0], err)
+	}
+	at, _ := time.Parse(time.RFC3339, stamp)
+	if index < 180 || index > 210 || !at.Equal(start.Add(time.Duration(index)*time.Second)) {
+		t.Errorf("run(-follow)=%q, wanted a change at 200 timestamped with its item", lines[0])
+	}
+
+	if err := run([]string{"-follow"}, strings.NewReader(""), io.Discard); err == nil {
+		t.Errorf("run(-follow) without -w=nil, wanted an error")
+	}
+}
@@ -0,0 +1,106 @@
+package change
+
+import "math"
+
+// CorrelationChangePoint is a potential change point found by CheckCorrelation().
+type CorrelationChangePoint struct {
+	// Index is the offset into the data sets of the suspected change point
+	Index int
+
+	// Difference is the change in the correlation coefficient
+	Difference float64
+
+	// Confidence is the confidence returned by the Fisher z-transform test
+	Confidence float64
+
+	// Statistic is the z statistic of the difference in the transformed correlations
+	Statistic float64
+
+	// Before is the Pearson correlation coefficient before the change point
+	Before float64
+
+	// After is the Pearson correlation coefficient after the change point
+	After float64
+}
+
+// CheckCorrelation returns a potential change point in the correlation
+// between two aligned series, such as when two previously coupled metrics
+// decouple.  The split is chosen where the correlations on either side are
+// most significantly different, using Fisher's z-transform, and the
+// confidence is from the same test.  The detector's Test and Cost are not
+// used.
+func (d *Detector) CheckCorrelation(xs, ys []float64) *CorrelationChangePoint {
+	n := len(xs)
+	if len(ys) < n {
+		n = len(ys)
+	}
+
+	minSampleSize := d.minSampleSize()
+
+	// the variance of the transform is 1/(n-3)
+	if minSampleSize < 4 {
+		minSampleSize = 4
+	}
+
+	sx := make([]float64, n+1)
+	sy := make([]float64, n+1)
+	sxx := make([]float64, n+1)
+	syy := make([]float64, n+1)
+	sxy := make([]float64, n+1)
+	for i := 0; i < n; i++ {
+		x, y := xs[i], ys[i]
+		sx[i+1] = sx[i] + x
+		sy[i+1] = sy[i] + y
+		sxx[i+1] = sxx[i] + x*x
+		syy[i+1] = syy[i] + y*y
+		sxy[i+1] = sxy[i] + x*y
+	}
+
+	corr := func(i, j int) float64 {
+		m := float64(j - i)
+		x, y := sx[j]-sx[i], sy[j]-sy[i]
+		cxx := (sxx[j] - sxx[i]) - x*x/m
+		cyy := (syy[j] - syy[i]) - y*y/m
+		cxy := (sxy[j] - sxy[i]) - x*y/m
+		if cxx <= 0 || cyy <= 0 {
+			return 0
+		}
+		return math.Max(-1, math.Min(1, cxy/math.Sqrt(cxx*cyy)))
+	}
+
+	var best *CorrelationChangePoint
+
+	for l := minSampleSize; l < (n - minSampleSize + 1); l++ {
+		r1, r2 := corr(0, l), corr(l, n)
+		z := (fisherZ(r2) - fisherZ(r1)) / math.Sqrt(1/float64(l-3)+1/float64(n-l-3))
+
+		if best == nil || math.Abs(z) > math.Abs(best.Statistic) {
+			best = &CorrelationChangePoint{
+				Index:      l,
+				Difference: r2 - r1,
+				Statistic:  z,
+				Before:     r1,
+				After:      r2,
+			}
+		}
+	}
+
+	if best == nil {
+		return nil
+	}
+
+	best.Confidence = math.Erf(math.Abs(best.Statistic) / math.Sqrt2)
+
+	// not above our threshold
+	if best.Confidence <= d.MinConfidence {
+		return nil
+	}
+
+	return best
+}
+
+// fisherZ returns Fisher's z-transform of the correlation coefficient r, clamped to keep it finite
+func fisherZ(r float64) float64 {
+	const max = 1 - 1e-12
+	return math.Atanh(math.Max(-max, math.Min(max, r)))
+}
@@ -0,0 +1,37 @@
+package change
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestCheckCorrelation(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// two metrics which track each other until they decouple at 150
+	var xs, ys []float64
+	for i := 0; i < 300; i++ {
+		x := rnd.NormFloat64()
+		y := x + 0.3*rnd.NormFloat64()
+		if i >= 150 {
+			y = rnd.NormFloat64()
+		}
+		xs = append(xs, x)
+		ys = append(ys, y)
+	}
+
+	d := New(20, 0.999)
+
+	r := d.CheckCorrelation(xs, ys)
+	if r == nil || r.Index < 140 || r.Index > 160 {
+		t.Fatalf("CheckCorrelation()=%+v, wanted change near 150", r)
+	}
+
+	if r.Before < 0.8 || r.After > 0.3 || r.Difference > -0.5 {
+		t.Errorf("CheckCorrelation() before=%f after=%f, wanted ~0.95 and ~0", r.Before, r.After)
+	}
+
+	if r := d.CheckCorrelation(xs[:150], ys[:150]); r != nil {
+		t.Errorf("CheckCorrelation() found change %+v in stable window", r)
+	}
+}
@@ -0,0 +1,192 @@
+package change
+
+import (
+	"math"
+	"sort"
+)
+
+// Cost measures how badly a single model fits a segment of a series.  It is
+// used to score candidate splits, and by the segmentation algorithms to choose
+// between segmentations.  Lower costs are better fits.
+type Cost interface {
+	// Fit returns a function giving the cost of the segment series[i:j]
+	Fit(series []float64) func(i, j int) float64
+}
+
+// L2 is the sum of squared deviations from the segment mean.  It detects
+// changes in the mean, and splitting on it is equivalent to maximising the
+// between-class scatter used by default.
+type L2 struct{}
+
+// Fit implements the Cost interface
+func (L2) Fit(series []float64) func(i, j int) float64 {
+	return newMeanCost(series)
+}
+
+// newMeanCost returns a function computing the sum of squared deviations from
+// the mean of series[i:j] in constant time
+func newMeanCost(series []float64) func(i, j int) float64 {
+	cumsum, cumsumsq := prefixSums(series, origin(series))
+
+	return func(i, j int) float64 {
+		sum := cumsum[j] - cumsum[i]
+		return (cumsumsq[j] - cumsumsq[i]) - sum*sum/float64(j-i)
+	}
+}
+
+// prefixSums returns the sums and sums of squares of series[:i] for 0 <= i <=
+// len(series), after subtracting shift from each item.  Variances computed
+// from the sums are the same for any shift, but are only accurate if the
+// shifted items are small, so a shift such as origin(series) should be used.
+func prefixSums(series []float64, shift float64) (cumsum, cumsumsq []float64) {
+	cumsum = make([]float64, len(series)+1)
+	cumsumsq = make([]float64, len(series)+1)
+
+	var sum, sumsq kahan
+	for i, v := range series {
+		v -= shift
+		sum.add(v)
+		sumsq.add(v * v)
+		cumsum[i+1] = sum.value()
+		cumsumsq[i+1] = sumsq.value()
+	}
+	return cumsum, cumsumsq
+}
+
+// origin returns a value close to the items of series, to shift them by
+// before computing variances from sums of squares.  Without the shift, the
+// variance of items with a large mean and small spread is lost to
+// cancellation, and may even be negative.
+func origin(series []float64) float64 {
+	if len(series) == 0 {
+		return 0
+	}
+	return series[0]
+}
+
+// L1 is the sum of absolute deviations from the segment median.  It detects
+// changes in the median and is robust to outliers, but each segment cost
+// takes O(n log n) time to compute.
+type L1 struct{}
+
+// Fit implements the Cost interface
+func (L1) Fit(series []float64) func(i, j int) float64 {
+	var buf []float64
+	return func(i, j int) float64 {
+		buf = append(buf[:0], series[i:j]...)
+		sort.Float64s(buf)
+
+		m := len(buf) / 2
+		median := buf[m]
+		if len(buf)%2 == 0 {
+			median = (buf[m-1] + buf[m]) / 2
+		}
+
+		var cost float64
+		for _, v := range buf {
+			cost += math.Abs(v - median)
+		}
+		return cost
+	}
+}
+
+// normalMinVariance is the smallest variance used by Normal, to avoid infinite costs for constant segments
+const normalMinVariance = 1e-12
+
+// Normal is twice the negative log-likelihood of the segment under a normal
+// distribution with its own mean and variance (up to a constant).  It detects
+// changes in the mean, the variance, or both.
+type Normal struct{}
+
+// Fit implements the Cost interface
+func (Normal) Fit(series []float64) func(i, j int) float64 {
+	cumsum, cumsumsq := prefixSums(series, origin(series))
+
+	return func(i, j int) float64 {
+		n := float64(j - i)
+		sum := cumsum[j] - cumsum[i]
+		variance := ((cumsumsq[j] - cumsumsq[i]) - sum*sum/n) / n
+		return n * math.Log(math.Max(variance, normalMinVariance))
+	}
+}
+
+// Poisson is twice the negative log-likelihood of the segment under a Poisson
+// distribution with its own rate (up to a constant).  It is suitable for
+// non-negative counts of events.  Poisson is also a Test, of the ratio of the
+// rates of the two samples.
+type Poisson struct {
+	// Exact selects the exact conditional test of the rates instead of the
+	// chi-square approximation to the likelihood ratio
+	Exact bool
+}
+
+// Fit implements the Cost interface
+func (Poisson) Fit(series []float64) func(i, j int) float64 {
+	cumsum, _ := prefixSums(series, 0)
+
+	return func(i, j int) float64 {
+		sum := cumsum[j] - cumsum[i]
+		if sum <= 0 {
+			return 0
+		}
+		return 2 * (sum - sum*math.Log(sum/float64(j-i)))
+	}
+}
+
+// RBF is the kernel cost using the radial basis function k(x, y) =
+// exp(-Gamma*(x-y)^2).  It detects arbitrary changes in distribution, but
+// needs O(n^2) time and memory for a series of length n.
+type RBF struct {
+	// Gamma is the kernel's bandwidth parameter.  If zero, it is set by the
+	// median heuristic to one over the median squared distance between items.
+	Gamma float64
+}
+
+// Fit implements the Cost interface
+func (r RBF) Fit(series []float64) func(i, j int) float64 {
+	n := len(series)
+
+	gamma := r.Gamma
+	if gamma == 0 {
+		gamma = medianGamma(series)
+	}
+
+	// g[i][j] is the sum of the kernel over series[:i] x series[:j]
+	g := make([][]float64, n+1)
+	g[0] = make([]float64, n+1)
+	for a := 1; a <= n; a++ {
+		g[a] = make([]float64, n+1)
+		for b := 1; b <= n; b++ {
+			d := series[a-1] - series[b-1]
+			g[a][b] = g[a-1][b] + g[a][b-1] - g[a-1][b-1] + math.Exp(-gamma*d*d)
+		}
+	}
+
+	return func(i, j int) float64 {
+		m := float64(j - i)
+		within := g[j][j] - g[i][j] - g[j][i] + g[i][i]
+		return m - within/m
+	}
+}
+
+// medianGamma returns one over the median squared distance between distinct pairs of items in series
+func medianGamma(series []float64) float64 {
+	var d2 []float64
+	for a := range series {
+		for b := a + 1; b < len(series); b++ {
+			d := series[a] - series[b]
+			d2 = append(d2, d*d)
+		}
+	}
+
+	if len(d2) == 0 {
+		return 1
+	}
+
+	sort.Float64s(d2)
+	if m := d2[len(d2)/2]; m > 0 {
+		return 1 / m
+	}
+
+	return 1
+}
@@ -0,0 +1,84 @@
+package change
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestCostConstant(t *testing.T) {
+	series := []float64{3, 3, 3, 3, 3, 3}
+
+	for _, c := range []Cost{L1{}, L2{}, RBF{}} {
+		if got := c.Fit(series)(1, 5); math.Abs(got) > 1e-9 {
+			t.Errorf("%T cost of constant segment=%f, wanted 0", c, got)
+		}
+	}
+}
+
+func TestCostL2Scatter(t *testing.T) {
+	series := []float64{1, 2, 1, 2, 5, 6, 5, 6, 5}
+	cost := L2{}.Fit(series)
+
+	n := len(series)
+	for l := 1; l < n; l++ {
+		before, after := sampleStats(series[:l]), sampleStats(series[l:])
+		n1, n2 := float64(l), float64(n-l)
+		diff := before.Mean() - after.Mean()
+		sb := n1 * n2 / (n1 + n2) * diff * diff
+
+		if gain := cost(0, n) - cost(0, l) - cost(l, n); math.Abs(gain-sb) > 1e-9 {
+			t.Errorf("L2 gain at %d=%f, wanted sb=%f", l, gain, sb)
+		}
+	}
+}
+
+func TestCostDetector(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// a change in variance only, which the default scatter can't see
+	var w []float64
+	for i := 0; i < 200; i++ {
+		v := rnd.NormFloat64()
+		if i >= 120 {
+			v *= 5
+		}
+		w = append(w, v)
+	}
+
+	d := Detector{MinSampleSize: 20, Cost: Normal{}, Test: KolmogorovSmirnov{}, MinConfidence: 0.99}
+	if r := d.Check(w); r == nil || r.Index < 115 || r.Index > 125 {
+		t.Errorf("Check with Normal cost=%+v, wanted change near 120", r)
+	}
+
+	rbf := Detector{MinSampleSize: 20, Cost: RBF{}, Test: KolmogorovSmirnov{}, MinConfidence: 0.99}
+	if r := rbf.Check(w); r == nil || r.Index < 110 || r.Index > 130 {
+		t.Errorf("Check with RBF cost=%+v, wanted change near 120", r)
+	}
+}
+
+func TestPELTPoisson(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// event counts whose rate triples
+	var series []float64
+	for i := 0; i < 200; i++ {
+		rate := 2.0
+		if i >= 100 {
+			rate = 6
+		}
+
+		// Knuth's algorithm
+		var k float64
+		for p := rnd.Float64(); p > math.Exp(-rate); p *= rnd.Float64() {
+			k++
+		}
+		series = append(series, k)
+	}
+
+	penalty := 3 * math.Log(float64(len(series)))
+	if got := PELT(series, Poisson{}, penalty, 5); !reflect.DeepEqual(got, []int{100}) {
+		t.Errorf("PELT with Poisson cost=%v, wanted [100]", got)
+	}
+}
@@ -0,0 +1,107 @@
+package change
+
+// CheckCovariance returns a potential change point in the covariance matrix
+// of a window of vector-valued samples, such as a change in the variance of
+// one metric or in the correlation between two, whether or not the means
+// change.  Each element of window is one sample, and all samples must have the
+// same number of dimensions.
+//
+// The split is chosen to maximise Box's M statistic for the equality of the
+// covariance matrices on either side, and the confidence is from its
+// chi-square approximation with p(p+1)/2 degrees of freedom for p dimensions.
+// As the statistic is maximised over the candidate splits, this overstates
+// the confidence, so MinConfidence should be set conservatively.  The
+// detector's Test and Cost are not used.
+func (d *Detector) CheckCovariance(window [][]float64) *MultiChangePoint {
+	n := len(window)
+	if n == 0 {
+		return nil
+	}
+	p := len(window[0])
+
+	minSampleSize := d.minSampleSize()
+
+	// each covariance matrix needs more samples than dimensions
+	if minSampleSize <= p {
+		minSampleSize = p + 1
+	}
+
+	// prefix sums of the samples and of their outer products
+	sum := make([][]float64, n+1)
+	outer := make([][][]float64, n+1)
+	sum[0] = make([]float64, p)
+	outer[0] = newMatrix(p)
+	for i, w := range window {
+		sum[i+1] = make([]float64, p)
+		outer[i+1] = newMatrix(p)
+		for a := 0; a < p; a++ {
+			sum[i+1][a] = sum[i][a] + w[a]
+			for b := 0; b < p; b++ {
+				outer[i+1][a][b] = outer[i][a][b] + w[a]*w[b]
+			}
+		}
+	}
+
+	// scatter returns the sum of the centered outer products of window[i:j]
+	scatter := func(i, j int) [][]float64 {
+		m := float64(j - i)
+		s := newMatrix(p)
+		for a := 0; a < p; a++ {
+			sa := sum[j][a] - sum[i][a]
+			for b := 0; b < p; b++ {
+				sb := sum[j][b] - sum[i][b]
+				s[a][b] = (outer[j][a][b] - outer[i][a][b]) - sa*sb/m
+			}
+		}
+		return s
+	}
+
+	fp := float64(p)
+	df := fp * (fp + 1) / 2
+
+	var maxm float64
+	var maxmIdx int
+
+	for l := minSampleSize; l < (n - minSampleSize + 1); l++ {
+		n1, n2 := float64(l), float64(n-l)
+
+		s1, s2 := scatter(0, l), scatter(l, n)
+		pooled := newMatrix(p)
+		for a := 0; a < p; a++ {
+			for b := 0; b < p; b++ {
+				pooled[a][b] = (s1[a][b] + s2[a][b]) / (n1 + n2 - 2)
+				s1[a][b] /= n1 - 1
+				s2[a][b] /= n2 - 1
+			}
+		}
+
+		ld, ok := logdet(pooled)
+		ld1, ok1 := logdet(s1)
+		ld2, ok2 := logdet(s2)
+		if !ok || !ok1 || !ok2 {
+			continue
+		}
+
+		m := (n1+n2-2)*ld - (n1-1)*ld1 - (n2-1)*ld2
+		c := (2*fp*fp + 3*fp - 1) / (6 * (fp + 1)) * (1/(n1-1) + 1/(n2-1) - 1/(n1+n2-2))
+		m *= 1 - c
+
+		if maxm < m {
+			maxm = m
+			maxmIdx = l
+		}
+	}
+
+	if maxmIdx == 0 {
+		return nil
+	}
+
+	conf := chiSquareCDF(maxm, df)
+
+	// not above our threshold
+	if conf <= d.MinConfidence {
+		return nil
+	}
+
+	return newMultiChangePoint(window, maxmIdx, conf, maxm)
+}
@@ -0,0 +1,50 @@
+package change
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestLogdet(t *testing.T) {
+	ld, ok := logdet([][]float64{{2, 1}, {1, 3}})
+	if !ok || math.Abs(ld-math.Log(5)) > 1e-9 {
+		t.Errorf("logdet()=%f, %v, wanted log(5)", ld, ok)
+	}
+
+	if _, ok := logdet([][]float64{{1, 2}, {2, 1}}); ok {
+		t.Errorf("logdet() of matrix with negative determinant succeeded")
+	}
+}
+
+func TestCheckCovariance(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// two metrics which are strongly correlated until 150, with the same
+	// means and variances throughout
+	window := make([][]float64, 300)
+	for i := range window {
+		a := rnd.NormFloat64()
+		b := 0.95*a + math.Sqrt(1-0.95*0.95)*rnd.NormFloat64()
+		if i >= 150 {
+			b = rnd.NormFloat64()
+		}
+		window[i] = []float64{a, b}
+	}
+
+	d := New(20, 0.9999)
+
+	r := d.CheckCovariance(window)
+	if r == nil || r.Index < 140 || r.Index > 160 {
+		t.Fatalf("CheckCovariance()=%+v, wanted change near 150", r)
+	}
+
+	// the mean-based detector doesn't see it
+	if r := d.CheckMulti(window); r != nil {
+		t.Errorf("CheckMulti() found change %+v", r)
+	}
+
+	if r := d.CheckCovariance(window[:150]); r != nil {
+		t.Errorf("CheckCovariance() found change %+v in stable window", r)
+	}
+}
@@ -0,0 +1,113 @@
+package change
+
+import "math"
+
+// CUSUM is a tabular cumulative sum control chart.  It accumulates the
+// deviations of each item from a known in-control mean, and signals a change
+// when either the upper or lower sum exceeds the decision interval.  It uses
+// constant memory and is suitable for monitoring unbounded streams.
+type CUSUM struct {
+	target float64
+	stddev float64
+	k      float64
+	h      float64
+
+	items int
+
+	// base is the offset of the first item since the last change reported
+	base int
+
+	hi, lo side
+}
+
+// side is one half of the tabular CUSUM
+type side struct {
+	sum   float64
+	start int
+
+	// statistics of the items since the sum last left zero
+	n      int
+	mean   float64
+	sqdiff float64
+}
+
+func (s *side) reset(start int) {
+	*s = side{start: start}
+}
+
+func (s *side) add(item float64) {
+	s.n++
+	delta := item - s.mean
+	s.mean += delta / float64(s.n)
+	s.sqdiff += delta * (item - s.mean)
+}
+
+func (s *side) stats() Stats {
+	st := Stats{mean: s.mean, n: s.n}
+	if s.n > 1 {
+		st.variance = s.sqdiff / float64(s.n-1)
+	}
+	return st
+}
+
+// NewCUSUM constructs a CUSUM detector for a stream with the given in-control
+// mean and standard deviation.  The allowance k and decision interval h are in
+// units of the standard deviation; k=0.5 and h=4 or 5 are common choices.
+func NewCUSUM(target, stddev, k, h float64) *CUSUM {
+	return &CUSUM{
+		target: target,
+		stddev: stddev,
+		k:      k * stddev,
+		h:      h * stddev,
+	}
+}
+
+// Push adds a float to the stream and returns a change point if either sum
+// exceeds the decision interval.  The change point's Index is the offset in
+// the stream of the first item after the change, estimated as the item where
+// the exceeding sum last left zero, and Statistic is the value of that sum.
+// Both sums are reset after a change is reported.
+func (c *CUSUM) Push(item float64) *ChangePoint {
+	idx := c.items
+	c.items++
+
+	c.hi.sum = math.Max(0, c.hi.sum+item-c.target-c.k)
+	if c.hi.sum == 0 {
+		c.hi.reset(idx + 1)
+	} else {
+		c.hi.add(item)
+	}
+
+	c.lo.sum = math.Max(0, c.lo.sum+c.target-c.k-item)
+	if c.lo.sum == 0 {
+		c.lo.reset(idx + 1)
+	} else {
+		c.lo.add(item)
+	}
+
+	var s *side
+	switch {
+	case c.hi.sum > c.h:
+		s = &c.hi
+	case c.lo.sum > c.h:
+		s = &c.lo
+	default:
+		return nil
+	}
+
+	after := s.stats()
+
+	cp := &ChangePoint{
+		Index:      s.start,
+		Difference: after.Mean() - c.target,
+		Statistic:  s.sum,
+		Before:     Stats{mean: c.target, variance: c.stddev * c.stddev, n: s.start - c.base},
+		After:      after,
+	}
+
+	c.hi.reset(c.items)
+	c.lo.reset(c.items)
+	c.base = c.items
+
+	return cp
+}
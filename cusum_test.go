@@ -0,0 +1,59 @@
+package change
+
+import "testing"
+
+func TestCUSUM(t *testing.T) {
+
+	var tests = []struct {
+		shift float64
+		idx   int
+	}{
+		{0, -1}, // no change
+		{2, 50},
+		{-2, 50},
+	}
+
+	for _, tt := range tests {
+		c := NewCUSUM(10, 1, 0.5, 5)
+
+		idx := -1
+		for i := 0; i < 100; i++ {
+			// alternate around the mean to stay in control
+			v := 10 + 0.5*float64(i%2*2-1)
+			if i >= 50 {
+				v += tt.shift
+			}
+
+			if r := c.Push(v); r != nil && idx == -1 {
+				idx = r.Index
+				if (r.Difference > 0) != (tt.shift > 0) {
+					t.Errorf("CUSUM shift=%f difference=%f has wrong sign", tt.shift, r.Difference)
+				}
+			}
+		}
+
+		if idx != tt.idx {
+			t.Errorf("CUSUM shift=%f index=%d, wanted %d", tt.shift, idx, tt.idx)
+		}
+	}
+}
+
+func TestCUSUMBefore(t *testing.T) {
+	c := NewCUSUM(10, 1, 0.5, 5)
+
+	// the shift is reported, and reported again soon after the reset
+	var before []int
+	for i := 0; i < 100; i++ {
+		v := 10 + 0.5*float64(i%2*2-1)
+		if i >= 50 {
+			v += 2
+		}
+		if r := c.Push(v); r != nil {
+			before = append(before, r.Before.Len())
+		}
+	}
+
+	if len(before) < 2 || before[0] != 50 || before[1] != 0 {
+		t.Errorf("CUSUM before lengths=%v, wanted 50 and then 0", before)
+	}
+}
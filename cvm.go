@@ -0,0 +1,100 @@
+package change
+
+import (
+	"math"
+	"sort"
+)
+
+// CramerVonMises is the two-sample Cramér-von Mises test.  Like the
+// Kolmogorov-Smirnov test it compares the empirical distribution functions of
+// the samples, but it integrates the squared difference between them rather
+// than taking the largest, which makes it more sensitive to differences in
+// the middle of the distribution.
+//
+// The reported statistic is Anderson's (1962) T.  The confidence is from the
+// limiting distribution of T after standardising it with its exact mean and
+// variance for the sample sizes.
+type CramerVonMises struct{}
+
+// Test implements the Test interface
+func (CramerVonMises) Test(before, after []float64) (float64, float64) {
+	n1, n2 := float64(len(before)), float64(len(after))
+	n := n1 + n2
+	k := n1 * n2
+
+	r, _ := ranks(before, after)
+	rx := append([]float64(nil), r[:len(before)]...)
+	ry := append([]float64(nil), r[len(before):]...)
+	sort.Float64s(rx)
+	sort.Float64s(ry)
+
+	var ux, uy float64
+	for i, v := range rx {
+		d := v - float64(i+1)
+		ux += d * d
+	}
+	for j, v := range ry {
+		d := v - float64(j+1)
+		uy += d * d
+	}
+
+	u := n1*ux + n2*uy
+	t := u/(k*n) - (4*k-1)/(6*n)
+
+	et := (1 + 1/n) / 6
+	vt := (n + 1) * (4*k*n - 3*(n1*n1+n2*n2) - 2*k) / (45 * n * n * 4 * k)
+	tn := 1.0/6 + (t-et)/math.Sqrt(45*vt)
+
+	return t, cvmCDF(tn)
+}
+
+// cvmCDF returns the limiting cumulative distribution function of the
+// Cramér-von Mises statistic, using the series of Csörgő and Faraway (1996)
+func cvmCDF(x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+
+	var sum float64
+	for k := 0; k < 20; k++ {
+		fk := float64(k)
+		y := 4*fk + 1
+		q := y * y / (16 * x)
+		if q > 700 {
+			break
+		}
+
+		lg1, _ := math.Lgamma(fk + 0.5)
+		lg2, _ := math.Lgamma(fk + 1)
+
+		sum += math.Exp(lg1-lg2-q) / (math.Pow(math.Pi, 1.5) * math.Sqrt(x)) * math.Sqrt(y) * besselK(0.25, q)
+	}
+
+	return math.Max(0, math.Min(1, sum))
+}
+
+// besselK returns the modified Bessel function of the second kind K_nu(z) for
+// z > 0, by integrating its representation exp(-z cosh t) cosh(nu t) over t
+func besselK(nu, z float64) float64 {
+	const h = 0.005
+
+	var sum float64
+	for t := 0.0; ; t += h {
+		e := z * math.Cosh(t)
+		if e > 750 {
+			break
+		}
+
+		v := math.Exp(-e) * math.Cosh(nu*t)
+		if t == 0 {
+			v /= 2
+		}
+		sum += v
+
+		if v < 1e-18*sum {
+			break
+		}
+	}
+
+	return sum * h
+}
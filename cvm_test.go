@@ -0,0 +1,59 @@
+package change
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestBesselK(t *testing.T) {
+
+	var tests = []struct {
+		nu, z, want float64
+	}{
+		{0.5, 1, math.Sqrt(math.Pi/2) * math.Exp(-1)}, // closed form for nu=1/2
+		{0.5, 3, math.Sqrt(math.Pi/6) * math.Exp(-3)},
+	}
+
+	for _, tt := range tests {
+		if got := besselK(tt.nu, tt.z); math.Abs(got-tt.want) > 1e-8 {
+			t.Errorf("besselK(%f, %f)=%.10f, wanted %.10f", tt.nu, tt.z, got, tt.want)
+		}
+	}
+}
+
+func TestCvmCDF(t *testing.T) {
+	// asymptotic critical values of the Cramér-von Mises statistic
+	var tests = []struct {
+		x, p float64
+	}{
+		{0.34730, 0.90},
+		{0.46136, 0.95},
+		{0.74346, 0.99},
+	}
+
+	for _, tt := range tests {
+		if got := cvmCDF(tt.x); math.Abs(got-tt.p) > 1e-4 {
+			t.Errorf("cvmCDF(%f)=%f, wanted %f", tt.x, got, tt.p)
+		}
+	}
+}
+
+func TestCramerVonMises(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2))
+
+	var xs, ys, zs []float64
+	for i := 0; i < 80; i++ {
+		xs = append(xs, rnd.NormFloat64())
+		ys = append(ys, rnd.NormFloat64())
+		zs = append(zs, rnd.NormFloat64()+0.7)
+	}
+
+	if _, conf := (CramerVonMises{}).Test(xs, ys); conf > 0.95 {
+		t.Errorf("CramerVonMises confidence=%f for the same distribution", conf)
+	}
+
+	if _, conf := (CramerVonMises{}).Test(xs, zs); conf < 0.99 {
+		t.Errorf("CramerVonMises confidence=%f for shifted distributions", conf)
+	}
+}
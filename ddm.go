@@ -0,0 +1,170 @@
+package change
+
+import "math"
+
+// Level is the state reported by a concept drift detector
+type Level int
+
+const (
+	// InControl means no drift has been detected
+	InControl Level = iota
+
+	// Warning means the error rate has risen enough that a drift may be starting
+	Warning
+
+	// Drift means the error rate has changed significantly
+	Drift
+)
+
+func (l Level) String() string {
+	switch l {
+	case InControl:
+		return "in control"
+	case Warning:
+		return "warning"
+	case Drift:
+		return "drift"
+	}
+	return "unknown"
+}
+
+// ddmMinItems is the number of items (or, for EDDM, errors) seen before drift can be reported
+const ddmMinItems = 30
+
+// DDM is the Drift Detection Method of Gama et al. (2004).  It monitors a
+// stream of classifier errors, modelled as Bernoulli trials, and reports a
+// warning or drift when the error rate rises significantly above the lowest
+// rate seen so far.
+type DDM struct {
+	warning float64
+	drift   float64
+
+	n      int
+	errors int
+
+	pmin, smin float64
+}
+
+// NewDDM constructs a DDM detector.  Warning and drift are the number of
+// standard deviations above the minimum error rate at which each level is
+// reported; 2 and 3 are the usual choices.
+func NewDDM(warning, drift float64) *DDM {
+	d := &DDM{
+		warning: warning,
+		drift:   drift,
+	}
+	d.Reset()
+	return d
+}
+
+// Push adds the outcome of one prediction to the stream and returns the
+// current level.  The detector is reset after a drift is reported.
+func (d *DDM) Push(err bool) Level {
+	d.n++
+	if err {
+		d.errors++
+	}
+
+	n := float64(d.n)
+	p := float64(d.errors) / n
+	s := math.Sqrt(p * (1 - p) / n)
+
+	if d.n < ddmMinItems {
+		return InControl
+	}
+
+	if p+s < d.pmin+d.smin {
+		d.pmin, d.smin = p, s
+	}
+
+	switch {
+	case p+s > d.pmin+d.drift*d.smin:
+		d.Reset()
+		return Drift
+	case p+s > d.pmin+d.warning*d.smin:
+		return Warning
+	}
+
+	return InControl
+}
+
+// Reset clears the detector's state
+func (d *DDM) Reset() {
+	d.n, d.errors = 0, 0
+	d.pmin, d.smin = math.Inf(1), math.Inf(1)
+}
+
+// EDDM is the Early Drift Detection Method of Baena-García et al. (2006).
+// Instead of the error rate it monitors the distance between consecutive
+// errors, which makes it quicker than DDM to detect gradual drift.  A warning
+// or drift is reported when the mean distance plus two standard deviations
+// falls below a fraction of its maximum.
+type EDDM struct {
+	warning float64
+	drift   float64
+
+	n    int
+	last int
+
+	// running statistics of the distance between errors
+	errors int
+	mean   float64
+	sqdiff float64
+
+	max float64
+}
+
+// NewEDDM constructs an EDDM detector.  Warning and drift are the ratios to
+// the maximum at which each level is reported; 0.95 and 0.90 are the usual
+// choices.
+func NewEDDM(warning, drift float64) *EDDM {
+	return &EDDM{
+		warning: warning,
+		drift:   drift,
+	}
+}
+
+// Push adds the outcome of one prediction to the stream and returns the
+// current level.  The detector is reset after a drift is reported.
+func (e *EDDM) Push(err bool) Level {
+	e.n++
+	if !err {
+		return InControl
+	}
+
+	dist := float64(e.n - e.last)
+	e.last = e.n
+
+	e.errors++
+	delta := dist - e.mean
+	e.mean += delta / float64(e.errors)
+	e.sqdiff += delta * (dist - e.mean)
+
+	if e.errors < ddmMinItems {
+		// the early estimates are too noisy to be used as the maximum
+		return InControl
+	}
+
+	m := e.mean + 2*math.Sqrt(e.sqdiff/float64(e.errors))
+	if m > e.max {
+		e.max = m
+	}
+
+	switch ratio := m / e.max; {
+	case ratio < e.drift:
+		e.Reset()
+		return Drift
+	case ratio < e.warning:
+		return Warning
+	}
+
+	return InControl
+}
+
+// Reset clears the detector's state
+func (e *EDDM) Reset() {
+	*e = EDDM{
+		warning: e.warning,
+		drift:   e.drift,
+	}
+}
@@ -0,0 +1,56 @@
+package change
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// levels runs a stream of errors with the given rates, each lasting length items, through push
+func levels(push func(bool) Level, rnd *rand.Rand, length int, rates ...float64) (warning, drift int) {
+	warning, drift = -1, -1
+	var i int
+	for _, r := range rates {
+		for j := 0; j < length; j++ {
+			switch push(rnd.Float64() < r) {
+			case Warning:
+				if warning == -1 {
+					warning = i
+				}
+			case Drift:
+				if drift == -1 {
+					drift = i
+				}
+			}
+			i++
+		}
+	}
+	return warning, drift
+}
+
+func TestDDM(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	if _, drift := levels(NewDDM(2, 3).Push, rnd, 2000, 0.1); drift != -1 {
+		t.Errorf("DDM found drift at %d in a stable stream", drift)
+	}
+
+	warning, drift := levels(NewDDM(2, 3).Push, rnd, 2000, 0.1, 0.4)
+	if drift < 2000 || drift > 2200 {
+		t.Errorf("DDM drift at %d, wanted shortly after 2000", drift)
+	}
+	if warning == -1 || warning > drift {
+		t.Errorf("DDM warning at %d, wanted before drift at %d", warning, drift)
+	}
+}
+
+func TestEDDM(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	warning, drift := levels(NewEDDM(0.95, 0.9).Push, rnd, 2000, 0.1, 0.5)
+	if drift < 2000 || drift > 2200 {
+		t.Errorf("EDDM drift at %d, wanted shortly after 2000", drift)
+	}
+	if warning == -1 {
+		t.Errorf("EDDM reported no warning")
+	}
+}
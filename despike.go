@@ -0,0 +1,70 @@
+package change
+
+import (
+	"math"
+	"sort"
+)
+
+// DefaultDespikeK is the threshold used by Despike if K is zero
+const DefaultDespikeK = 5
+
+// Despike replaces isolated single-item excursions with the mean of their
+// neighbours, so that a one-off bad reading is not mistaken for a change, or
+// taken as the location of one.  An item is a spike if it differs from both
+// its neighbours in the same direction by more than K times the noise level,
+// which is estimated from the median absolute difference between neighbouring
+// items, while they are within that distance of each other.  Runs of two or
+// more unusual items are left alone, as they may be a real change.  The
+// first and last items are compared with the two items next to them.  If the
+// noise level is zero, as for quantised or 0/1 series where most neighbours
+// are equal, the isolated items are the signal and nothing is replaced.
+type Despike struct {
+	// K is the threshold in standard deviations.  If zero,
+	// DefaultDespikeK is used.
+	K float64
+}
+
+// Apply implements the Transform interface
+func (s Despike) Apply(series []float64) []float64 {
+	out := append([]float64(nil), series...)
+
+	n := len(series)
+	if n < 3 {
+		return out
+	}
+
+	k := s.K
+	if k == 0 {
+		k = DefaultDespikeK
+	}
+
+	// the differences of independent items have twice their variance
+	diffs := make([]float64, n-1)
+	for i := range diffs {
+		diffs[i] = math.Abs(series[i+1] - series[i])
+	}
+	sort.Float64s(diffs)
+	noise := quantile(diffs, 0.5)
+	if noise == 0 {
+		return out
+	}
+	threshold := k * madScale * noise / math.Sqrt2
+
+	for i, v := range series {
+		a, b := i-1, i+1
+		switch i {
+		case 0:
+			a = 2
+		case n - 1:
+			b = n - 3
+		}
+
+		da, db := v-series[a], v-series[b]
+		if math.Abs(da) > threshold && math.Abs(db) > threshold && (da > 0) == (db > 0) &&
+			math.Abs(series[a]-series[b]) <= threshold {
+			out[i] = (series[a] + series[b]) / 2
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,50 @@
+package change
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestDespike(t *testing.T) {
+	got := Despike{}.Apply([]float64{1, 2, 1, 2, 50, 1, 2, 1, 2, -40, 1, 2, 30, 30, 1, 2, 1})
+	want := []float64{1, 2, 1, 2, 1.5, 1, 2, 1, 2, 1.5, 1, 2, 30, 30, 1, 2, 1}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Despike.Apply()=%v, wanted %v", got, want)
+			break
+		}
+	}
+
+	if got := (Despike{}).Apply([]float64{100, 1, 2, 1, 2, 1, -100}); got[0] != 1.5 || got[6] != 1.5 {
+		t.Errorf("Despike.Apply() ends=%v, wanted them replaced", got)
+	}
+
+	// in a 0/1 series the isolated events are the signal
+	binary := []float64{0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 1}
+	got = Despike{}.Apply(binary)
+	for i := range binary {
+		if got[i] != binary[i] {
+			t.Errorf("Despike.Apply()=%v, wanted %v", got, binary)
+			break
+		}
+	}
+}
+
+func TestDespikeCheck(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// a single huge reading anchors the change point, instead of the real step
+	w := steps(rnd, 100, 0, 1.5)
+	w[40] = 200
+
+	d := Detector{MinSampleSize: 1, MinConfidence: 0.99, Cost: L2{}}
+	if r := d.Check(w); r != nil && r.Index >= 90 && r.Index <= 110 {
+		t.Errorf("Check() with a spike=%+v, wanted it not to find the step", r)
+	}
+
+	d.Transform = Despike{}
+	if r := d.Check(w); r == nil || r.Index < 90 || r.Index > 110 {
+		t.Errorf("Check(Despike)=%+v, wanted index 100", r)
+	}
+}
@@ -0,0 +1,27 @@
+package change
+
+import "math"
+
+// CohensD returns the standardised effect size of the change: the difference
+// in means divided by the pooled standard deviation.  In large windows even a
+// tiny change can be significant, so this is useful to filter out changes too
+// small to matter.  It is infinite if neither segment has any variance.
+func (cp *ChangePoint) CohensD() float64 {
+	n1, n2 := float64(cp.Before.Len()), float64(cp.After.Len())
+
+	pooled := ((n1-1)*cp.Before.Var() + (n2-1)*cp.After.Var()) / (n1 + n2 - 2)
+	if pooled == 0 {
+		if cp.Difference == 0 {
+			return 0
+		}
+		return math.Copysign(math.Inf(1), cp.Difference)
+	}
+
+	return cp.Difference / math.Sqrt(pooled)
+}
+
+// HedgesG returns Cohen's d with Hedges' correction for its bias in small samples
+func (cp *ChangePoint) HedgesG() float64 {
+	n := float64(cp.Before.Len() + cp.After.Len())
+	return cp.CohensD() * (1 - 3/(4*n-9))
+}
@@ -0,0 +1,31 @@
+package change
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEffectSize(t *testing.T) {
+	var tests = []struct {
+		before, after []float64
+		d, g          float64
+	}{
+		// pooled variance 1, difference 2
+		{[]float64{1, 2, 3}, []float64{3, 4, 5}, 2, 2 * (1 - 3.0/15)},
+		{[]float64{1, 2, 3}, []float64{1, 2, 3}, 0, 0},
+		{[]float64{1, 1, 1}, []float64{2, 2, 2}, math.Inf(1), math.Inf(1)},
+	}
+
+	for _, tt := range tests {
+		before, after := sampleStats(tt.before), sampleStats(tt.after)
+		cp := &ChangePoint{Difference: after.Mean() - before.Mean(), Before: before, After: after}
+
+		if d := cp.CohensD(); d != tt.d && math.Abs(d-tt.d) > 1e-12 {
+			t.Errorf("CohensD()=%f, wanted %f", d, tt.d)
+		}
+
+		if g := cp.HedgesG(); g != tt.g && math.Abs(g-tt.g) > 1e-12 {
+			t.Errorf("HedgesG()=%f, wanted %f", g, tt.g)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package change
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// Energy is the energy distance cost: the mean absolute difference between
+// all pairs of items in the segment, scaled by its length.  The reduction in
+// cost from splitting a segment is the energy statistic of Székely and Rizzo
+// between the two halves, which is zero only if they have the same
+// distribution, so it detects arbitrary changes in distribution.  Each
+// segment cost takes O(n log n) time to compute.
+type Energy struct{}
+
+// Fit implements the Cost interface
+func (Energy) Fit(series []float64) func(i, j int) float64 {
+	var buf []float64
+	return func(i, j int) float64 {
+		buf = append(buf[:0], series[i:j]...)
+		sort.Float64s(buf)
+
+		// with the items sorted, the sum over pairs a<b of |x[b]-x[a]| counts
+		// x[k] positively k times and negatively m-1-k times
+		m := len(buf)
+		var sum float64
+		for k, v := range buf {
+			sum += v * float64(2*k-m+1)
+		}
+
+		// over ordered pairs, scaled by the length
+		return 2 * sum / float64(m)
+	}
+}
+
+// EDivisive finds multiple change points in series with the e-divisive
+// method of Matteson and James (2014).  Splits are scored with the energy
+// statistic, so changes of any kind in the distribution are found without
+// assuming the data is normal.  Each split's significance is estimated by a
+// permutation test: the segment is shuffled the given number of times, and
+// the confidence is the fraction of shuffles whose best split scores lower
+// than the real one.  The confidence is at most permutations/(permutations+1),
+// so enough shuffles must be used to exceed MinConfidence.  The segmentation
+// is recursive, as in Segment.
+//
+// The shuffles use rnd; if it is nil, a fixed seed is used so results are
+// reproducible.  The detector's Test, Cost and Scorer are not used.  The change
+// points are returned in order, with Index as the offset into series and
+// Statistic as the energy statistic.
+func (d *Detector) EDivisive(series []float64, permutations int, rnd *rand.Rand) []*ChangePoint {
+	if rnd == nil {
+		rnd = rand.New(rand.NewSource(1))
+	}
+
+	// scanRange prefers a Scorer to the Cost
+	energy := *d
+	energy.Cost = Energy{}
+	energy.Scorer = nil
+
+	var cps []*ChangePoint
+	energy.eDivisive(series, 0, permutations, rnd, &cps)
+
+	cps = energy.limit(cps)
+	sort.Slice(cps, func(i, j int) bool { return cps[i].Index < cps[j].Index })
+
+	return cps
+}
+
+func (d *Detector) eDivisive(series []float64, offset, permutations int, rnd *rand.Rand, cps *[]*ChangePoint) {
+	s := d.scan(series)
+	if s.before.n == 0 {
+		return
+	}
+
+	conf := d.permutationTest(series, s.sb, permutations, rnd)
+
+	// not above our threshold
+	if conf <= d.MinConfidence {
+		return
+	}
+
+	*cps = append(*cps, &ChangePoint{
+		Index:      offset + s.index,
+		Difference: s.after.Mean() - s.before.Mean(),
+		Confidence: conf,
+		Statistic:  s.sb,
+		Before:     s.before,
+		After:      s.after,
+		Score:      s.sb,
+	})
+
+	d.eDivisive(series[:s.index], offset, permutations, rnd, cps)
+	d.eDivisive(series[s.index:], offset+s.index, permutations, rnd, cps)
+}
+
+// permutationTest returns the fraction of random shuffles of window whose best
+// split scores lower than observed, with the usual correction so that the
+// confidence is never exactly 1
+func (d *Detector) permutationTest(window []float64, observed float64, permutations int, rnd *rand.Rand) float64 {
+	shuffled := append([]float64(nil), window...)
+
+	var exceed int
+	for i := 0; i < permutations; i++ {
+		rnd.Shuffle(len(shuffled), func(a, b int) { shuffled[a], shuffled[b] = shuffled[b], shuffled[a] })
+		if d.scan(shuffled).sb >= observed {
+			exceed++
+		}
+	}
+
+	return 1 - float64(exceed+1)/float64(permutations+1)
+}
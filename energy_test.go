@@ -0,0 +1,67 @@
+package change
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestEnergyCost(t *testing.T) {
+	series := []float64{4, 1, 3, 2}
+
+	// sum over ordered pairs of |x-y|, divided by the length
+	var want float64
+	for _, x := range series {
+		for _, y := range series {
+			want += math.Abs(x - y)
+		}
+	}
+	want /= float64(len(series))
+
+	if got := (Energy{}).Fit(series)(0, len(series)); math.Abs(got-want) > 1e-9 {
+		t.Errorf("Energy cost=%f, wanted %f", got, want)
+	}
+}
+
+func TestEDivisive(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// same mean throughout, but the middle section is bimodal
+	var series []float64
+	for i := 0; i < 300; i++ {
+		v := rnd.NormFloat64()
+		if i >= 100 && i < 200 {
+			v = 0.1*rnd.NormFloat64() + float64(i%2*4-2)
+		}
+		series = append(series, v)
+	}
+
+	d := New(20, 0.99)
+
+	var got []int
+	for _, cp := range d.EDivisive(series, 199, nil) {
+		got = append(got, cp.Index)
+	}
+
+	want := []int{100, 200}
+	if len(got) != len(want) {
+		t.Fatalf("EDivisive()=%v, wanted %v", got, want)
+	}
+
+	for i := range got {
+		if math.Abs(float64(got[i]-want[i])) > 2 {
+			t.Errorf("EDivisive()=%v, wanted %v", got, want)
+		}
+	}
+
+	// the detector's own Scorer is ignored
+	d.Scorer = Wasserstein{}
+	var scored []int
+	for _, cp := range d.EDivisive(series, 199, nil) {
+		scored = append(scored, cp.Index)
+	}
+	if !reflect.DeepEqual(scored, got) {
+		t.Errorf("EDivisive(Scorer)=%v, wanted the energy statistic's %v", scored, got)
+	}
+}
@@ -0,0 +1,84 @@
+package change
+
+import "math"
+
+// EWMA is an exponentially weighted moving average control chart.  It
+// smooths the stream with weight lambda on the newest item and signals a
+// change when the smoothed value leaves the control limits of L standard
+// deviations around the in-control mean.  Small values of lambda make it
+// sensitive to small, gradual shifts.
+type EWMA struct {
+	target float64
+	stddev float64
+	lambda float64
+	l      float64
+
+	items int
+
+	// z is the smoothed value, and t the number of items since it was last reset
+	z float64
+	t int
+
+	// run holds the items since z last crossed the target
+	run side
+}
+
+// NewEWMA constructs an EWMA detector for a stream with the given in-control
+// mean and standard deviation.  Lambda is the smoothing weight in (0, 1], and
+// l the width of the control limits in standard deviations; lambda=0.2 and
+// l=3 are common choices.
+func NewEWMA(target, stddev, lambda, l float64) *EWMA {
+	return &EWMA{
+		target: target,
+		stddev: stddev,
+		lambda: lambda,
+		l:      l,
+		z:      target,
+	}
+}
+
+// Push adds a float to the stream and returns a change point if the smoothed
+// value is outside the control limits.  The change point's Index is the offset
+// in the stream of the first item after the smoothed value last crossed the
+// target, and Statistic is the smoothed value.  The chart is restarted from
+// the target after a change is reported.
+func (e *EWMA) Push(item float64) *ChangePoint {
+	idx := e.items
+	e.items++
+	e.t++
+
+	prev := e.z
+	e.z = e.lambda*item + (1-e.lambda)*e.z
+
+	if (prev-e.target)*(e.z-e.target) <= 0 {
+		// crossed (or touched) the target, so any drift starts here
+		e.run.reset(idx)
+	}
+	e.run.add(item)
+
+	if math.Abs(e.z-e.target) <= e.limit() {
+		return nil
+	}
+
+	after := e.run.stats()
+
+	cp := &ChangePoint{
+		Index:      e.run.start,
+		Difference: after.Mean() - e.target,
+		Statistic:  e.z,
+		Before:     Stats{mean: e.target, variance: e.stddev * e.stddev, n: e.run.start},
+		After:      after,
+	}
+
+	e.z = e.target
+	e.t = 0
+	e.run.reset(e.items)
+
+	return cp
+}
+
+// limit returns the distance of the control limits from the target, using the exact variance of the smoothed value after t items
+func (e *EWMA) limit() float64 {
+	decay := 1 - math.Pow(1-e.lambda, 2*float64(e.t))
+	return e.l * e.stddev * math.Sqrt(e.lambda/(2-e.lambda)*decay)
+}
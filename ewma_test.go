@@ -0,0 +1,38 @@
+package change
+
+import "testing"
+
+func TestEWMA(t *testing.T) {
+
+	var tests = []struct {
+		shift float64
+		found bool
+	}{
+		{0, false},
+		{1, true},
+		{-1, true},
+	}
+
+	for _, tt := range tests {
+		e := NewEWMA(10, 1, 0.2, 3)
+
+		idx := -1
+		for i := 0; i < 100; i++ {
+			v := 10 + 0.5*float64(i%2*2-1)
+			if i >= 50 {
+				v += tt.shift
+			}
+
+			if r := e.Push(v); r != nil && idx == -1 {
+				idx = r.Index
+				if (r.Difference > 0) != (tt.shift > 0) {
+					t.Errorf("EWMA shift=%f difference=%f has wrong sign", tt.shift, r.Difference)
+				}
+			}
+		}
+
+		if found := idx != -1; found != tt.found || (found && (idx < 49 || idx > 51)) {
+			t.Errorf("EWMA shift=%f index=%d, wanted found=%v near 50", tt.shift, idx, tt.found)
+		}
+	}
+}
@@ -19,6 +19,7 @@ func main() {
 	windowSize := flag.Int("w", 120, "window size")
 	minSample := flag.Int("ms", 30, "min sample size")
 	blockSize := flag.Int("bs", 10, "block size")
+	confidence := flag.Float64("c", 0.995, "minimum confidence level")
 	compressPoints := flag.Int("cp", 10, "compress points for graph display")
 	fname := flag.String("f", "", "file name")
 	ymin := flag.Int("ymin", 0, "minimum y value for graph")
@@ -41,7 +42,7 @@ func main() {
 
 	scanner := bufio.NewScanner(f)
 
-	s := change.NewStream(*windowSize, *minSample, *blockSize, 0.995)
+	s := change.NewStream(*windowSize, *minSample, *blockSize, *confidence)
 
 	type graphPoints [2]float64
 	var graphData []graphPoints
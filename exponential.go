@@ -0,0 +1,36 @@
+package change
+
+import "math"
+
+// Exponential models the items as exponentially distributed gaps between
+// events, such as the intervals between requests, and detects changes in the
+// event rate.  It is both a Cost and a Test:
+//
+//	d := change.Detector{Cost: change.Exponential{}, Test: change.Exponential{}}
+//
+// As a cost it is twice the negative log-likelihood of the segment with its
+// own rate (up to a constant).  As a test, the reported statistic is twice the
+// log of the likelihood ratio, and the confidence is from its chi-square
+// distribution with one degree of freedom.  All items must be positive.
+type Exponential struct{}
+
+// Fit implements the Cost interface
+func (Exponential) Fit(series []float64) func(i, j int) float64 {
+	cumsum, _ := prefixSums(series, 0)
+
+	return func(i, j int) float64 {
+		n := float64(j - i)
+		return 2 * n * math.Log((cumsum[j]-cumsum[i])/n)
+	}
+}
+
+// Test implements the Test interface
+func (e Exponential) Test(before, after []float64) (float64, float64) {
+	pooled := append(append([]float64(nil), before...), after...)
+	cost := e.Fit(pooled)
+
+	n1, n := len(before), len(pooled)
+	stat := math.Max(0, cost(0, n)-cost(0, n1)-cost(n1, n))
+
+	return stat, chiSquareCDF(stat, 1)
+}
@@ -0,0 +1,29 @@
+package change
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestExponential(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// request gaps where the traffic rate doubles at 200
+	var w []float64
+	for i := 0; i < 400; i++ {
+		mean := 1.0
+		if i >= 200 {
+			mean = 0.5
+		}
+		w = append(w, mean*rnd.ExpFloat64())
+	}
+
+	d := Detector{MinSampleSize: 20, Cost: Exponential{}, Test: Exponential{}, MinConfidence: 0.999}
+	if r := d.Check(w); r == nil || r.Index < 180 || r.Index > 220 || r.Difference >= 0 {
+		t.Errorf("Check with Exponential=%+v, wanted shorter gaps from about 200", r)
+	}
+
+	if r := d.Check(w[:200]); r != nil {
+		t.Errorf("Check with Exponential found change %+v in stable window", r)
+	}
+}
@@ -0,0 +1,27 @@
+package change
+
+// Number is the set of item types accepted by DetectChange
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// DetectChange is d.Check for a window of any numeric type, such as []float32
+// or []int64 counters.  The items are converted to float64 internally, so
+// integers beyond 2^53 lose precision.
+func DetectChange[T Number](d *Detector, window []T) *ChangePoint {
+	if w, ok := any(window).([]float64); ok {
+		return d.Check(w)
+	}
+	return d.Check(toFloats(window))
+}
+
+// toFloats returns the items of xs converted to float64
+func toFloats[T Number](xs []T) []float64 {
+	fs := make([]float64, len(xs))
+	for i, x := range xs {
+		fs[i] = float64(x)
+	}
+	return fs
+}
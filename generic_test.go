@@ -0,0 +1,42 @@
+package change
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestDetectChangeGeneric(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	w := steps(rnd, 100, 0, 5)
+	d := New(30, 0.99)
+
+	want := d.Check(w)
+	if want == nil {
+		t.Fatalf("Check found no change point")
+	}
+
+	f32 := make([]float32, len(w))
+	i64 := make([]int64, len(w))
+	for i, v := range w {
+		f32[i] = float32(v)
+		i64[i] = int64(v * 1000)
+	}
+
+	if got := DetectChange(d, w); got == nil || *got != *want {
+		t.Errorf("DetectChange([]float64)=%+v, wanted %+v", got, want)
+	}
+
+	if got := DetectChange(d, f32); got == nil || got.Index != want.Index {
+		t.Errorf("DetectChange([]float32)=%+v, wanted index %d", got, want.Index)
+	}
+
+	if got := DetectChange(d, i64); got == nil || got.Index != want.Index {
+		t.Errorf("DetectChange([]int64)=%+v, wanted index %d", got, want.Index)
+	}
+
+	type count uint32
+	if got := DetectChange(d, []count{1, 1, 1, 1, 1, 1, 1, 1}); got != nil {
+		t.Errorf("DetectChange([]count)=%+v, wanted nil", got)
+	}
+}
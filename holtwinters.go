@@ -0,0 +1,100 @@
+package change
+
+// HoltWinters detects changes relative to a Holt-Winters forecast of a
+// stream.  It maintains an additive Holt-Winters model of the level, trend
+// and seasonal cycle, and checks a rolling window of the residuals of its
+// one-step forecasts.  A level shift is then found relative to an evolving
+// expected value, rather than a flat historical mean.  As the forecast
+// adapts to the shift its residuals return to zero, and the split between the
+// shifted and the adapted residuals may then be reported as well.
+type HoltWinters struct {
+	alpha, beta, gamma float64
+	period             int
+
+	level, trend float64
+	season       []float64
+
+	// init holds the first two periods of items, which initialise the model
+	init []float64
+
+	items     int
+	residuals *Rolling
+}
+
+// NewHoltWinters returns a detector checking windows of windowSize forecast
+// residuals with d.  Alpha, beta and gamma are the smoothing weights in (0, 1]
+// of the level, trend and seasonal component, and period the length of the
+// seasonal cycle in items; a period less than 2 models no cycle.  Small
+// weights make the forecast adapt slowly, so changes stand out for longer.
+func NewHoltWinters(d *Detector, windowSize, period int, alpha, beta, gamma float64) *HoltWinters {
+	if period < 2 {
+		period = 1
+		gamma = 0
+	}
+
+	return &HoltWinters{
+		alpha:     alpha,
+		beta:      beta,
+		gamma:     gamma,
+		period:    period,
+		season:    make([]float64, period),
+		residuals: NewRolling(d, windowSize),
+	}
+}
+
+// Push adds an item to the stream, updates the model, and checks the window
+// of residuals.  The first two periods of items, or two items without a
+// cycle, initialise the model and are not checked.  The returned change
+// point's Index is the offset in the stream of the first item after the
+// change, and its statistics are of the residuals.
+func (h *HoltWinters) Push(item float64) *ChangePoint {
+	if need := 2 * h.period; len(h.init) < need {
+		h.items++
+		h.init = append(h.init, item)
+		if len(h.init) == need {
+			h.initialise()
+		}
+		return nil
+	}
+
+	phase := h.items % h.period
+	residual := item - h.Forecast()
+	h.items++
+
+	level := h.level
+	h.level = h.alpha*(item-h.season[phase]) + (1-h.alpha)*(level+h.trend)
+	h.trend = h.beta*(h.level-level) + (1-h.beta)*h.trend
+	h.season[phase] = h.gamma*(item-h.level) + (1-h.gamma)*h.season[phase]
+
+	cp := h.residuals.Push(residual)
+	if cp != nil {
+		cp.Index += len(h.init)
+	}
+
+	return cp
+}
+
+// Forecast returns the model's forecast of the next item
+func (h *HoltWinters) Forecast() float64 {
+	return h.level + h.trend + h.season[h.items%h.period]
+}
+
+// initialise sets the level to the mean of the first period, the trend to the
+// change in mean per item between the first two periods, and the seasonal
+// component to the first period's deviations from the trend
+func (h *HoltWinters) initialise() {
+	p := h.period
+	first := sampleStats(h.init[:p]).Mean()
+	second := sampleStats(h.init[p:]).Mean()
+
+	h.trend = (second - first) / float64(p)
+	for i, v := range h.init[:p] {
+		h.season[i] = v - (first + h.trend*(float64(i)-float64(p-1)/2))
+	}
+
+	// the model is at the end of the second period
+	h.level = second + h.trend*float64(p-1)/2
+	for i, v := range h.init[p:] {
+		h.season[i] = (h.season[i] + v - (second + h.trend*(float64(i)-float64(p-1)/2))) / 2
+	}
+}
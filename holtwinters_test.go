@@ -0,0 +1,59 @@
+package change
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestHoltWinters(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// a rising daily cycle with a level shift after 20 days, which a detector
+	// on the raw items would see everywhere
+	w := steps(rnd, 480, 0, 4)
+	for i := range w {
+		w[i] += 0.05*float64(i) + 10*math.Sin(2*math.Pi*float64(i)/24)
+	}
+
+	h := NewHoltWinters(New(30, 0.999), 120, 24, 0.1, 0.01, 0.1)
+
+	var found []int
+	for _, v := range w {
+		if cp := h.Push(v); cp != nil {
+			found = append(found, cp.Index)
+		}
+	}
+
+	// as the forecast adapts, the later change points are where the
+	// residuals return to zero, but there are no false alarms before the shift
+	if len(found) == 0 || found[0] < 475 || found[0] > 485 {
+		t.Fatalf("HoltWinters change points=%v, wanted 480 first", found)
+	}
+	for _, idx := range found {
+		if idx < 475 {
+			t.Errorf("HoltWinters change points=%v, wanted none before 480", found)
+			break
+		}
+	}
+
+	// the model has adapted to the new level
+	next := 4 + 0.05*float64(len(w)) + 10*math.Sin(2*math.Pi*float64(len(w))/24)
+	if f := h.Forecast(); math.Abs(f-next) > 1.5 {
+		t.Errorf("Forecast()=%f, wanted about %f", f, next)
+	}
+}
+
+func TestHoltWintersNoCycle(t *testing.T) {
+	// an exact trend is forecast exactly, so there are no residuals
+	h := NewHoltWinters(New(30, 0.99), 100, 0, 0.5, 0.5, 0.5)
+	for i := 0; i < 300; i++ {
+		if cp := h.Push(3 * float64(i)); cp != nil {
+			t.Fatalf("HoltWinters(trend)=%+v, wanted nil", cp)
+		}
+	}
+
+	if f := h.Forecast(); math.Abs(f-900) > 1e-9 {
+		t.Errorf("Forecast()=%f, wanted 900", f)
+	}
+}
@@ -0,0 +1,143 @@
+package change
+
+import "time"
+
+// Transition is a change of state reported by Hysteresis.
+type Transition struct {
+	// Changed is true when a change is raised, and false when the stream
+	// has recovered
+	Changed bool
+
+	// Index is the offset in the stream of the first item after the change,
+	// or of the first of the recent items which have recovered
+	Index int
+
+	// Time is the time of the item at Index, if the items were pushed with
+	// PushAt
+	Time time.Time
+
+	// Confidence is the confidence of the change, or of the remaining
+	// difference from the baseline when it recovered
+	Confidence float64
+
+	// ChangePoint is the change point which raised the change, for a change
+	// and its recovery
+	ChangePoint *ChangePoint
+}
+
+// Hysteresis tracks whether a stream is in a changed state, with separate
+// confidence levels for entering and leaving it, so that a marginal change
+// doesn't flap between the states.  A change is raised when a rolling window
+// has a change point with confidence above the enter level.  The items
+// before it are then the baseline, and the stream has recovered once the
+// most recent MinSampleSize items differ from the baseline with confidence
+// below the exit level, by Welch's t-test.  The baseline and the recent items
+// are prepared together by the detector's Transform, Outliers and Missing
+// policy, as a window would be.  A lasting shift to a new level
+// stays changed.  After a recovery, no change is raised until the window
+// has slid past the recovered items, so that the return to the baseline
+// isn't itself taken as a change.
+type Hysteresis struct {
+	exit float64
+
+	rolling *Rolling
+	minSize int
+
+	// change is the change point which raised the current change, or nil
+	change *ChangePoint
+
+	// baseline is the items before the change point, as they were pushed,
+	// and buf the baseline followed by the recent items
+	baseline []float64
+	buf      []float64
+
+	// recovered is the index of the recovery from the last change
+	recovered int
+}
+
+// NewHysteresis returns a detector of changes and recoveries in windows of
+// windowSize items, using d to find the change points.  Enter and exit are
+// the confidence levels for raising and clearing a change, such as 0.999 and
+// 0.9; d's MinConfidence is not used.
+func NewHysteresis(d *Detector, windowSize int, enter, exit float64) *Hysteresis {
+	rd := *d
+	rd.MinConfidence = enter
+
+	return &Hysteresis{
+		exit:    exit,
+		rolling: NewRolling(&rd, windowSize),
+		minSize: rd.minSampleSize(),
+	}
+}
+
+// Push adds an item to the stream, and returns a transition if it changes
+// state
+func (h *Hysteresis) Push(item float64) *Transition {
+	return h.push(time.Time{}, item, false)
+}
+
+// PushAt is Push for an item with a timestamp
+func (h *Hysteresis) PushAt(t time.Time, item float64) *Transition {
+	return h.push(t, item, true)
+}
+
+func (h *Hysteresis) push(t time.Time, item float64, timed bool) *Transition {
+	r := h.rolling
+
+	var cp *ChangePoint
+	if timed {
+		cp = r.PushAt(t, item)
+	} else {
+		cp = r.Push(item)
+	}
+
+	if h.change == nil {
+		if cp == nil || r.items-r.windowSize < h.recovered {
+			return nil
+		}
+
+		w := r.Window()
+		h.change = cp
+		h.baseline = append(h.baseline[:0], w[:cp.Index-(r.items-len(w))]...)
+		return &Transition{Changed: true, Index: cp.Index, Time: cp.Time, Confidence: cp.Confidence, ChangePoint: cp}
+	}
+
+	// the recent items must not overlap the change
+	start := r.items - h.minSize
+	if start < h.change.Index {
+		return nil
+	}
+
+	w := r.Window()
+	conf := h.difference(w[len(w)-h.minSize:])
+	if conf >= h.exit {
+		return nil
+	}
+
+	tr := &Transition{Index: start, Confidence: conf, ChangePoint: h.change}
+	if r.times != nil {
+		tr.Time = r.times[start%r.windowSize]
+	}
+	h.change = nil
+	h.recovered = start
+
+	return tr
+}
+
+// difference returns the confidence that the recent items differ from the baseline
+func (h *Hysteresis) difference(recent []float64) float64 {
+	h.buf = append(append(h.buf[:0], h.baseline...), recent...)
+
+	p := h.rolling.detector.prepare(h.buf)
+	k := p.split(len(h.baseline))
+	if k < 2 || len(p.series)-k < 2 {
+		// too few items left to tell
+		return 1
+	}
+
+	_, _, conf := welch(sampleStats(p.series[:k]), sampleStats(p.series[k:]))
+	return conf
+}
+
+// Changed reports whether the stream is in a changed state
+func (h *Hysteresis) Changed() bool { return h.change != nil }
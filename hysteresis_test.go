@@ -0,0 +1,78 @@
+package change
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestHysteresis(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// an incident from 300 to 500, then back to normal
+	w := steps(rnd, 100, 0, 0, 0, 4, 4, 0, 0, 0, 0)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	h := NewHysteresis(New(30, 0), 100, 0.9999, 0.9)
+
+	var got []*Transition
+	for i, v := range w {
+		if tr := h.PushAt(start.Add(time.Duration(i)*time.Second), v); tr != nil {
+			got = append(got, tr)
+			if tr.Changed != h.Changed() {
+				t.Errorf("Changed()=%v after transition %+v", h.Changed(), tr)
+			}
+		}
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("Hysteresis transitions=%d, wanted a change and a recovery", len(got))
+	}
+
+	if c := got[0]; !c.Changed || c.Index < 280 || c.Index > 320 || !c.Time.Equal(start.Add(time.Duration(c.Index)*time.Second)) {
+		t.Errorf("Hysteresis change=%+v, wanted at 300", c)
+	}
+
+	if r := got[1]; r.Changed || r.Index < 500 || r.Index > 520 || r.ChangePoint != got[0].ChangePoint || r.Confidence >= 0.9 {
+		t.Errorf("Hysteresis recovery=%+v, wanted soon after 500", r)
+	}
+
+	// a lasting shift stays changed
+	h = NewHysteresis(New(30, 0), 100, 0.9999, 0.9)
+	var n int
+	for _, v := range steps(rnd, 200, 0, 4, 4) {
+		if h.Push(v) != nil {
+			n++
+		}
+	}
+	if n != 1 || !h.Changed() {
+		t.Errorf("Hysteresis with a lasting shift transitions=%d changed=%v, wanted 1 and true", n, h.Changed())
+	}
+}
+
+func TestHysteresisTransform(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// the same incident on a log scale: the recent items are compared with
+	// the baseline after the same transform
+	var w []float64
+	for _, v := range steps(rnd, 100, 0, 0, 0, 4, 4, 0, 0, 0, 0) {
+		w = append(w, math.Exp(v))
+	}
+
+	d := New(30, 0)
+	d.Transform = Log{}
+	h := NewHysteresis(d, 100, 0.9999, 0.9)
+
+	var got []*Transition
+	for _, v := range w {
+		if tr := h.Push(v); tr != nil {
+			got = append(got, tr)
+		}
+	}
+
+	if len(got) != 2 || got[1].Changed || got[1].Index < 500 || got[1].Index > 520 {
+		t.Errorf("Hysteresis with Log transitions=%+v, wanted a change and a recovery soon after 500", got)
+	}
+}
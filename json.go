@@ -0,0 +1,248 @@
+package change
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// JSONVersion is the version of the JSON schema of change points.  Fields
+// may be added within a version, but not removed or changed.
+//
+// A change point is encoded as an object with the fields
+//
+//	version             the schema version, currently 1
+//	index               the index of the change point
+//	difference          the difference in means
+//	confidence          the confidence of the change
+//	statistic           the test statistic
+//	degrees_of_freedom  the degrees of freedom of the t-test, if used
+//	critical            the critical value of the t-test, if used
+//	before, after       the statistics either side of the change point
+//	score               the split score
+//	time                the RFC 3339 time of the change point, if known
+//	kind                the kind of change, if classified: "step", "ramp",
+//	                    "spike" or "variance"
+//
+// and statistics as objects with the fields n, mean and variance.  Numbers
+// which are not finite, such as the infinite t statistic of samples with no
+// variance, are encoded as the strings "NaN", "+Inf" and "-Inf".
+const JSONVersion = 1
+
+type jsonChangePoint struct {
+	Version          int       `json:"version"`
+	Index            int       `json:"index"`
+	Difference       jsonFloat `json:"difference"`
+	Confidence       jsonFloat `json:"confidence"`
+	Statistic        jsonFloat `json:"statistic"`
+	DegreesOfFreedom jsonFloat `json:"degrees_of_freedom,omitempty"`
+	Critical         jsonFloat `json:"critical,omitempty"`
+	Before           Stats     `json:"before"`
+	After            Stats     `json:"after"`
+	Score            jsonFloat `json:"score"`
+	Time             string    `json:"time,omitempty"`
+	Kind             string    `json:"kind,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler, using the schema described by JSONVersion
+func (cp ChangePoint) MarshalJSON() ([]byte, error) {
+	j := jsonChangePoint{
+		Version:          JSONVersion,
+		Index:            cp.Index,
+		Difference:       jsonFloat(cp.Difference),
+		Confidence:       jsonFloat(cp.Confidence),
+		Statistic:        jsonFloat(cp.Statistic),
+		DegreesOfFreedom: jsonFloat(cp.DegreesOfFreedom),
+		Critical:         jsonFloat(cp.Critical),
+		Before:           cp.Before,
+		After:            cp.After,
+		Score:            jsonFloat(cp.Score),
+	}
+
+	if !cp.Time.IsZero() {
+		j.Time = cp.Time.Format(time.RFC3339Nano)
+	}
+	if cp.Kind != Unclassified {
+		j.Kind = cp.Kind.String()
+	}
+
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting the schema described
+// by JSONVersion, or an object without a version
+func (cp *ChangePoint) UnmarshalJSON(data []byte) error {
+	var j jsonChangePoint
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if j.Version > JSONVersion {
+		return fmt.Errorf("change: unknown JSON version %d", j.Version)
+	}
+
+	*cp = ChangePoint{
+		Index:            j.Index,
+		Difference:       float64(j.Difference),
+		Confidence:       float64(j.Confidence),
+		Statistic:        float64(j.Statistic),
+		DegreesOfFreedom: float64(j.DegreesOfFreedom),
+		Critical:         float64(j.Critical),
+		Before:           j.Before,
+		After:            j.After,
+		Score:            float64(j.Score),
+	}
+
+	if j.Time != "" {
+		t, err := time.Parse(time.RFC3339Nano, j.Time)
+		if err != nil {
+			return fmt.Errorf("change: bad time: %w", err)
+		}
+		cp.Time = t
+	}
+
+	if j.Kind != "" {
+		k, err := parseKind(j.Kind)
+		if err != nil {
+			return err
+		}
+		cp.Kind = k
+	}
+
+	return nil
+}
+
+type jsonStats struct {
+	N        int       `json:"n"`
+	Mean     jsonFloat `json:"mean"`
+	Variance jsonFloat `json:"variance"`
+}
+
+// MarshalJSON implements json.Marshaler
+func (s Stats) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonStats{N: s.n, Mean: jsonFloat(s.mean), Variance: jsonFloat(s.variance)})
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (s *Stats) UnmarshalJSON(data []byte) error {
+	var j jsonStats
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	*s = Stats{n: j.N, mean: float64(j.Mean), variance: float64(j.Variance)}
+	return nil
+}
+
+// parseKind returns the Kind with the given String
+func parseKind(s string) (Kind, error) {
+	for k := Unclassified; k <= VarianceChange; k++ {
+		if k.String() == s {
+			return k, nil
+		}
+	}
+	return Unclassified, fmt.Errorf("change: unknown kind %q", s)
+}
+
+// jsonFloat is a float64 which encodes non-finite values as strings
+type jsonFloat float64
+
+func (f jsonFloat) MarshalJSON() ([]byte, error) {
+	v := float64(f)
+	switch {
+	case math.IsNaN(v):
+		return []byte(`"NaN"`), nil
+	case math.IsInf(v, 1):
+		return []byte(`"+Inf"`), nil
+	case math.IsInf(v, -1):
+		return []byte(`"-Inf"`), nil
+	}
+	return strconv.AppendFloat(nil, v, 'g', -1, 64), nil
+}
+
+func (f *jsonFloat) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil || !(math.IsNaN(v) || math.IsInf(v, 0)) {
+			return fmt.Errorf("change: bad number %q", s)
+		}
+		*f = jsonFloat(v)
+		return nil
+	}
+
+	var v float64
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*f = jsonFloat(v)
+	return nil
+}
+
+// mergeJSON returns the JSON object of cp with the fields of the object extra added
+func mergeJSON(cp ChangePoint, extra interface{}) ([]byte, error) {
+	a, err := cp.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(extra)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) <= 2 {
+		return a, nil
+	}
+	return append(append(a[:len(a)-1], ','), b[1:]...), nil
+}
+
+type jsonQuantile struct {
+	Quantile       jsonFloat `json:"quantile"`
+	BeforeQuantile jsonFloat `json:"before_quantile"`
+	AfterQuantile  jsonFloat `json:"after_quantile"`
+}
+
+// MarshalJSON implements json.Marshaler, adding the fields quantile,
+// before_quantile and after_quantile to the schema of a ChangePoint
+func (q QuantileChangePoint) MarshalJSON() ([]byte, error) {
+	return mergeJSON(q.ChangePoint, jsonQuantile{jsonFloat(q.Quantile), jsonFloat(q.BeforeQuantile), jsonFloat(q.AfterQuantile)})
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (q *QuantileChangePoint) UnmarshalJSON(data []byte) error {
+	var j jsonQuantile
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if err := q.ChangePoint.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	q.Quantile, q.BeforeQuantile, q.AfterQuantile = float64(j.Quantile), float64(j.BeforeQuantile), float64(j.AfterQuantile)
+	return nil
+}
+
+type jsonRobust struct {
+	BeforeLocation jsonFloat `json:"before_location"`
+	AfterLocation  jsonFloat `json:"after_location"`
+	BeforeScale    jsonFloat `json:"before_scale"`
+	AfterScale     jsonFloat `json:"after_scale"`
+}
+
+// MarshalJSON implements json.Marshaler, adding the fields before_location,
+// after_location, before_scale and after_scale to the schema of a ChangePoint
+func (r RobustChangePoint) MarshalJSON() ([]byte, error) {
+	return mergeJSON(r.ChangePoint, jsonRobust{jsonFloat(r.BeforeLocation), jsonFloat(r.AfterLocation), jsonFloat(r.BeforeScale), jsonFloat(r.AfterScale)})
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (r *RobustChangePoint) UnmarshalJSON(data []byte) error {
+	var j jsonRobust
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if err := r.ChangePoint.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	r.BeforeLocation, r.AfterLocation = float64(j.BeforeLocation), float64(j.AfterLocation)
+	r.BeforeScale, r.AfterScale = float64(j.BeforeScale), float64(j.AfterScale)
+	return nil
+}
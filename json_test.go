@@ -0,0 +1,97 @@
+package change
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestChangePointJSON(t *testing.T) {
+	cp := ChangePoint{
+		Index:            100,
+		Difference:       2.5,
+		Confidence:       0.999,
+		Statistic:        math.Inf(-1),
+		DegreesOfFreedom: 57.25,
+		Before:           Stats{n: 100, mean: 1, variance: 0.5},
+		After:            Stats{n: 80, mean: 3.5, variance: 0},
+		Score:            42,
+		Time:             time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC),
+		Kind:             Step,
+	}
+
+	data, err := json.Marshal(cp)
+	if err != nil {
+		t.Fatalf("Marshal() err=%v", err)
+	}
+
+	want := `{"version":1,"index":100,"difference":2.5,"confidence":0.999,"statistic":"-Inf","degrees_of_freedom":57.25,` +
+		`"before":{"n":100,"mean":1,"variance":0.5},"after":{"n":80,"mean":3.5,"variance":0},"score":42,` +
+		`"time":"2024-03-01T12:30:00Z","kind":"step"}`
+	if string(data) != want {
+		t.Errorf("Marshal()=%s, wanted %s", data, want)
+	}
+
+	var got ChangePoint
+	if err := json.Unmarshal(data, &got); err != nil || !reflect.DeepEqual(got, cp) {
+		t.Errorf("Unmarshal()=%+v err=%v, wanted %+v", got, err, cp)
+	}
+
+	// a pointer, and the optional fields left out
+	data, _ = json.Marshal(&ChangePoint{Index: 3})
+	if s := string(data); strings.Contains(s, "time") || strings.Contains(s, "kind") || strings.Contains(s, "critical") {
+		t.Errorf("Marshal(no time or kind)=%s, wanted them left out", s)
+	}
+
+	for _, bad := range []string{`{"version":2}`, `{"kind":"wobble"}`, `{"time":"yesterday"}`, `{"score":"lots"}`} {
+		if err := json.Unmarshal([]byte(bad), &got); err == nil {
+			t.Errorf("Unmarshal(%s) err=nil, wanted an error", bad)
+		}
+	}
+}
+
+func TestExtendedChangePointJSON(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	w := steps(rnd, 100, 0, 3)
+	d := New(30, 0.99)
+
+	q := d.CheckQuantile(w, 0.9)
+	r := d.CheckRobust(w, 0)
+	if q == nil || r == nil {
+		t.Fatalf("CheckQuantile()=%v, CheckRobust()=%v, wanted change points", q, r)
+	}
+
+	for _, v := range []interface{}{q, r} {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("Marshal(%T) err=%v", v, err)
+		}
+
+		got := reflect.New(reflect.TypeOf(v).Elem()).Interface()
+		if err := json.Unmarshal(data, got); err != nil || !reflect.DeepEqual(got, v) {
+			t.Errorf("Unmarshal(%s)=%+v err=%v, wanted %+v", data, got, err, v)
+		}
+	}
+}
+
+func TestEventJSON(t *testing.T) {
+	ev := Event{Key: "cpu", ChangePoint: &ChangePoint{Index: 3, Kind: Spike}}
+
+	data, err := json.Marshal(ev)
+	if err != nil {
+		t.Fatalf("Marshal() err=%v", err)
+	}
+	if !strings.HasPrefix(string(data), `{"key":"cpu","change_point":{"version":1,"index":3,`) {
+		t.Errorf("Marshal()=%s, wanted the key and the change point", data)
+	}
+
+	var got Event
+	if err := json.Unmarshal(data, &got); err != nil || !reflect.DeepEqual(got, ev) {
+		t.Errorf("Unmarshal()=%+v err=%v, wanted %+v", got, err, ev)
+	}
+}
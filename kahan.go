@@ -0,0 +1,26 @@
+package change
+
+import "math"
+
+// kahan is a running sum using Neumaier's variant of Kahan's compensated
+// summation.  The rounding error of each addition is accumulated separately,
+// so the sum of many large values is as accurate as if it were computed
+// exactly and rounded once.
+type kahan struct {
+	sum float64
+	c   float64
+}
+
+// add adds v to the sum
+func (k *kahan) add(v float64) {
+	t := k.sum + v
+	if math.Abs(k.sum) >= math.Abs(v) {
+		k.c += (k.sum - t) + v
+	} else {
+		k.c += (v - t) + k.sum
+	}
+	k.sum = t
+}
+
+// value returns the sum
+func (k *kahan) value() float64 { return k.sum + k.c }
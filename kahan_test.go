@@ -0,0 +1,44 @@
+package change
+
+import (
+	"math"
+	"testing"
+)
+
+func TestKahan(t *testing.T) {
+	var tests = []struct {
+		xs   []float64
+		want float64
+	}{
+		{[]float64{1, 2, 3}, 6},
+		{[]float64{1, 1e100, 1, -1e100}, 2},
+		{[]float64{1e16, 1, 1, 1, 1, -1e16}, 4},
+	}
+
+	for _, tt := range tests {
+		var k kahan
+		for _, x := range tt.xs {
+			k.add(x)
+		}
+		if got := k.value(); got != tt.want {
+			t.Errorf("kahan(%v)=%v, wanted %v", tt.xs, got, tt.want)
+		}
+	}
+}
+
+func TestPrefixSumsPrecision(t *testing.T) {
+	// a large offset with a small repeating pattern, whose naive running sum
+	// loses the pattern to rounding
+	const offset = 1e15
+	var series []float64
+	for i := 0; i < 100000; i++ {
+		series = append(series, offset+float64(i%4)*0.25)
+	}
+
+	cumsum, _ := prefixSums(series, 0)
+
+	n := float64(len(series))
+	if got, want := cumsum[len(series)]/n-offset, 0.375; math.Abs(got-want) > 1e-3 {
+		t.Errorf("prefixSums mean=offset+%v, wanted offset+%v", got, want)
+	}
+}
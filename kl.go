@@ -0,0 +1,97 @@
+package change
+
+import (
+	"math"
+	"sort"
+)
+
+// Binning is a strategy for choosing the edges of histogram bins
+type Binning int
+
+const (
+	// EqualWidth divides the range of the data into bins of the same width
+	EqualWidth Binning = iota
+
+	// EqualFrequency places the bin edges at quantiles of the data, so each
+	// bin holds about the same number of items
+	EqualFrequency
+)
+
+// DefaultBins is the number of histogram bins used if none is given
+const DefaultBins = 10
+
+// KLDivergence scores splits by the symmetric Kullback-Leibler divergence
+// between histograms of the items before and after the split.  It detects
+// changes in the shape of the distribution, such as one mode of a bimodal
+// metric growing at the expense of the other, which the mean-based scatter
+// ignores.
+//
+// The bins are chosen from the whole window, so both histograms share them,
+// and half an item is added to each bin so that empty bins do not give
+// infinite divergences.  The divergence is scaled by n1*n2/(n1+n2) so that
+// splits near the edges of the window are not favoured by their noise.
+type KLDivergence struct {
+	// Bins is the number of histogram bins.  If zero, DefaultBins is used.
+	Bins int
+
+	// Binning is the strategy for choosing the bin edges
+	Binning Binning
+}
+
+// Score implements the Scorer interface
+func (k KLDivergence) Score(before, after []float64) float64 {
+	bins := k.Bins
+	if bins == 0 {
+		bins = DefaultBins
+	}
+
+	pooled := append(append([]float64(nil), before...), after...)
+	edges := binEdges(pooled, bins, k.Binning)
+
+	p := histogram(before, edges)
+	q := histogram(after, edges)
+
+	var kl float64
+	for i := range p {
+		kl += (p[i] - q[i]) * math.Log(p[i]/q[i])
+	}
+
+	n1, n2 := float64(len(before)), float64(len(after))
+	return n1 * n2 / (n1 + n2) * kl
+}
+
+// binEdges returns the inner edges dividing xs into the given number of bins
+func binEdges(xs []float64, bins int, binning Binning) []float64 {
+	sorted := append([]float64(nil), xs...)
+	sort.Float64s(sorted)
+
+	edges := make([]float64, bins-1)
+	switch binning {
+	case EqualFrequency:
+		for i := range edges {
+			edges[i] = sorted[(i+1)*len(sorted)/bins]
+		}
+	default:
+		lo, hi := sorted[0], sorted[len(sorted)-1]
+		for i := range edges {
+			edges[i] = lo + (hi-lo)*float64(i+1)/float64(bins)
+		}
+	}
+
+	return edges
+}
+
+// histogram returns the smoothed relative frequencies of xs in the bins separated by edges
+func histogram(xs []float64, edges []float64) []float64 {
+	h := make([]float64, len(edges)+1)
+	for _, x := range xs {
+		h[sort.SearchFloat64s(edges, x)]++
+	}
+
+	total := float64(len(xs)) + 0.5*float64(len(h))
+	for i := range h {
+		h[i] = (h[i] + 0.5) / total
+	}
+
+	return h
+}
@@ -0,0 +1,31 @@
+package change
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestKLDivergence(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// a bimodal metric whose upper mode moves out and shrinks, from half the
+	// items at 4 to a quarter of them at 8, so that the mean stays at 2
+	var w []float64
+	for i := 0; i < 300; i++ {
+		v := 0.2 * rnd.NormFloat64()
+		switch {
+		case i < 150 && i%2 == 0:
+			v += 4
+		case i >= 150 && i%4 == 0:
+			v += 8
+		}
+		w = append(w, v)
+	}
+
+	for _, b := range []Binning{EqualWidth, EqualFrequency} {
+		d := Detector{MinSampleSize: 20, Scorer: KLDivergence{Binning: b}, Test: KolmogorovSmirnov{}, MinConfidence: 0.99}
+		if r := d.Check(w); r == nil || r.Index < 140 || r.Index > 160 {
+			t.Errorf("Check with KLDivergence binning=%d=%+v, wanted change near 150", b, r)
+		}
+	}
+}
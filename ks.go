@@ -0,0 +1,82 @@
+package change
+
+import (
+	"math"
+	"sort"
+)
+
+// KolmogorovSmirnov is the two-sample Kolmogorov-Smirnov test.  It compares
+// the empirical distribution functions of the two samples, so it is sensitive
+// to any difference in distribution (shape, spread) and not just a shift in
+// the mean.
+//
+// The reported statistic is D, the largest distance between the two
+// empirical distribution functions.  The confidence is computed from the
+// asymptotic Kolmogorov distribution.  The change point is accepted if the
+// confidence is above the detector's MinConfidence, which is equivalent to D
+// exceeding the critical value at that confidence.
+type KolmogorovSmirnov struct{}
+
+// Test implements the Test interface
+func (KolmogorovSmirnov) Test(before, after []float64) (float64, float64) {
+	d := ksStatistic(before, after)
+
+	n1, n2 := float64(len(before)), float64(len(after))
+	ne := math.Sqrt(n1 * n2 / (n1 + n2))
+
+	return d, 1 - ksProb((ne+0.12+0.11/ne)*d)
+}
+
+// ksStatistic returns the two-sample Kolmogorov-Smirnov D statistic
+func ksStatistic(xs, ys []float64) float64 {
+	x := append([]float64(nil), xs...)
+	y := append([]float64(nil), ys...)
+	sort.Float64s(x)
+	sort.Float64s(y)
+
+	n1, n2 := float64(len(x)), float64(len(y))
+
+	var d float64
+	var i, j int
+	for i < len(x) && j < len(y) {
+		v := math.Min(x[i], y[j])
+		for i < len(x) && x[i] == v {
+			i++
+		}
+		for j < len(y) && y[j] == v {
+			j++
+		}
+
+		if diff := math.Abs(float64(i)/n1 - float64(j)/n2); diff > d {
+			d = diff
+		}
+	}
+
+	return d
+}
+
+// ksProb returns the Kolmogorov distribution survival function Q(lambda)
+func ksProb(lambda float64) float64 {
+	if lambda < 0.2 {
+		// the series converges very slowly here, and the value is 1 to within float precision
+		return 1
+	}
+
+	const eps1, eps2 = 1e-6, 1e-16
+
+	a2 := -2 * lambda * lambda
+	fac := 2.0
+	var sum, prev float64
+	for k := 1; k <= 100; k++ {
+		term := fac * math.Exp(a2*float64(k*k))
+		sum += term
+		if math.Abs(term) <= eps1*prev || math.Abs(term) <= eps2*sum {
+			return sum
+		}
+		fac = -fac
+		prev = math.Abs(term)
+	}
+
+	// failed to converge
+	return 1
+}
@@ -0,0 +1,42 @@
+package change
+
+import (
+	"math"
+	"testing"
+)
+
+func TestKolmogorovSmirnov(t *testing.T) {
+
+	var tests = []struct {
+		before, after []float64
+		d             float64
+		significant   bool
+	}{
+		{
+			[]float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+			[]float64{11, 12, 13, 14, 15, 16, 17, 18, 19, 20},
+			1,
+			true,
+		},
+		{
+			[]float64{1, 3, 5, 7, 9, 11, 13, 15, 17, 19},
+			[]float64{2, 4, 6, 8, 10, 12, 14, 16, 18, 20},
+			0.1,
+			false,
+		},
+		{
+			// same mean, very different spread
+			[]float64{-10, 10, -10, 10, -10, 10, -10, 10, -10, 10, -10, 10, -10, 10, -10, 10},
+			[]float64{-1, 1, -1, 1, -1, 1, -1, 1, -1, 1, -1, 1, -1, 1, -1, 1},
+			0.5,
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		d, conf := KolmogorovSmirnov{}.Test(tt.before, tt.after)
+		if math.Abs(d-tt.d) > 1e-9 || (conf >= 0.95) != tt.significant {
+			t.Errorf("KolmogorovSmirnov(%v, %v)=(%f, %f), wanted D=%f significant=%v", tt.before, tt.after, d, conf, tt.d, tt.significant)
+		}
+	}
+}
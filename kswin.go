@@ -0,0 +1,111 @@
+package change
+
+import (
+	"math"
+	"math/rand"
+)
+
+// KSWIN is the Kolmogorov-Smirnov windowing drift detector of Raab et al.
+// (2020).  It keeps a sliding window of recent items, and compares the newest
+// items in the window against a random sample of the older ones with a
+// two-sample Kolmogorov-Smirnov test.  This detects changes in the shape of
+// the distribution that mean-based detectors miss.
+type KSWIN struct {
+	windowSize int
+	statSize   int
+	alpha      float64
+	rnd        *rand.Rand
+
+	items int
+	data  []float64
+
+	sample []float64
+	idx    []int
+}
+
+// NewKSWIN constructs a KSWIN detector.  The window holds windowSize items, of
+// which the newest statSize are tested against an equal sized sample of the
+// rest, so windowSize must be at least twice statSize; a smaller window is
+// widened to that, and a statSize less than 1 is taken as 1.  A drift is
+// reported when the test's p-value is below alpha.  The sample is drawn using
+// rnd; if it is nil, a fixed seed is used so results are reproducible.
+func NewKSWIN(windowSize, statSize int, alpha float64, rnd *rand.Rand) *KSWIN {
+	if rnd == nil {
+		rnd = rand.New(rand.NewSource(1))
+	}
+	if statSize < 1 {
+		statSize = 1
+	}
+	if windowSize < 2*statSize {
+		windowSize = 2 * statSize
+	}
+
+	return &KSWIN{
+		windowSize: windowSize,
+		statSize:   statSize,
+		alpha:      alpha,
+		rnd:        rnd,
+		data:       make([]float64, 0, windowSize),
+		sample:     make([]float64, statSize),
+		idx:        make([]int, windowSize-statSize),
+	}
+}
+
+// Push adds a float to the stream and returns a change point if drift is
+// detected.  The change point's Index is the offset in the stream of the first
+// of the newest items that were tested, Statistic is the Kolmogorov-Smirnov D
+// statistic, Before is the statistics of the sample of older items, and After
+// is the statistics of the newest items.  After a drift the window is cut
+// back to just the newest items.
+func (k *KSWIN) Push(item float64) *ChangePoint {
+	k.items++
+
+	if len(k.data) == k.windowSize {
+		copy(k.data, k.data[1:])
+		k.data = k.data[:len(k.data)-1]
+	}
+	k.data = append(k.data, item)
+
+	if len(k.data) < k.windowSize {
+		return nil
+	}
+
+	split := k.windowSize - k.statSize
+	recent := k.data[split:]
+
+	// partial Fisher-Yates shuffle to sample the older items without replacement
+	for i := range k.idx {
+		k.idx[i] = i
+	}
+	for i := range k.sample {
+		j := i + k.rnd.Intn(split-i)
+		k.idx[i], k.idx[j] = k.idx[j], k.idx[i]
+		k.sample[i] = k.data[k.idx[i]]
+	}
+
+	d := ksStatistic(k.sample, recent)
+
+	n := float64(k.statSize)
+	ne := math.Sqrt(n / 2)
+	p := ksProb((ne + 0.12 + 0.11/ne) * d)
+
+	if p >= k.alpha {
+		return nil
+	}
+
+	before, after := sampleStats(k.sample), sampleStats(recent)
+
+	cp := &ChangePoint{
+		Index:      k.items - k.statSize,
+		Difference: after.Mean() - before.Mean(),
+		Confidence: 1 - p,
+		Statistic:  d,
+		Before:     before,
+		After:      after,
+	}
+
+	copy(k.data, recent)
+	k.data = k.data[:k.statSize]
+
+	return cp
+}
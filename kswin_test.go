@@ -0,0 +1,61 @@
+package change
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestKSWIN(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	var series []float64
+	for i := 0; i < 2000; i++ {
+		v := rnd.NormFloat64()
+		if i >= 1000 {
+			// same mean, much wider spread
+			v *= 5
+		}
+		series = append(series, v)
+	}
+
+	k := NewKSWIN(200, 60, 0.0001, nil)
+
+	var found []int
+	for i, v := range series {
+		if r := k.Push(v); r != nil {
+			found = append(found, i)
+		}
+	}
+
+	if len(found) == 0 {
+		t.Fatalf("KSWIN found no drift")
+	}
+
+	if found[0] < 1000 || found[0] > 1100 {
+		t.Errorf("KSWIN found drift at %v, wanted shortly after 1000", found)
+	}
+}
+
+func TestKSWINSizes(t *testing.T) {
+	var tests = []struct {
+		windowSize, statSize int
+		wantWindow, wantStat int
+	}{
+		{100, 30, 100, 30},
+		{50, 30, 60, 30},
+		{100, 0, 100, 1},
+		{0, -5, 2, 1},
+	}
+
+	for _, tt := range tests {
+		k := NewKSWIN(tt.windowSize, tt.statSize, 0.005, nil)
+		if k.windowSize != tt.wantWindow || k.statSize != tt.wantStat {
+			t.Errorf("NewKSWIN(%d, %d) sizes=%d, %d, wanted %d, %d", tt.windowSize, tt.statSize, k.windowSize, k.statSize, tt.wantWindow, tt.wantStat)
+		}
+
+		// pushing must not panic
+		for i := 0; i < 3*k.windowSize; i++ {
+			k.Push(float64(i % 7))
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package change
+
+import "math"
+
+// Lepage is the Lepage test for a change in location or scale.  It combines
+// the Wilcoxon rank sum statistic, which is sensitive to a shift in location,
+// with the Ansari-Bradley statistic, which is sensitive to a change in spread,
+// so a single test confirms either kind of change.  Like the Mann-Whitney test
+// it uses only ranks, so makes no assumption about the distribution.
+//
+// The reported statistic is the sum of the squares of the two standardised
+// statistics, and the confidence is from its asymptotic chi-square
+// distribution with two degrees of freedom.
+type Lepage struct{}
+
+// Test implements the Test interface
+func (Lepage) Test(before, after []float64) (float64, float64) {
+	n := float64(len(before) + len(after))
+
+	r, _ := ranks(before, after)
+
+	// Ansari-Bradley scores rank items by how far they are from the
+	// outside of the pooled sample, using the midranks for ties
+	ab := make([]float64, len(r))
+	for i, v := range r {
+		ab[i] = math.Min(v, n+1-v)
+	}
+
+	zw := linearRankZ(r, len(before))
+	zab := linearRankZ(ab, len(before))
+
+	l := zw*zw + zab*zab
+
+	return l, chiSquareCDF(l, 2)
+}
+
+// linearRankZ returns the standardised sum of the first m of the scores,
+// using the exact permutation mean and variance so that ties are accounted for
+func linearRankZ(scores []float64, m int) float64 {
+	n := float64(len(scores))
+	m1 := float64(m)
+	m2 := n - m1
+
+	var sum, total float64
+	for i, v := range scores {
+		total += v
+		if i < m {
+			sum += v
+		}
+	}
+	mean := total / n
+
+	var ss float64
+	for _, v := range scores {
+		ss += (v - mean) * (v - mean)
+	}
+
+	variance := m1 * m2 / (n * (n - 1)) * ss
+	if variance == 0 {
+		return 0
+	}
+
+	return (sum - m1*mean) / math.Sqrt(variance)
+}
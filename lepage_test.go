@@ -0,0 +1,41 @@
+package change
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestLinearRankZ(t *testing.T) {
+	// Wilcoxon rank sum of {1,2,3} against {4,5,6}: W=6, E[W]=10.5, Var[W]=5.25
+	r, _ := ranks([]float64{1, 2, 3}, []float64{4, 5, 6})
+	want := (6 - 10.5) / math.Sqrt(5.25)
+
+	if got := linearRankZ(r, 3); math.Abs(got-want) > 1e-12 {
+		t.Errorf("linearRankZ()=%f, wanted %f", got, want)
+	}
+}
+
+func TestLepage(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2))
+
+	// the mean is the same, only the spread changes
+	var xs, ys, zs []float64
+	for i := 0; i < 100; i++ {
+		xs = append(xs, rnd.NormFloat64())
+		ys = append(ys, rnd.NormFloat64())
+		zs = append(zs, 3*rnd.NormFloat64())
+	}
+
+	if _, conf := (Lepage{}).Test(xs, ys); conf > 0.95 {
+		t.Errorf("Lepage confidence=%f for the same distribution", conf)
+	}
+
+	if _, conf := (Lepage{}).Test(xs, zs); conf < 0.999 {
+		t.Errorf("Lepage confidence=%f for a change in scale", conf)
+	}
+
+	if _, conf := (MannWhitney{}).Test(xs, zs); conf > 0.95 {
+		t.Errorf("MannWhitney confidence=%f for a change in scale only", conf)
+	}
+}
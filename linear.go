@@ -0,0 +1,116 @@
+package change
+
+import "math"
+
+// Linear is the residual sum of squares of a least-squares line fitted to the
+// segment, with the item's offset as the independent variable.  Splitting on
+// it finds changes in trend (the start or end of a ramp) rather than just
+// steps in level.  It is intended to be used with the Slope test:
+//
+//	d := change.Detector{Cost: change.Linear{}, Test: change.Slope{}}
+type Linear struct{}
+
+// Fit implements the Cost interface
+func (Linear) Fit(series []float64) func(i, j int) float64 {
+	n := len(series)
+
+	// prefix sums of x, x^2, y, y^2 and xy, where x is the offset and y the value
+	sx := make([]float64, n+1)
+	sxx := make([]float64, n+1)
+	sy := make([]float64, n+1)
+	syy := make([]float64, n+1)
+	sxy := make([]float64, n+1)
+
+	for k, y := range series {
+		x := float64(k)
+		sx[k+1] = sx[k] + x
+		sxx[k+1] = sxx[k] + x*x
+		sy[k+1] = sy[k] + y
+		syy[k+1] = syy[k] + y*y
+		sxy[k+1] = sxy[k] + x*y
+	}
+
+	return func(i, j int) float64 {
+		m := float64(j - i)
+		x, y := sx[j]-sx[i], sy[j]-sy[i]
+
+		cxx := (sxx[j] - sxx[i]) - x*x/m
+		cyy := (syy[j] - syy[i]) - y*y/m
+		cxy := (sxy[j] - sxy[i]) - x*y/m
+
+		if cxx <= 0 {
+			return cyy
+		}
+
+		return math.Max(0, cyy-cxy*cxy/cxx)
+	}
+}
+
+// Slope is a t-test for a difference in the slopes of lines fitted to the
+// samples before and after the change point.  The reported statistic is the
+// t statistic of the difference in slopes, with n1+n2-4 degrees of freedom.
+type Slope struct{}
+
+// Test implements the Test interface
+func (Slope) Test(before, after []float64) (float64, float64) {
+	b1, se1 := linearFit(before)
+	b2, se2 := linearFit(after)
+
+	diff := b2 - b1
+	se := math.Sqrt(se1*se1 + se2*se2)
+
+	if se == 0 {
+		// both samples are perfect lines
+		if diff == 0 {
+			return 0, 0
+		}
+		return math.Copysign(math.Inf(1), diff), 1
+	}
+
+	t := diff / se
+	df := float64(len(before) + len(after) - 4)
+	if df < 1 {
+		return t, 0
+	}
+
+	return t, 1 - betainc(df/2, 0.5, df/(df+t*t))
+}
+
+// linearFit returns the slope of the least-squares line through ys, with the
+// offset of each item as x, and the slope's standard error
+func linearFit(ys []float64) (slope, se float64) {
+	n := float64(len(ys))
+
+	var mx, my float64
+	for i, y := range ys {
+		mx += float64(i)
+		my += y
+	}
+	mx /= n
+	my /= n
+
+	var sxx, sxy float64
+	for i, y := range ys {
+		dx := float64(i) - mx
+		sxx += dx * dx
+		sxy += dx * (y - my)
+	}
+
+	if sxx == 0 {
+		return 0, 0
+	}
+
+	slope = sxy / sxx
+
+	var rss float64
+	for i, y := range ys {
+		r := y - (my + slope*(float64(i)-mx))
+		rss += r * r
+	}
+
+	if len(ys) > 2 {
+		se = math.Sqrt(rss / (n - 2) / sxx)
+	}
+
+	return slope, se
+}
@@ -0,0 +1,53 @@
+package change
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestLinearFit(t *testing.T) {
+	slope, se := linearFit([]float64{1, 3, 5, 7, 9})
+	if math.Abs(slope-2) > 1e-9 || se != 0 {
+		t.Errorf("linearFit()=(%f, %f), wanted (2, 0)", slope, se)
+	}
+
+	cost := Linear{}.Fit([]float64{0, 0, 1, 3, 5, 7, 9})
+	if c := cost(2, 7); math.Abs(c) > 1e-9 {
+		t.Errorf("Linear cost of a line=%f, wanted 0", c)
+	}
+}
+
+func TestSlope(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// flat, then a ramp starting at 100 which the level-based scatter
+	// places much later
+	var w []float64
+	for i := 0; i < 200; i++ {
+		v := rnd.NormFloat64() * 0.5
+		if i >= 100 {
+			v += float64(i-100) * 0.1
+		}
+		w = append(w, v)
+	}
+
+	d := Detector{MinSampleSize: 10, Cost: Linear{}, Test: Slope{}, MinConfidence: 0.999}
+	r := d.Check(w)
+	if r == nil || r.Index < 95 || r.Index > 105 {
+		t.Fatalf("Check with Linear cost=%+v, wanted change near 100", r)
+	}
+
+	if r.Statistic <= 0 {
+		t.Errorf("Slope statistic=%f, wanted an increase", r.Statistic)
+	}
+
+	// a steady trend has no change in slope
+	for i := range w {
+		w[i] = float64(i)*0.1 + rnd.NormFloat64()*0.5
+	}
+
+	if r := d.Check(w); r != nil {
+		t.Errorf("Check with Linear cost found change %+v in a steady trend", r)
+	}
+}
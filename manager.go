@@ -0,0 +1,278 @@
+package change
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Event is a change point found by a Manager in one of its series.  It is
+// encoded as JSON as an object with the fields key and change_point.
+type Event struct {
+	// Key identifies the series
+	Key string `json:"key"`
+
+	// ChangePoint is the change point, with Index the offset in the series
+	ChangePoint *ChangePoint `json:"change_point"`
+}
+
+// Sample is an item of one of the series of a Manager
+type Sample struct {
+	Key   string
+	Time  time.Time
+	Value float64
+}
+
+// DefaultSinkTimeout is how long a Manager waits for its Sink if
+// Manager.SinkTimeout is zero
+const DefaultSinkTimeout = 10 * time.Second
+
+// managerShards is the number of independently locked shards of a Manager's detectors
+const managerShards = 64
+
+// Manager owns a rolling detector for each of many series, such as the
+// metrics of a fleet of servers, keyed by a string.  A detector is created
+// the first time its key is pushed.  It is safe for concurrent use: the
+// detectors are sharded by key, and goroutines pushing to different shards
+// don't contend.
+type Manager struct {
+	// Sink, if set, is notified of each event found by Push, PushAt or
+	// Watch.  Notify runs on the goroutine which pushed the item, which
+	// waits for it to return, so a slow sink holds up ingestion.  The
+	// detector's lock is not held.
+	Sink Sink
+
+	// SinkTimeout bounds each call of Sink.Notify, through the deadline of
+	// its context.  If zero, DefaultSinkTimeout is used.
+	SinkTimeout time.Duration
+
+	// SinkError, if set, is called with each event which Sink returned an
+	// error for
+	SinkError func(ev Event, err error)
+
+	newDetector func(key string) *Rolling
+	shards      [managerShards]managerShard
+}
+
+type managerShard struct {
+	sync.Mutex
+	detectors map[string]*Rolling
+}
+
+// NewManager returns a manager which calls newDetector to create the
+// detector for each new key.  It may be called concurrently for different
+// keys.
+func NewManager(newDetector func(key string) *Rolling) *Manager {
+	m := &Manager{newDetector: newDetector}
+	for i := range m.shards {
+		m.shards[i].detectors = make(map[string]*Rolling)
+	}
+	return m
+}
+
+// shard returns the shard holding the detector for key
+func (m *Manager) shard(key string) *managerShard {
+	// FNV-1a
+	h := uint32(2166136261)
+	for i := 0; i < len(key); i++ {
+		h ^= uint32(key[i])
+		h *= 16777619
+	}
+	return &m.shards[h%managerShards]
+}
+
+// Push adds an item to the series with the given key, and returns an event if
+// its detector found a change point
+func (m *Manager) Push(key string, item float64) *Event {
+	sh := m.shard(key)
+	sh.Lock()
+	ev := m.event(key, sh.detector(m, key).Push(item))
+	sh.Unlock()
+
+	m.notify(context.Background(), ev)
+	return ev
+}
+
+// PushAt is Push for an item with a timestamp
+func (m *Manager) PushAt(key string, t time.Time, item float64) *Event {
+	ev := m.pushAt(key, t, item)
+	m.notify(context.Background(), ev)
+	return ev
+}
+
+// pushAt is PushAt without notifying the sink
+func (m *Manager) pushAt(key string, t time.Time, item float64) *Event {
+	sh := m.shard(key)
+	sh.Lock()
+	defer sh.Unlock()
+
+	return m.event(key, sh.detector(m, key).PushAt(t, item))
+}
+
+// notify passes ev, if any, to the sink, and any error to SinkError
+func (m *Manager) notify(ctx context.Context, ev *Event) {
+	if ev == nil || m.Sink == nil {
+		return
+	}
+
+	timeout := m.SinkTimeout
+	if timeout == 0 {
+		timeout = DefaultSinkTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err := m.Sink.Notify(ctx, *ev); err != nil && m.SinkError != nil {
+		m.SinkError(*ev, err)
+	}
+}
+
+// detector returns the detector for key, creating it if needed.  The shard must be locked.
+func (sh *managerShard) detector(m *Manager, key string) *Rolling {
+	r, ok := sh.detectors[key]
+	if !ok {
+		r = m.newDetector(key)
+		sh.detectors[key] = r
+	}
+	return r
+}
+
+func (m *Manager) event(key string, cp *ChangePoint) *Event {
+	if cp == nil {
+		return nil
+	}
+	return &Event{Key: key, ChangePoint: cp}
+}
+
+// Detector returns the detector for key, or nil if it hasn't been pushed.
+// It must not be used while other goroutines may push to the key.
+func (m *Manager) Detector(key string) *Rolling {
+	sh := m.shard(key)
+	sh.Lock()
+	defer sh.Unlock()
+
+	return sh.detectors[key]
+}
+
+// Remove discards the detector for key, such as for a server which has been
+// retired.  If the key is pushed again, a new detector is created.
+func (m *Manager) Remove(key string) {
+	sh := m.shard(key)
+	sh.Lock()
+	defer sh.Unlock()
+
+	delete(sh.detectors, key)
+}
+
+// Keys returns the keys of the series, in sorted order
+func (m *Manager) Keys() []string {
+	var keys []string
+	for i := range m.shards {
+		sh := &m.shards[i]
+		sh.Lock()
+		for k := range sh.detectors {
+			keys = append(keys, k)
+		}
+		sh.Unlock()
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Watch pushes the samples received from in with PushAt, and sends each event
+// on the returned channel, as one stream for all the series.  The channel is
+// closed once in is closed or ctx is cancelled.  Other goroutines may push
+// to m at the same time.
+func (m *Manager) Watch(ctx context.Context, in <-chan Sample) <-chan Event {
+	out := make(chan Event)
+
+	go func() {
+		defer close(out)
+
+		for {
+			var s Sample
+			var ok bool
+			select {
+			case s, ok = <-in:
+				if !ok {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+
+			ev := m.pushAt(s.Key, s.Time, s.Value)
+			if ev == nil {
+				continue
+			}
+			m.notify(ctx, ev)
+
+			select {
+			case out <- *ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// LabelKey returns a key for a set of labels, such as {host="a",metric="cpu"},
+// which is the same whatever the order of the labels
+func LabelKey(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(name)
+		b.WriteByte('=')
+		b.WriteString(strconv.Quote(labels[name]))
+	}
+	b.WriteByte('}')
+
+	return b.String()
+}
+
+// ParseLabelKey returns the labels of a key made by LabelKey
+func ParseLabelKey(key string) (map[string]string, error) {
+	if len(key) < 2 || key[0] != '{' || key[len(key)-1] != '}' {
+		return nil, fmt.Errorf("change: bad label key %q", key)
+	}
+
+	labels := make(map[string]string)
+	for rest := key[1 : len(key)-1]; rest != ""; {
+		name, after, ok := strings.Cut(rest, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("change: bad label key %q", key)
+		}
+
+		quoted, err := strconv.QuotedPrefix(after)
+		if err != nil {
+			return nil, fmt.Errorf("change: bad label key %q", key)
+		}
+		labels[name], _ = strconv.Unquote(quoted)
+
+		rest = after[len(quoted):]
+		if rest != "" {
+			if rest[0] != ',' || len(rest) == 1 {
+				return nil, fmt.Errorf("change: bad label key %q", key)
+			}
+			rest = rest[1:]
+		}
+	}
+
+	return labels, nil
+}
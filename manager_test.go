@@ -0,0 +1,113 @@
+package change
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestManager(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	series := map[string][]float64{
+		"flat": steps(rnd, 200, 0, 0),
+		"step": steps(rnd, 200, 0, 5),
+	}
+
+	var created []string
+	m := NewManager(func(key string) *Rolling {
+		created = append(created, key)
+		r := NewRolling(New(30, 0.99999), 100)
+		r.Cooldown = 1000
+		return r
+	})
+
+	var events []*Event
+	for i := 0; i < 400; i++ {
+		for _, key := range []string{"flat", "step"} {
+			if ev := m.Push(key, series[key][i]); ev != nil {
+				events = append(events, ev)
+			}
+		}
+	}
+
+	if !reflect.DeepEqual(created, []string{"flat", "step"}) || !reflect.DeepEqual(m.Keys(), created) {
+		t.Errorf("Manager created=%v keys=%v, wanted one detector per key", created, m.Keys())
+	}
+
+	if len(events) != 1 || events[0].Key != "step" || events[0].ChangePoint.Index < 190 || events[0].ChangePoint.Index > 210 {
+		t.Errorf("Manager events=%+v, wanted one in step at 200", events)
+	}
+
+	m.Remove("flat")
+	if m.Detector("flat") != nil || m.Detector("step") == nil {
+		t.Errorf("Remove(flat) left keys=%v", m.Keys())
+	}
+}
+
+func TestManagerWatch(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	w := steps(rnd, 200, 0, 5)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	in := make(chan Sample)
+	go func() {
+		for i, v := range w {
+			for _, key := range []string{"a", "b"} {
+				in <- Sample{Key: key, Time: start.Add(time.Duration(i) * time.Second), Value: v}
+			}
+		}
+		close(in)
+	}()
+
+	m := NewManager(func(string) *Rolling {
+		r := NewRolling(New(30, 0.99999), 100)
+		r.Cooldown = 1000
+		return r
+	})
+
+	found := make(map[string]bool)
+	for ev := range m.Watch(context.Background(), in) {
+		found[ev.Key] = true
+		if !ev.ChangePoint.Time.Equal(start.Add(time.Duration(ev.ChangePoint.Index) * time.Second)) {
+			t.Errorf("Watch() event=%+v, wanted the time of its index", ev.ChangePoint)
+		}
+	}
+
+	if !found["a"] || !found["b"] {
+		t.Errorf("Watch() events for %v, wanted a and b", found)
+	}
+}
+
+func TestLabelKey(t *testing.T) {
+	got := LabelKey(map[string]string{"metric": "cpu", "host": `a"b`})
+	if want := `{host="a\"b",metric="cpu"}`; got != want {
+		t.Errorf("LabelKey()=%s, wanted %s", got, want)
+	}
+
+	if got := LabelKey(nil); got != "{}" {
+		t.Errorf("LabelKey(nil)=%s, wanted {}", got)
+	}
+}
+
+func TestParseLabelKey(t *testing.T) {
+	for _, labels := range []map[string]string{
+		{"metric": "cpu", "host": `a"b`},
+		{"path": `/x,y="z"`},
+		{},
+	} {
+		got, err := ParseLabelKey(LabelKey(labels))
+		if err != nil || !reflect.DeepEqual(got, labels) {
+			t.Errorf("ParseLabelKey(LabelKey(%v))=%v, %v", labels, got, err)
+		}
+	}
+
+	for _, key := range []string{"", "cpu", `{host}`, `{host=a}`, `{host="a",}`, `{host="a"metric="b"}`, `{="a"}`} {
+		if got, err := ParseLabelKey(key); err == nil {
+			t.Errorf("ParseLabelKey(%q)=%v, wanted an error", key, got)
+		}
+	}
+}
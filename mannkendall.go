@@ -0,0 +1,120 @@
+package change
+
+import (
+	"math"
+	"sort"
+)
+
+// Trend is the result of a Mann-Kendall trend test.
+type Trend struct {
+	// S is Kendall's S statistic: the number of increasing pairs of items
+	// minus the number of decreasing pairs
+	S float64
+
+	// Tau is Kendall's rank correlation between the items and time
+	Tau float64
+
+	// Z is the normalised test statistic, positive for an upward trend
+	Z float64
+
+	// Confidence is the two-sided confidence that there is a monotonic trend
+	Confidence float64
+}
+
+// MannKendall performs the Mann-Kendall test for a monotonic trend in series.
+// It can distinguish a series that is steadily rising or falling from one
+// with a single level shift, which Check reports in the same way: run both,
+// and a significant trend with a change point suggests a ramp rather than a
+// step.  The variance of S is corrected for ties.
+func MannKendall(series []float64) Trend {
+	s, v := mannKendallS(series)
+	n := float64(len(series))
+	return newTrend(s, v, n*(n-1)/2)
+}
+
+// SeasonalMannKendall performs the seasonal Mann-Kendall test of Hirsch et
+// al. (1982).  The series is divided into seasons by the given period, such
+// as 24 for hourly data with a daily cycle, and the statistics for each season
+// are summed, so the seasonal cycle itself is not mistaken for a trend.  A
+// period less than 1 is taken as 1, which is the plain Mann-Kendall test.
+func SeasonalMannKendall(series []float64, period int) Trend {
+	if period < 1 {
+		period = 1
+	}
+
+	var s, v, pairs float64
+	season := make([]float64, 0, len(series)/period+1)
+	for k := 0; k < period; k++ {
+		season = season[:0]
+		for i := k; i < len(series); i += period {
+			season = append(season, series[i])
+		}
+
+		sk, vk := mannKendallS(season)
+		s += sk
+		v += vk
+
+		n := float64(len(season))
+		pairs += n * (n - 1) / 2
+	}
+
+	return newTrend(s, v, pairs)
+}
+
+// mannKendallS returns Kendall's S for series and its variance under the null hypothesis
+func mannKendallS(series []float64) (s, variance float64) {
+	n := len(series)
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			switch {
+			case series[j] > series[i]:
+				s++
+			case series[j] < series[i]:
+				s--
+			}
+		}
+	}
+
+	sorted := append([]float64(nil), series...)
+	sort.Float64s(sorted)
+
+	var ties float64
+	for i := 0; i < n; {
+		j := i + 1
+		for j < n && sorted[j] == sorted[i] {
+			j++
+		}
+		t := float64(j - i)
+		ties += t * (t - 1) * (2*t + 5)
+		i = j
+	}
+
+	fn := float64(n)
+	variance = (fn*(fn-1)*(2*fn+5) - ties) / 18
+
+	return s, variance
+}
+
+// newTrend returns the result of the test for statistic s with the given variance and number of pairs
+func newTrend(s, variance, pairs float64) Trend {
+	t := Trend{S: s}
+	if pairs > 0 {
+		t.Tau = s / pairs
+	}
+
+	if variance <= 0 {
+		return t
+	}
+
+	// continuity correction
+	switch {
+	case s > 0:
+		t.Z = (s - 1) / math.Sqrt(variance)
+	case s < 0:
+		t.Z = (s + 1) / math.Sqrt(variance)
+	}
+
+	t.Confidence = math.Erf(math.Abs(t.Z) / math.Sqrt2)
+
+	return t
+}
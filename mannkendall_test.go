@@ -0,0 +1,58 @@
+package change
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestMannKendall(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	tr := MannKendall([]float64{1, 2, 3, 4, 5})
+	if tr.S != 10 || tr.Tau != 1 {
+		t.Errorf("MannKendall() S=%f tau=%f, wanted 10 and 1", tr.S, tr.Tau)
+	}
+
+	var trend, flat []float64
+	for i := 0; i < 100; i++ {
+		trend = append(trend, 0.05*float64(i)+rnd.NormFloat64())
+		flat = append(flat, rnd.NormFloat64())
+	}
+
+	if tr := MannKendall(trend); tr.Z <= 0 || tr.Confidence < 0.999 {
+		t.Errorf("MannKendall() of upward trend=%+v", tr)
+	}
+
+	if tr := MannKendall(flat); tr.Confidence > 0.95 {
+		t.Errorf("MannKendall() of flat series=%+v", tr)
+	}
+}
+
+func TestSeasonalMannKendall(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// a strong daily cycle with no trend, and the same with a slow decline
+	var cycle, declining []float64
+	for i := 0; i < 24*14; i++ {
+		v := 10*math.Sin(2*math.Pi*float64(i)/24) + rnd.NormFloat64()
+		cycle = append(cycle, v)
+		declining = append(declining, v-0.01*float64(i))
+	}
+
+	if tr := SeasonalMannKendall(cycle, 24); tr.Confidence > 0.95 {
+		t.Errorf("SeasonalMannKendall() of cycle=%+v", tr)
+	}
+
+	if tr := SeasonalMannKendall(declining, 24); tr.Z >= 0 || tr.Confidence < 0.999 {
+		t.Errorf("SeasonalMannKendall() of declining cycle=%+v", tr)
+	}
+
+	// a period less than 1 is no seasons at all
+	want := MannKendall(declining)
+	for _, period := range []int{1, 0, -24} {
+		if tr := SeasonalMannKendall(declining, period); tr != want {
+			t.Errorf("SeasonalMannKendall(period=%d)=%+v, wanted %+v", period, tr, want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package change
+
+import (
+	"math"
+	"sort"
+)
+
+// MannWhitney is the Mann-Whitney U test.  It compares the ranks of the two
+// samples rather than their means, so it does not assume the data is normally
+// distributed and is much less affected by skew and outliers than the t-test.
+//
+// The reported statistic is U, the smaller of U1 = R1 - n1(n1+1)/2 and
+// U2 = n1*n2 - U1, where R1 is the sum of the ranks of the first sample.  The
+// confidence uses the normal approximation to the distribution of U, with
+// corrections for ties and continuity.
+type MannWhitney struct{}
+
+// Test implements the Test interface
+func (MannWhitney) Test(before, after []float64) (float64, float64) {
+	n1, n2 := float64(len(before)), float64(len(after))
+	n := n1 + n2
+
+	r, ties := ranks(before, after)
+
+	var r1 float64
+	for _, v := range r[:len(before)] {
+		r1 += v
+	}
+
+	u1 := r1 - n1*(n1+1)/2
+	u := math.Min(u1, n1*n2-u1)
+
+	mu := n1 * n2 / 2
+	sigma := math.Sqrt(n1 * n2 / 12 * ((n + 1) - ties/(n*(n-1))))
+	if sigma == 0 {
+		// every value is the same
+		return u, 0
+	}
+
+	z := (math.Abs(u-mu) - 0.5) / sigma
+	if z <= 0 {
+		return u, 0
+	}
+
+	return u, math.Erf(z / math.Sqrt2)
+}
+
+// ranks returns the ranks of the concatenation of xs and ys, with tied values
+// given the average of the ranks they span.  It also returns the tie
+// correction term sum(t^3 - t) over all groups of t tied values.
+func ranks(xs, ys []float64) ([]float64, float64) {
+	n := len(xs) + len(ys)
+
+	idx := make([]int, n)
+	for i := range idx {
+		idx[i] = i
+	}
+
+	value := func(i int) float64 {
+		if i < len(xs) {
+			return xs[i]
+		}
+		return ys[i-len(xs)]
+	}
+
+	sort.Slice(idx, func(i, j int) bool { return value(idx[i]) < value(idx[j]) })
+
+	r := make([]float64, n)
+	var ties float64
+	for i := 0; i < n; {
+		j := i + 1
+		for j < n && value(idx[j]) == value(idx[i]) {
+			j++
+		}
+
+		// positions i..j-1 are tied and share ranks i+1..j
+		avg := float64(i+1+j) / 2
+		for k := i; k < j; k++ {
+			r[idx[k]] = avg
+		}
+
+		t := float64(j - i)
+		ties += t*t*t - t
+		i = j
+	}
+
+	return r, ties
+}
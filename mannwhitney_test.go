@@ -0,0 +1,58 @@
+package change
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMannWhitney(t *testing.T) {
+
+	var tests = []struct {
+		before, after []float64
+		u             float64
+		significant   bool
+	}{
+		{
+			[]float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+			[]float64{11, 12, 13, 14, 15, 16, 17, 18, 19, 20},
+			0,
+			true,
+		},
+		{
+			[]float64{1, 3, 5, 7, 9, 11, 13, 15, 17, 19},
+			[]float64{2, 4, 6, 8, 10, 12, 14, 16, 18, 20},
+			45,
+			false,
+		},
+		{
+			// one huge outlier doesn't hide the shift
+			[]float64{1, 1, 2, 1, 2, 1, 1, 2, 1, 1000},
+			[]float64{5, 6, 5, 5, 6, 5, 6, 5, 5, 6},
+			10,
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		u, conf := MannWhitney{}.Test(tt.before, tt.after)
+		if u != tt.u || (conf >= 0.95) != tt.significant {
+			t.Errorf("MannWhitney(%v, %v)=(%f, %f), wanted U=%f significant=%v", tt.before, tt.after, u, conf, tt.u, tt.significant)
+		}
+	}
+}
+
+func TestRanks(t *testing.T) {
+	r, ties := ranks([]float64{3, 1, 2}, []float64{2, 5})
+
+	want := []float64{4, 1, 2.5, 2.5, 5}
+	for i := range want {
+		if math.Abs(r[i]-want[i]) > 1e-9 {
+			t.Errorf("ranks()=%v, wanted %v", r, want)
+			break
+		}
+	}
+
+	if ties != 6 {
+		t.Errorf("ranks() ties=%f, wanted 6", ties)
+	}
+}
@@ -0,0 +1,149 @@
+package change
+
+import "math"
+
+// covariance returns the mean vector and unbiased covariance matrix of rows
+func covariance(rows [][]float64) (mean []float64, cov [][]float64) {
+	p := len(rows[0])
+	n := float64(len(rows))
+
+	mean = make([]float64, p)
+	for _, r := range rows {
+		for k, v := range r {
+			mean[k] += v
+		}
+	}
+	for k := range mean {
+		mean[k] /= n
+	}
+
+	cov = make([][]float64, p)
+	for a := range cov {
+		cov[a] = make([]float64, p)
+	}
+
+	for _, r := range rows {
+		for a := 0; a < p; a++ {
+			da := r[a] - mean[a]
+			for b := a; b < p; b++ {
+				cov[a][b] += da * (r[b] - mean[b])
+			}
+		}
+	}
+
+	for a := 0; a < p; a++ {
+		for b := a; b < p; b++ {
+			cov[a][b] /= n - 1
+			cov[b][a] = cov[a][b]
+		}
+	}
+
+	return mean, cov
+}
+
+// newMatrix returns a p by p matrix of zeros
+func newMatrix(p int) [][]float64 {
+	m := make([][]float64, p)
+	for i := range m {
+		m[i] = make([]float64, p)
+	}
+	return m
+}
+
+// solve returns x such that m x = v, using Gaussian elimination with partial
+// pivoting.  It returns false if m is singular.
+func solve(m [][]float64, v []float64) ([]float64, bool) {
+	p := len(v)
+
+	// augmented copy
+	a := make([][]float64, p)
+	for i := range a {
+		a[i] = make([]float64, p+1)
+		copy(a[i], m[i])
+		a[i][p] = v[i]
+	}
+
+	for col := 0; col < p; col++ {
+		pivot := col
+		for r := col + 1; r < p; r++ {
+			if math.Abs(a[r][col]) > math.Abs(a[pivot][col]) {
+				pivot = r
+			}
+		}
+
+		if math.Abs(a[pivot][col]) < 1e-300 {
+			return nil, false
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+
+		for r := col + 1; r < p; r++ {
+			f := a[r][col] / a[col][col]
+			for c := col; c <= p; c++ {
+				a[r][c] -= f * a[col][c]
+			}
+		}
+	}
+
+	x := make([]float64, p)
+	for r := p - 1; r >= 0; r-- {
+		sum := a[r][p]
+		for c := r + 1; c < p; c++ {
+			sum -= a[r][c] * x[c]
+		}
+		x[r] = sum / a[r][r]
+	}
+
+	return x, true
+}
+
+// fCDF returns the cumulative distribution function of the F distribution with d1 and d2 degrees of freedom
+func fCDF(x, d1, d2 float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	return betainc(d1/2, d2/2, d1*x/(d1*x+d2))
+}
+
+// logdet returns the log of the determinant of m, using LU decomposition
+// with partial pivoting.  It returns false if the determinant is not positive.
+func logdet(m [][]float64) (float64, bool) {
+	p := len(m)
+
+	a := make([][]float64, p)
+	for i := range a {
+		a[i] = append([]float64(nil), m[i]...)
+	}
+
+	var ld float64
+	sign := 1.0
+	for col := 0; col < p; col++ {
+		pivot := col
+		for r := col + 1; r < p; r++ {
+			if math.Abs(a[r][col]) > math.Abs(a[pivot][col]) {
+				pivot = r
+			}
+		}
+
+		if a[pivot][col] == 0 {
+			return 0, false
+		}
+		if pivot != col {
+			a[col], a[pivot] = a[pivot], a[col]
+			sign = -sign
+		}
+
+		if a[col][col] < 0 {
+			sign = -sign
+		}
+		ld += math.Log(math.Abs(a[col][col]))
+
+		for r := col + 1; r < p; r++ {
+			f := a[r][col] / a[col][col]
+			for c := col; c < p; c++ {
+				a[r][c] -= f * a[col][c]
+			}
+		}
+	}
+
+	return ld, sign > 0
+}
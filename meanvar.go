@@ -0,0 +1,64 @@
+package change
+
+import "math"
+
+// MeanVariance is a likelihood-ratio test for a change in the mean, the
+// variance, or both, of normally distributed data.  Each side of the change
+// point is allowed its own mean and variance, so a simultaneous shift in level
+// and spread can be significant even when neither alone would be.  It is
+// intended to be used with the Normal cost, which scores splits with the same
+// likelihood:
+//
+//	d := change.Detector{Cost: change.Normal{}, Test: change.MeanVariance{}}
+//
+// The reported statistic is twice the log of the likelihood ratio, and the
+// confidence is from its asymptotic chi-square distribution with two degrees
+// of freedom.  As the statistic is maximised over the candidate splits, this
+// overstates the confidence, so MinConfidence should be set conservatively.
+type MeanVariance struct{}
+
+// Test implements the Test interface
+func (MeanVariance) Test(before, after []float64) (float64, float64) {
+	n1, n2 := float64(len(before)), float64(len(after))
+
+	s1, s2 := sampleStats(before), sampleStats(after)
+	v1, v2 := mleVariance(s1), mleVariance(s2)
+	v := mleVariance(s1.merge(s2))
+
+	stat := (n1+n2)*math.Log(v) - n1*math.Log(v1) - n2*math.Log(v2)
+	if stat < 0 {
+		stat = 0
+	}
+
+	return stat, chiSquareCDF(stat, 2)
+}
+
+// mleVariance returns the maximum likelihood estimate of the variance, bounded away from zero
+func mleVariance(s Stats) float64 {
+	if s.n == 0 {
+		return normalMinVariance
+	}
+	return math.Max(s.variance*float64(s.n-1)/float64(s.n), normalMinVariance)
+}
+
+// merge returns the statistics of the union of the two data sets
+func (s Stats) merge(o Stats) Stats {
+	if s.n == 0 {
+		return o
+	}
+	if o.n == 0 {
+		return s
+	}
+
+	n1, n2 := float64(s.n), float64(o.n)
+	n := n1 + n2
+	d := o.mean - s.mean
+
+	m2 := s.variance*(n1-1) + o.variance*(n2-1) + d*d*n1*n2/n
+
+	return Stats{
+		mean:     s.mean + d*n2/n,
+		variance: m2 / (n - 1),
+		n:        s.n + o.n,
+	}
+}
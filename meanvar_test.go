@@ -0,0 +1,63 @@
+package change
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestChiSquare(t *testing.T) {
+
+	var tests = []struct {
+		x, df, p float64
+	}{
+		{0, 2, 0},
+		{5.991464547107979, 2, 0.95},
+		{3.841458820694124, 1, 0.95},
+		{6.634896601021214, 1, 0.99},
+		{18.30703805327515, 10, 0.95},
+		{124.3421134, 100, 0.95},
+	}
+
+	for _, tt := range tests {
+		if p := chiSquareCDF(tt.x, tt.df); math.Abs(p-tt.p) > 1e-7 {
+			t.Errorf("chiSquareCDF(%f, %f)=%f, wanted %f", tt.x, tt.df, p, tt.p)
+		}
+	}
+}
+
+func TestMeanVariance(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// a small shift in both level and spread
+	var w []float64
+	for i := 0; i < 300; i++ {
+		v := rnd.NormFloat64()
+		if i >= 150 {
+			v = v*2 + 0.3
+		}
+		w = append(w, v)
+	}
+
+	d := Detector{MinSampleSize: 20, Cost: Normal{}, Test: MeanVariance{}, MinConfidence: 0.999}
+	if r := d.Check(w); r == nil || r.Index < 140 || r.Index > 160 {
+		t.Errorf("Check with MeanVariance=%+v, wanted change near 150", r)
+	}
+
+	// no change
+	if r := d.Check(w[:150]); r != nil {
+		t.Errorf("Check with MeanVariance found change %+v in stable window", r)
+	}
+}
+
+func TestStatsMerge(t *testing.T) {
+	xs := []float64{1, 2, 3, 4}
+	ys := []float64{10, 11, 12}
+
+	got := sampleStats(xs).merge(sampleStats(ys))
+	want := sampleStats(append(xs, ys...))
+
+	if got.n != want.n || math.Abs(got.mean-want.mean) > 1e-9 || math.Abs(got.variance-want.variance) > 1e-9 {
+		t.Errorf("merge()=%+v, wanted %+v", got, want)
+	}
+}
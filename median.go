@@ -0,0 +1,51 @@
+package change
+
+import "sort"
+
+// MoodMedian is Mood's median test.  It counts how many items on each side of
+// the change point lie above the median of the whole window, and tests the
+// resulting 2x2 table with Pearson's chi-square test.  As only the side of the
+// median each item falls on matters, a handful of extreme outliers in either
+// segment have no more influence than any other item.
+//
+// The reported statistic is the chi-square statistic, with one degree of freedom.
+type MoodMedian struct{}
+
+// Test implements the Test interface
+func (MoodMedian) Test(before, after []float64) (float64, float64) {
+	pooled := make([]float64, 0, len(before)+len(after))
+	pooled = append(pooled, before...)
+	pooled = append(pooled, after...)
+	sort.Float64s(pooled)
+
+	median := quantile(pooled, 0.5)
+
+	above := func(xs []float64) float64 {
+		var c float64
+		for _, x := range xs {
+			if x > median {
+				c++
+			}
+		}
+		return c
+	}
+
+	n1, n2 := float64(len(before)), float64(len(after))
+	a1, a2 := above(before), above(after)
+
+	n := n1 + n2
+	a := a1 + a2
+	if a == 0 || a == n {
+		// every item is on the same side of the median
+		return 0, 0
+	}
+
+	// sum over the four cells of (observed - expected)^2 / expected
+	var chi float64
+	for _, c := range [][3]float64{{a1, n1, a}, {n1 - a1, n1, n - a}, {a2, n2, a}, {n2 - a2, n2, n - a}} {
+		e := c[1] * c[2] / n
+		chi += (c[0] - e) * (c[0] - e) / e
+	}
+
+	return chi, chiSquareCDF(chi, 1)
+}
@@ -0,0 +1,44 @@
+package change
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestMoodMedianStatistic(t *testing.T) {
+	// 3 of 4 above the pooled median of 4.5 before, 1 of 4 after: chi2=2
+	before := []float64{1, 6, 7, 8}
+	after := []float64{2, 3, 4, 5}
+
+	stat, conf := (MoodMedian{}).Test(before, after)
+	if math.Abs(stat-2) > 1e-12 {
+		t.Errorf("MoodMedian statistic=%f, wanted 2", stat)
+	}
+
+	if want := chiSquareCDF(2, 1); math.Abs(conf-want) > 1e-12 {
+		t.Errorf("MoodMedian confidence=%f, wanted %f", conf, want)
+	}
+}
+
+func TestMoodMedian(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// the median shifts, and a few huge outliers are added to the before segment
+	var before, after []float64
+	for i := 0; i < 100; i++ {
+		before = append(before, rnd.NormFloat64())
+		after = append(after, 1+rnd.NormFloat64())
+	}
+	for i := 0; i < 5; i++ {
+		before[i*20] = 1000
+	}
+
+	if _, conf := (MoodMedian{}).Test(before, after); conf < 0.999 {
+		t.Errorf("MoodMedian confidence=%f for a shift in median", conf)
+	}
+
+	if _, conf := (MoodMedian{}).Test(before[:50], before[50:]); conf > 0.95 {
+		t.Errorf("MoodMedian confidence=%f for the same distribution", conf)
+	}
+}
@@ -0,0 +1,56 @@
+package change
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+type jsonItem struct {
+	Value *float64  `json:"value"`
+	Time  time.Time `json:"time"`
+}
+
+// ParseItem parses an item sent as a message, such as by a message broker.
+// It is either a number as text, such as 1.5, or a JSON object such as
+//
+//	{"value": 1.5, "time": "2024-03-01T12:00:00Z"}
+//
+// in which the time is optional.  The time is zero if there is none.
+func ParseItem(data []byte) (float64, time.Time, error) {
+	data = bytes.TrimSpace(data)
+
+	if len(data) == 0 || data[0] != '{' {
+		v, err := strconv.ParseFloat(string(data), 64)
+		if err != nil {
+			return 0, time.Time{}, fmt.Errorf("change: bad item %q", data)
+		}
+		return v, time.Time{}, nil
+	}
+
+	var j jsonItem
+	if err := json.Unmarshal(data, &j); err != nil {
+		return 0, time.Time{}, fmt.Errorf("change: bad item: %w", err)
+	}
+	if j.Value == nil {
+		return 0, time.Time{}, fmt.Errorf("change: no value in item %s", data)
+	}
+
+	return *j.Value, j.Time, nil
+}
+
+// PushMessage pushes the item held by a message, parsed by ParseItem, to the
+// series with the given key.  An item without a time is given the time the
+// message was received.
+func (m *Manager) PushMessage(key string, data []byte, received time.Time) (*Event, error) {
+	v, t, err := ParseItem(data)
+	if err != nil {
+		return nil, err
+	}
+	if t.IsZero() {
+		t = received
+	}
+	return m.PushAt(key, t, v), nil
+}
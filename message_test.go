@@ -0,0 +1,58 @@
+package change
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseItem(t *testing.T) {
+	ts := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	var tests = []struct {
+		data string
+		v    float64
+		t    time.Time
+		ok   bool
+	}{
+		{"1.5", 1.5, time.Time{}, true},
+		{" -2e3\n", -2000, time.Time{}, true},
+		{`{"value": 3}`, 3, time.Time{}, true},
+		{`{"value": 3, "time": "2024-03-02T00:00:00Z"}`, 3, ts, true},
+		{`{"time": "2024-03-02T00:00:00Z"}`, 0, time.Time{}, false},
+		{`{"value": "3"}`, 0, time.Time{}, false},
+		{"", 0, time.Time{}, false},
+		{"x", 0, time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		v, got, err := ParseItem([]byte(tt.data))
+		if (err == nil) != tt.ok || v != tt.v || !got.Equal(tt.t) {
+			t.Errorf("ParseItem(%q)=%v, %v, %v, wanted %v, %v", tt.data, v, got, err, tt.v, tt.t)
+		}
+	}
+}
+
+func TestPushMessage(t *testing.T) {
+	m := NewManager(func(string) *Rolling { return NewRolling(New(30, 0.99), 100) })
+
+	received := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+	sent := received.Add(-time.Minute)
+
+	if _, err := m.PushMessage("a", []byte("1.5"), received); err != nil {
+		t.Fatalf("PushMessage(1.5) err=%v", err)
+	}
+	if _, err := m.PushMessage("a", []byte(`{"value": 2, "time": "2024-03-01T23:59:00Z"}`), received); err != nil {
+		t.Fatalf("PushMessage(object) err=%v", err)
+	}
+	if _, err := m.PushMessage("a", []byte("x"), received); err == nil {
+		t.Errorf("PushMessage(x) err=nil, wanted an error")
+	}
+
+	r := m.Detector("a")
+	if got := r.Window(); len(got) != 2 || got[0] != 1.5 || got[1] != 2 {
+		t.Errorf("PushMessage() window=%v, wanted [1.5 2]", got)
+	}
+	if got := r.times[:2]; !got[0].Equal(received) || !got[1].Equal(sent) {
+		t.Errorf("PushMessage() times=%v, wanted [%v %v]", got, received, sent)
+	}
+}
@@ -0,0 +1,105 @@
+package change
+
+import "math"
+
+// MEWMA is a multivariate exponentially weighted moving average control chart
+// (Lowry et al., 1992).  It smooths a stream of vector-valued samples and
+// signals a change when the Mahalanobis distance of the smoothed vector from
+// the in-control mean exceeds a chi-square control limit.  It is the
+// streaming companion to CheckMulti.
+type MEWMA struct {
+	mean   []float64
+	cov    [][]float64
+	lambda []float64
+	h      float64
+
+	items int
+
+	// z is the smoothed deviation from the mean, and t the number of items since it was last reset
+	z []float64
+	t int
+
+	zcov [][]float64
+}
+
+// NewMEWMA constructs a MEWMA detector for a stream with the given in-control
+// mean vector and covariance matrix.  Lambda is the diagonal of the smoothing
+// matrix, one weight in (0, 1] for each dimension; 0.1 is a common choice.
+// The control limit is the chi-square quantile at the given confidence, with
+// one degree of freedom per dimension.
+func NewMEWMA(mean []float64, cov [][]float64, lambda []float64, confidence float64) *MEWMA {
+	p := len(mean)
+
+	m := &MEWMA{
+		mean:   mean,
+		cov:    cov,
+		lambda: lambda,
+		h:      chiSquareQuantile(confidence, float64(p)),
+		z:      make([]float64, p),
+		zcov:   make([][]float64, p),
+	}
+
+	for a := range m.zcov {
+		m.zcov[a] = make([]float64, p)
+	}
+
+	return m
+}
+
+// Push adds a sample to the stream and returns a change point if the chart
+// signals.  The change point's Index is the offset in the stream of the
+// sample that triggered the signal, Statistic is the chart's T-squared value,
+// Difference is the smoothed deviation of each dimension from the in-control
+// mean, and Before is the in-control statistics; After is not set.  The chart
+// is restarted after a change is reported.
+func (m *MEWMA) Push(sample []float64) *MultiChangePoint {
+	idx := m.items
+	m.items++
+	m.t++
+
+	for k, l := range m.lambda {
+		m.z[k] = l*(sample[k]-m.mean[k]) + (1-l)*m.z[k]
+	}
+
+	// the exact covariance of z after t items
+	for a, la := range m.lambda {
+		for b, lb := range m.lambda {
+			decay := 1 - math.Pow((1-la)*(1-lb), float64(m.t))
+			m.zcov[a][b] = la * lb * decay / (la + lb - la*lb) * m.cov[a][b]
+		}
+	}
+
+	x, ok := solve(m.zcov, m.z)
+	if !ok {
+		return nil
+	}
+
+	var t2 float64
+	for k := range m.z {
+		t2 += m.z[k] * x[k]
+	}
+
+	if t2 <= m.h {
+		return nil
+	}
+
+	p := len(m.mean)
+	cp := &MultiChangePoint{
+		Index:      idx,
+		Difference: append([]float64(nil), m.z...),
+		Confidence: chiSquareCDF(t2, float64(p)),
+		Statistic:  t2,
+		Before:     make([]Stats, p),
+	}
+
+	for k := range cp.Before {
+		cp.Before[k] = Stats{mean: m.mean[k], variance: m.cov[k][k], n: idx}
+	}
+
+	for k := range m.z {
+		m.z[k] = 0
+	}
+	m.t = 0
+
+	return cp
+}
@@ -0,0 +1,46 @@
+package change
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestChiSquareQuantile(t *testing.T) {
+	for _, df := range []float64{1, 2, 3, 10} {
+		for _, p := range []float64{0.5, 0.95, 0.999} {
+			x := chiSquareQuantile(p, df)
+			if got := chiSquareCDF(x, df); math.Abs(got-p) > 1e-9 {
+				t.Errorf("chiSquareCDF(chiSquareQuantile(%f, %f))=%f", p, df, got)
+			}
+		}
+	}
+}
+
+func TestMEWMA(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// two correlated metrics; the shift breaks their usual relationship
+	// without either leaving its normal range
+	cov := [][]float64{{1, 0.9}, {0.9, 1}}
+
+	m := NewMEWMA([]float64{0, 0}, cov, []float64{0.1, 0.1}, 0.999)
+
+	found := -1
+	for i := 0; i < 1000; i++ {
+		a := rnd.NormFloat64()
+		b := 0.9*a + math.Sqrt(1-0.81)*rnd.NormFloat64()
+		if i >= 500 {
+			a += 0.5
+			b -= 0.5
+		}
+
+		if r := m.Push([]float64{a, b}); r != nil && found == -1 {
+			found = r.Index
+		}
+	}
+
+	if found < 500 || found > 550 {
+		t.Errorf("MEWMA signalled at %d, wanted shortly after 500", found)
+	}
+}
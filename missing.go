@@ -0,0 +1,99 @@
+package change
+
+import "math"
+
+// Missing is a policy for handling NaN and infinite values in a window, such
+// as gaps in a sensor feed.
+type Missing int
+
+const (
+	// MissingReject leaves windows as they are.  CheckValid returns an
+	// error for a window with missing values, and Check's result is
+	// meaningless.
+	MissingReject Missing = iota
+
+	// MissingSkip drops missing values from the window before checking it.
+	// The index of the change point is still the offset into the original
+	// window.
+	MissingSkip
+
+	// MissingInterpolate replaces missing values by linear interpolation
+	// between the nearest values either side, or the nearest value at the
+	// ends of the window.
+	MissingInterpolate
+)
+
+func (m Missing) String() string {
+	switch m {
+	case MissingReject:
+		return "reject"
+	case MissingSkip:
+		return "skip"
+	case MissingInterpolate:
+		return "interpolate"
+	}
+	return "unknown"
+}
+
+// missing reports whether v is a missing value
+func missing(v float64) bool { return math.IsNaN(v) || math.IsInf(v, 0) }
+
+// clean returns window with its missing values handled by the policy, and
+// the offset in window of each item of the result, or nil if the offsets are
+// unchanged.  The window is only copied if it has missing values.
+func (m Missing) clean(window []float64) ([]float64, []int) {
+	if m == MissingReject {
+		return window, nil
+	}
+
+	var found bool
+	for _, v := range window {
+		if missing(v) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return window, nil
+	}
+
+	if m == MissingSkip {
+		var cleaned []float64
+		var offsets []int
+		for i, v := range window {
+			if !missing(v) {
+				cleaned = append(cleaned, v)
+				offsets = append(offsets, i)
+			}
+		}
+		return cleaned, offsets
+	}
+
+	cleaned := append([]float64(nil), window...)
+
+	prev := -1
+	for i := 0; i <= len(cleaned); i++ {
+		if i < len(cleaned) && missing(cleaned[i]) {
+			continue
+		}
+
+		// fill the gap between prev and i
+		for j := prev + 1; j < i; j++ {
+			switch {
+			case prev < 0 && i == len(cleaned):
+				// nothing to interpolate from
+				cleaned[j] = math.NaN()
+			case prev < 0:
+				cleaned[j] = cleaned[i]
+			case i == len(cleaned):
+				cleaned[j] = cleaned[prev]
+			default:
+				frac := float64(j-prev) / float64(i-prev)
+				cleaned[j] = cleaned[prev] + frac*(cleaned[i]-cleaned[prev])
+			}
+		}
+		prev = i
+	}
+
+	return cleaned, nil
+}
@@ -0,0 +1,54 @@
+package change
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestMissingClean(t *testing.T) {
+	nan := math.NaN()
+
+	var tests = []struct {
+		m       Missing
+		window  []float64
+		want    []float64
+		offsets []int
+	}{
+		{MissingSkip, []float64{1, 2, 3}, []float64{1, 2, 3}, nil},
+		{MissingSkip, []float64{1, nan, 3, math.Inf(1)}, []float64{1, 3}, []int{0, 2}},
+		{MissingInterpolate, []float64{1, nan, nan, 4}, []float64{1, 2, 3, 4}, nil},
+		{MissingInterpolate, []float64{nan, 2, 3, nan}, []float64{2, 2, 3, 3}, nil},
+	}
+
+	for _, tt := range tests {
+		got, offsets := tt.m.clean(tt.window)
+		if !reflect.DeepEqual(got, tt.want) || !reflect.DeepEqual(offsets, tt.offsets) {
+			t.Errorf("%v.clean(%v)=%v, %v, wanted %v, %v", tt.m, tt.window, got, offsets, tt.want, tt.offsets)
+		}
+	}
+}
+
+func TestMissing(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// a change at 100, with a gap just before it
+	w := steps(rnd, 100, 0, 2)
+	for i := 80; i < 90; i++ {
+		w[i] = math.NaN()
+	}
+
+	for _, m := range []Missing{MissingSkip, MissingInterpolate} {
+		d := Detector{MinConfidence: 0.99, Missing: m}
+
+		cp, err := d.CheckValid(w)
+		if err != nil || cp == nil || cp.Index < 95 || cp.Index > 105 {
+			t.Errorf("CheckValid(%v)=%+v, %v, wanted index 100", m, cp, err)
+		}
+	}
+
+	if _, err := New(30, 0.99).CheckValid(w); err == nil {
+		t.Errorf("CheckValid(%v)=nil error, wanted ErrNotFinite", MissingReject)
+	}
+}
@@ -0,0 +1,103 @@
+package change
+
+import (
+	"math"
+	"math/rand"
+)
+
+// DefaultPermutations is the number of shuffles used by permutation tests if none is given
+const DefaultPermutations = 199
+
+// MMD is the kernel two-sample test using the maximum mean discrepancy of
+// Gretton et al. (2012) with an RBF kernel.  It can detect subtle changes of
+// any kind in the distribution.  It is intended to be used with the RBF cost,
+// whose split score is proportional to the same discrepancy:
+//
+//	d := change.Detector{Cost: change.RBF{}, Test: change.MMD{}}
+//
+// The reported statistic is the (biased) squared MMD, and the confidence is
+// estimated by a permutation test.  Each test takes O(n^2) time per shuffle.
+type MMD struct {
+	// Gamma is the kernel's bandwidth parameter.  If zero, it is set by the
+	// median heuristic, as for the RBF cost.
+	Gamma float64
+
+	// Permutations is the number of shuffles used to estimate the
+	// confidence, which is at most Permutations/(Permutations+1).  If zero,
+	// DefaultPermutations is used.
+	Permutations int
+
+	// Rand is the source of the shuffles.  If nil, a fixed seed is used so
+	// results are reproducible.
+	Rand *rand.Rand
+}
+
+// Test implements the Test interface
+func (m MMD) Test(before, after []float64) (float64, float64) {
+	pooled := append(append([]float64(nil), before...), after...)
+	n := len(pooled)
+
+	gamma := m.Gamma
+	if gamma == 0 {
+		gamma = medianGamma(pooled)
+	}
+
+	k := make([][]float64, n)
+	for a := range k {
+		k[a] = make([]float64, n)
+		for b := range k[a] {
+			d := pooled[a] - pooled[b]
+			k[a][b] = math.Exp(-gamma * d * d)
+		}
+	}
+
+	// labels are the indexes of the items in the first sample
+	labels := make([]int, n)
+	for i := range labels {
+		labels[i] = i
+	}
+	observed := mmd2(k, labels, len(before))
+
+	permutations := m.Permutations
+	if permutations == 0 {
+		permutations = DefaultPermutations
+	}
+
+	rnd := m.Rand
+	if rnd == nil {
+		rnd = rand.New(rand.NewSource(1))
+	}
+
+	var exceed int
+	for i := 0; i < permutations; i++ {
+		rnd.Shuffle(n, func(a, b int) { labels[a], labels[b] = labels[b], labels[a] })
+		if mmd2(k, labels, len(before)) >= observed {
+			exceed++
+		}
+	}
+
+	return observed, 1 - float64(exceed+1)/float64(permutations+1)
+}
+
+// mmd2 returns the biased squared MMD between the items idx[:n1] and idx[n1:], given the kernel matrix k
+func mmd2(k [][]float64, idx []int, n1 int) float64 {
+	xs, ys := idx[:n1], idx[n1:]
+
+	var kxx, kyy, kxy float64
+	for _, a := range xs {
+		for _, b := range xs {
+			kxx += k[a][b]
+		}
+		for _, b := range ys {
+			kxy += k[a][b]
+		}
+	}
+	for _, a := range ys {
+		for _, b := range ys {
+			kyy += k[a][b]
+		}
+	}
+
+	f1, f2 := float64(len(xs)), float64(len(ys))
+	return kxx/(f1*f1) + kyy/(f2*f2) - 2*kxy/(f1*f2)
+}
@@ -0,0 +1,36 @@
+package change
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestMMD(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	var same, bimodal []float64
+	for i := 0; i < 60; i++ {
+		same = append(same, rnd.NormFloat64())
+		bimodal = append(bimodal, 0.2*rnd.NormFloat64()+1.5*float64(i%2*2-1))
+	}
+
+	var other []float64
+	for i := 0; i < 60; i++ {
+		other = append(other, rnd.NormFloat64())
+	}
+
+	if _, conf := (MMD{}).Test(same, bimodal); conf < 0.99 {
+		t.Errorf("MMD confidence=%f for different distributions, wanted >= 0.99", conf)
+	}
+
+	if _, conf := (MMD{}).Test(same, other); conf > 0.95 {
+		t.Errorf("MMD confidence=%f for the same distribution, wanted < 0.95", conf)
+	}
+
+	// with the RBF cost in the detector
+	w := append(append([]float64(nil), same...), bimodal...)
+	d := Detector{MinSampleSize: 10, Cost: RBF{}, Test: MMD{}, MinConfidence: 0.99}
+	if r := d.Check(w); r == nil || r.Index < 55 || r.Index > 65 {
+		t.Errorf("Check with MMD=%+v, wanted change near 60", r)
+	}
+}
@@ -0,0 +1,165 @@
+package change
+
+// MultiChangePoint is a potential change point found by CheckMulti().
+type MultiChangePoint struct {
+	// Index is the offset into the data set of the suspected change point
+	Index int
+
+	// Difference is the difference in the means of each dimension
+	Difference []float64
+
+	// Confidence is the confidence returned by Hotelling's T-squared test
+	Confidence float64
+
+	// Statistic is Hotelling's T-squared statistic
+	Statistic float64
+
+	// Before is the statistics of each dimension before the change point
+	Before []Stats
+
+	// After is the statistics of each dimension after the change point
+	After []Stats
+}
+
+// CheckMulti returns a potential change point in a window of vector-valued
+// samples, such as CPU, memory, and latency measured together.  Each element
+// of window is one sample, and all samples must have the same number of
+// dimensions.
+//
+// The split is chosen to maximise the between-class scatter summed over the
+// dimensions, with each dimension scaled by its variance so that no single
+// unit of measurement dominates.  It is validated with Hotelling's two-sample
+// T-squared test, which takes the correlations between the dimensions into
+// account.  The detector's Test and Cost are not used.
+func (d *Detector) CheckMulti(window [][]float64) *MultiChangePoint {
+	n := len(window)
+	if n == 0 {
+		return nil
+	}
+	p := len(window[0])
+
+	minSampleSize := d.minSampleSize()
+
+	// the pooled covariance matrix needs more samples than dimensions
+	if minSampleSize <= p {
+		minSampleSize = p + 1
+	}
+
+	cumsum := make([][]float64, p)
+	scale := make([]float64, p)
+	for k := range cumsum {
+		cumsum[k] = make([]float64, n+1)
+		// the variance is taken from sums shifted by the first item, so
+		// that it isn't lost to cancellation for large items
+		shift := window[0][k]
+		var sum, sumsq float64
+		for i, w := range window {
+			cumsum[k][i+1] = cumsum[k][i] + w[k]
+			sum += w[k] - shift
+			sumsq += (w[k] - shift) * (w[k] - shift)
+		}
+
+		s := statsFromSums(sum, sumsq, n, shift)
+		if s.variance > 0 {
+			scale[k] = 1 / s.variance
+		}
+	}
+
+	var maxsb float64
+	var maxsbIdx int
+
+	for l := minSampleSize; l < (n - minSampleSize + 1); l++ {
+		n1, n2 := float64(l), float64(n-l)
+
+		var sb float64
+		for k := range cumsum {
+			mean1 := cumsum[k][l] / n1
+			mean2 := (cumsum[k][n] - cumsum[k][l]) / n2
+			sb += scale[k] * (mean1 - mean2) * (mean1 - mean2)
+		}
+		sb *= n1 * n2 / (n1 + n2)
+
+		if maxsb < sb {
+			maxsb = sb
+			maxsbIdx = l
+		}
+	}
+
+	if maxsbIdx == 0 {
+		return nil
+	}
+
+	t2, conf := hotelling(window[:maxsbIdx], window[maxsbIdx:])
+
+	// not above our threshold
+	if conf <= d.MinConfidence {
+		return nil
+	}
+
+	return newMultiChangePoint(window, maxsbIdx, conf, t2)
+}
+
+// newMultiChangePoint returns a change point at idx in window, with the statistics of each dimension filled in
+func newMultiChangePoint(window [][]float64, idx int, conf, stat float64) *MultiChangePoint {
+	n, p := len(window), len(window[0])
+
+	cp := &MultiChangePoint{
+		Index:      idx,
+		Difference: make([]float64, p),
+		Confidence: conf,
+		Statistic:  stat,
+		Before:     make([]Stats, p),
+		After:      make([]Stats, p),
+	}
+
+	col := make([]float64, n)
+	for k := 0; k < p; k++ {
+		for i, w := range window {
+			col[i] = w[k]
+		}
+		cp.Before[k] = sampleStats(col[:idx])
+		cp.After[k] = sampleStats(col[idx:])
+		cp.Difference[k] = cp.After[k].Mean() - cp.Before[k].Mean()
+	}
+
+	return cp
+}
+
+// hotelling performs Hotelling's two-sample T-squared test, returning the
+// statistic and the confidence that the mean vectors differ
+func hotelling(xs, ys [][]float64) (t2, conf float64) {
+	n1, n2 := float64(len(xs)), float64(len(ys))
+	p := len(xs[0])
+
+	m1, c1 := covariance(xs)
+	m2, c2 := covariance(ys)
+
+	// pooled covariance
+	pooled := make([][]float64, p)
+	diff := make([]float64, p)
+	for a := 0; a < p; a++ {
+		pooled[a] = make([]float64, p)
+		for b := 0; b < p; b++ {
+			pooled[a][b] = ((n1-1)*c1[a][b] + (n2-1)*c2[a][b]) / (n1 + n2 - 2)
+		}
+		diff[a] = m1[a] - m2[a]
+	}
+
+	x, ok := solve(pooled, diff)
+	if !ok {
+		// a singular covariance matrix means no variance in some direction
+		return 0, 0
+	}
+
+	var q float64
+	for a := range diff {
+		q += diff[a] * x[a]
+	}
+	t2 = n1 * n2 / (n1 + n2) * q
+
+	df1 := float64(p)
+	df2 := n1 + n2 - df1 - 1
+	f := df2 / (df1 * (n1 + n2 - 2)) * t2
+
+	return t2, fCDF(f, df1, df2)
+}
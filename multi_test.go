@@ -0,0 +1,76 @@
+package change
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestSolve(t *testing.T) {
+	m := [][]float64{{2, 1}, {1, 3}}
+	x, ok := solve(m, []float64{3, 5})
+	if !ok || math.Abs(x[0]-0.8) > 1e-9 || math.Abs(x[1]-1.4) > 1e-9 {
+		t.Errorf("solve()=%v, %v, wanted [0.8 1.4]", x, ok)
+	}
+
+	if _, ok := solve([][]float64{{1, 2}, {2, 4}}, []float64{1, 1}); ok {
+		t.Errorf("solve() of singular matrix succeeded")
+	}
+}
+
+func TestCheckMulti(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// three metrics on very different scales; only the second one shifts,
+	// and by less than its own noise
+	window := make([][]float64, 200)
+	for i := range window {
+		shift := 0.0
+		if i >= 120 {
+			shift = 0.8
+		}
+		window[i] = []float64{
+			100 + 10*rnd.NormFloat64(),
+			1 + shift + rnd.NormFloat64(),
+			0.01 * rnd.NormFloat64(),
+		}
+	}
+
+	d := New(20, 0.999)
+
+	r := d.CheckMulti(window)
+	if r == nil || r.Index < 110 || r.Index > 130 {
+		t.Fatalf("CheckMulti()=%+v, wanted change near 120", r)
+	}
+
+	if len(r.Before) != 3 || r.Difference[1] < 0.4 {
+		t.Errorf("CheckMulti() differences=%v, wanted ~0.8 in the second dimension", r.Difference)
+	}
+
+	if r := d.CheckMulti(window[:120]); r != nil {
+		t.Errorf("CheckMulti() found change %+v in stable window", r)
+	}
+}
+
+func TestCheckMultiOffset(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// small noise on a large offset, so the per-dimension variances used
+	// for scaling must not be computed from raw sums of squares
+	window := make([][]float64, 200)
+	for i := range window {
+		shift := 0.0
+		if i >= 120 {
+			shift = 0.8
+		}
+		window[i] = []float64{
+			1e9 + 0.01*shift + 0.01*rnd.NormFloat64(),
+			1e9 + rnd.NormFloat64(),
+		}
+	}
+
+	r := New(20, 0.999).CheckMulti(window)
+	if r == nil || r.Index < 110 || r.Index > 130 {
+		t.Fatalf("CheckMulti()=%+v, wanted change near 120", r)
+	}
+}
@@ -0,0 +1,73 @@
+package change
+
+import (
+	"math"
+	"sort"
+)
+
+// Outliers configures the handling of outlying items in windows, which
+// otherwise inflate the variances and can anchor the change point to a
+// single bad reading.  The zero value leaves windows as they are.
+type Outliers struct {
+	// K clips the items more than K standard deviations from the median of
+	// the window back to that distance, with the standard deviation
+	// estimated from the median absolute deviation.  If zero, or if the
+	// median absolute deviation is zero, as for quantised or 0/1 windows
+	// where most items share a value, no items are clipped.
+	K float64
+
+	// Drop is the fraction of items, such as 0.01, dropped from each end of
+	// the window's distribution.  The dropped items are skipped as by
+	// MissingSkip, or interpolated if the Missing policy is
+	// MissingInterpolate, and the index of the change point is still the
+	// offset into the original window.
+	Drop float64
+}
+
+// apply returns a copy of window with its outliers clipped, and those dropped
+// set to NaN.  Missing values in window are ignored, and left as they are.
+func (o Outliers) apply(window []float64) []float64 {
+	if o.K <= 0 && o.Drop <= 0 {
+		return window
+	}
+
+	sorted := make([]float64, 0, len(window))
+	for _, v := range window {
+		if !missing(v) {
+			sorted = append(sorted, v)
+		}
+	}
+	if len(sorted) == 0 {
+		return window
+	}
+	sort.Float64s(sorted)
+
+	lo, hi := math.Inf(-1), math.Inf(1)
+	if o.K > 0 {
+		// a zero MAD would clip every item to the median
+		if m := mad(sorted); m > 0 {
+			median := quantile(sorted, 0.5)
+			spread := o.K * madScale * m
+			lo, hi = median-spread, median+spread
+		}
+	}
+
+	dlo, dhi := math.Inf(-1), math.Inf(1)
+	if o.Drop > 0 {
+		dlo, dhi = quantile(sorted, o.Drop), quantile(sorted, 1-o.Drop)
+	}
+
+	out := make([]float64, len(window))
+	for i, v := range window {
+		switch {
+		case missing(v):
+			out[i] = v
+		case v < dlo || v > dhi:
+			out[i] = math.NaN()
+		default:
+			out[i] = math.Max(lo, math.Min(hi, v))
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,86 @@
+package change
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestOutliersApply(t *testing.T) {
+	w := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 100, math.NaN()}
+
+	clipped := Outliers{K: 3}.apply(w)
+	// the median is 5.5 and the MAD 2.5, so items are clipped to 5.5 +- 11.12
+	if got, want := clipped[9], 5.5+3*madScale*2.5; math.Abs(got-want) > 1e-9 {
+		t.Errorf("apply(K=3)[9]=%f, wanted %f", got, want)
+	}
+	if clipped[0] != 1 || !math.IsNaN(clipped[10]) {
+		t.Errorf("apply(K=3)=%v, wanted the other items unchanged", clipped)
+	}
+
+	dropped := Outliers{Drop: 0.1}.apply(w)
+	var n int
+	for i, v := range dropped {
+		if math.IsNaN(v) {
+			n++
+		} else if v != w[i] {
+			t.Errorf("apply(Drop=0.1)[%d]=%f, wanted %f", i, v, w[i])
+		}
+	}
+	if n != 3 || !math.IsNaN(dropped[0]) || !math.IsNaN(dropped[9]) {
+		t.Errorf("apply(Drop=0.1)=%v, wanted the ends dropped", dropped)
+	}
+
+	if w[9] != 100 {
+		t.Errorf("apply() modified the window")
+	}
+}
+
+func TestOutliers(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// a small shift, hidden by a few wild readings
+	w := steps(rnd, 100, 0, 1)
+	for _, i := range []int{20, 70, 130, 180} {
+		w[i] = 1000
+	}
+
+	if r := New(30, 0.99).Check(w); r != nil {
+		t.Errorf("Check() with outliers=%+v, wanted nil", r)
+	}
+
+	for _, o := range []Outliers{{K: 3}, {Drop: 0.02}} {
+		d := Detector{MinConfidence: 0.99, Outliers: o}
+		r := d.Check(w)
+		if r == nil || r.Index < 90 || r.Index > 110 {
+			t.Errorf("Check(%+v)=%+v, wanted index 100", o, r)
+		}
+	}
+}
+
+func TestOutliersBinary(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// an error indicator whose rate steps from 5% to 45%: most items are 0,
+	// so the MAD is 0 and clipping would erase every error
+	w := make([]float64, 400)
+	for i := range w {
+		rate := 0.05
+		if i >= 200 {
+			rate = 0.45
+		}
+		if rnd.Float64() < rate {
+			w[i] = 1
+		}
+	}
+
+	for _, d := range []Detector{
+		{MinConfidence: 0.99},
+		{MinConfidence: 0.99, Outliers: Outliers{K: 3}},
+	} {
+		r := d.Check(w)
+		if r == nil || r.Index < 180 || r.Index > 220 {
+			t.Errorf("Check(%+v)=%+v, wanted index 200", d.Outliers, r)
+		}
+	}
+}
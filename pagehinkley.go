@@ -0,0 +1,133 @@
+package change
+
+import "math"
+
+// PageHinkley is the Page-Hinkley test for a change in the mean of a stream.
+// It tracks the cumulative deviation of each item from the running mean, and
+// signals a change when that deviation rises more than lambda above its
+// minimum (or falls more than lambda below its maximum).  Each item is
+// processed in constant time and memory.
+type PageHinkley struct {
+	delta  float64
+	lambda float64
+	reset  bool
+
+	items int
+
+	// offset of the first item since the last reset
+	base int
+
+	// sum and sumsq are of the items less shift, the first item since the
+	// last reset, so they stay small when the items are large
+	n          int
+	shift      float64
+	sum, sumsq float64
+	up, down   phSide
+}
+
+// phSide tracks the cumulative deviation in one direction and where it was most extreme
+type phSide struct {
+	m float64
+
+	min      float64
+	minN     int
+	minSum   float64
+	minSumsq float64
+}
+
+// NewPageHinkley constructs a Page-Hinkley detector.  Delta is the magnitude
+// of change that is tolerated, and lambda is the detection threshold.  If
+// reset is true the detector's state is cleared after each detected change,
+// otherwise it keeps reporting the change for as long as the statistic stays
+// above lambda.
+func NewPageHinkley(delta, lambda float64, reset bool) *PageHinkley {
+	return &PageHinkley{
+		delta:  delta,
+		lambda: lambda,
+		reset:  reset,
+	}
+}
+
+// Push adds a float to the stream and returns a change point if one is
+// detected.  The change point's Index is the offset in the stream of the first
+// item after the change, and Statistic is the Page-Hinkley statistic.
+func (p *PageHinkley) Push(item float64) *ChangePoint {
+	p.items++
+	if p.n == 0 {
+		p.shift = item
+	}
+	p.n++
+	x := item - p.shift
+	p.sum += x
+	p.sumsq += x * x
+	mean := p.sum/float64(p.n) + p.shift
+
+	p.up.m += item - mean - p.delta
+	p.down.m += mean - item - p.delta
+
+	p.up.track(p)
+	p.down.track(p)
+
+	var s *phSide
+	switch {
+	case p.up.m-p.up.min > p.lambda:
+		s = &p.up
+	case p.down.m-p.down.min > p.lambda:
+		s = &p.down
+	default:
+		return nil
+	}
+
+	before := statsFromSums(s.minSum, s.minSumsq, s.minN, p.shift)
+	after := statsFromSums(p.sum-s.minSum, p.sumsq-s.minSumsq, p.n-s.minN, p.shift)
+
+	cp := &ChangePoint{
+		Index:      p.base + s.minN,
+		Difference: after.Mean() - before.Mean(),
+		Statistic:  s.m - s.min,
+		Before:     before,
+		After:      after,
+	}
+
+	if p.reset {
+		p.Reset()
+	}
+
+	return cp
+}
+
+func (s *phSide) track(p *PageHinkley) {
+	if s.m < s.min {
+		s.min = s.m
+		s.minN = p.n
+		s.minSum = p.sum
+		s.minSumsq = p.sumsq
+	}
+}
+
+// Reset clears the detector's state.  Offsets of later change points continue to count from the start of the stream.
+func (p *PageHinkley) Reset() {
+	*p = PageHinkley{
+		delta:  p.delta,
+		lambda: p.lambda,
+		reset:  p.reset,
+		items:  p.items,
+		base:   p.items,
+	}
+}
+
+// statsFromSums returns the statistics of n items, given the sum and sum of
+// squares of the items less shift.  The shift should be close to the items,
+// such as one of them, or the variance is lost to cancellation.
+func statsFromSums(sum, sumsq float64, n int, shift float64) Stats {
+	if n == 0 {
+		return Stats{}
+	}
+
+	fn := float64(n)
+	s := Stats{mean: sum/fn + shift, n: n}
+	if n > 1 {
+		s.variance = math.Max(0, (sumsq-sum*sum/fn)/(fn-1))
+	}
+	return s
+}
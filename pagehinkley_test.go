@@ -0,0 +1,74 @@
+package change
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestPageHinkley(t *testing.T) {
+
+	var tests = []struct {
+		shift float64
+		reset bool
+		found int
+	}{
+		{0, true, 0},
+		{3, true, 1},
+		{-3, true, 1},
+		{3, false, 40}, // keeps reporting without a reset
+	}
+
+	for _, tt := range tests {
+		p := NewPageHinkley(0.1, 20, tt.reset)
+
+		var found int
+		for i := 0; i < 100; i++ {
+			v := 5 + 0.5*float64(i%2*2-1)
+			if i >= 50 {
+				v += tt.shift
+			}
+
+			r := p.Push(v)
+			if r == nil {
+				continue
+			}
+
+			found++
+			if found == 1 && (r.Index < 48 || r.Index > 52) {
+				t.Errorf("PageHinkley shift=%f index=%d, wanted ~50", tt.shift, r.Index)
+			}
+		}
+
+		if (tt.reset && found != tt.found) || (!tt.reset && found < tt.found) {
+			t.Errorf("PageHinkley shift=%f reset=%v found %d changes, wanted %d", tt.shift, tt.reset, found, tt.found)
+		}
+	}
+}
+
+func TestPageHinkleyOffset(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// small noise on a large offset: summing the raw items would lose the
+	// variance to cancellation
+	p := NewPageHinkley(0.1, 20, true)
+	var r *ChangePoint
+	for i := 0; i < 200 && r == nil; i++ {
+		v := 1e9 + 0.5*rnd.NormFloat64()
+		if i >= 100 {
+			v += 3
+		}
+		r = p.Push(v)
+	}
+
+	if r == nil {
+		t.Fatalf("PageHinkley() found no change")
+	}
+
+	if v := r.Before.Var(); math.Abs(v-0.25) > 0.1 {
+		t.Errorf("PageHinkley() before variance=%v, wanted ~0.25", v)
+	}
+	if m := r.Before.Mean(); math.Abs(m-1e9) > 0.5 {
+		t.Errorf("PageHinkley() before mean=%v, wanted ~1e9", m)
+	}
+}
@@ -0,0 +1,47 @@
+package change
+
+import "sync"
+
+// parallelMinSplits is the fewest candidate splits worth scanning in parallel
+const parallelMinSplits = 4096
+
+// scanParallel is scanRange, with the range split between the given number of goroutines
+func (d *Detector) scanParallel(window, cumsum []float64, base float64, lo, hi int, scores []float64, workers int) (int, float64) {
+	type best struct {
+		idx int
+		sb  float64
+	}
+
+	shards := make([]best, workers)
+	size := (hi - lo + workers - 1) / workers
+
+	var wg sync.WaitGroup
+	for w := range shards {
+		slo := lo + w*size
+		shi := slo + size
+		if shi > hi {
+			shi = hi
+		}
+		if slo >= shi {
+			break
+		}
+
+		wg.Add(1)
+		go func(w, slo, shi int) {
+			defer wg.Done()
+			shards[w].idx, shards[w].sb = d.scanRange(window, cumsum, base, slo, shi, scores)
+		}(w, slo, shi)
+	}
+	wg.Wait()
+
+	// the shards are in order, so ties go to the earliest split as in a sequential scan
+	var maxsbIdx int
+	var maxsb float64
+	for _, b := range shards {
+		if maxsb < b.sb {
+			maxsbIdx, maxsb = b.idx, b.sb
+		}
+	}
+
+	return maxsbIdx, maxsb
+}
@@ -0,0 +1,48 @@
+package change
+
+import (
+	"math/rand"
+	"runtime"
+	"testing"
+)
+
+func TestWorkers(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	w := steps(rnd, 10000, 0, 0.1)
+
+	for _, d := range []Detector{
+		{MinConfidence: 0.99},
+		{MinConfidence: 0.99, Cost: Normal{}},
+		{MinConfidence: 0.99, Direction: Decrease},
+	} {
+		want := d.Check(w)
+		wantScores := d.Scores(w)
+
+		for _, workers := range []int{2, 3, 8} {
+			d.Workers = workers
+
+			got := d.Check(w)
+			if (got == nil) != (want == nil) || (got != nil && *got != *want) {
+				t.Errorf("Check(Workers=%d)=%+v, wanted %+v", workers, got, want)
+			}
+
+			scores := d.Scores(w)
+			for i := range scores {
+				if scores[i] != wantScores[i] {
+					t.Errorf("Scores(Workers=%d)[%d]=%f, wanted %f", workers, i, scores[i], wantScores[i])
+					break
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkWorkers(b *testing.B) {
+	w := steps(rand.New(rand.NewSource(1)), 100000, 0, 1)
+	d := Detector{MinConfidence: 0.99, Cost: Normal{}, Workers: runtime.NumCPU()}
+
+	for i := 0; i < b.N; i++ {
+		d.Check(w)
+	}
+}
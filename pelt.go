@@ -0,0 +1,72 @@
+package change
+
+import "math"
+
+// PELT finds the change points in series which minimise the total cost of the
+// segments plus penalty for every change point, using the Pruned Exact Linear
+// Time algorithm of Killick, Fearnhead and Eckley (2012).  If cost is nil, L2
+// is used.  Every segment is at least minSegment items long.
+//
+// A larger penalty gives fewer change points; for data with unit variance,
+// 2*log(n) is a reasonable starting point, or an information criterion may be
+// used, such as BIC.Value(len(series), 1).  The returned change points are the
+// offsets of the first item of each new segment, in increasing order.
+func PELT(series []float64, cost Cost, penalty float64, minSegment int) []int {
+	n := len(series)
+	if minSegment < 1 {
+		minSegment = 1
+	}
+
+	if cost == nil {
+		cost = L2{}
+	}
+	segmentCost := cost.Fit(series)
+
+	// f[t] is the optimal cost of segmenting series[:t], and last[t] the start of its final segment
+	f := make([]float64, n+1)
+	last := make([]int, n+1)
+	for i := range f {
+		f[i] = math.Inf(1)
+	}
+	f[0] = -penalty
+
+	candidates := []int{0}
+
+	for t := minSegment; t <= n; t++ {
+		for _, s := range candidates {
+			if t-s < minSegment {
+				continue
+			}
+			if v := f[s] + segmentCost(s, t) + penalty; v < f[t] {
+				f[t] = v
+				last[t] = s
+			}
+		}
+
+		// prune the candidates that can never be optimal again
+		var keep []int
+		for _, s := range candidates {
+			if t-s < minSegment || f[s]+segmentCost(s, t) <= f[t] {
+				keep = append(keep, s)
+			}
+		}
+		candidates = append(keep, t)
+	}
+
+	if math.IsInf(f[n], 1) {
+		// too short for even a single segment
+		return nil
+	}
+
+	var cps []int
+	for t := last[n]; t > 0; t = last[t] {
+		cps = append(cps, t)
+	}
+
+	// reverse so the change points are in order
+	for i, j := 0, len(cps)-1; i < j; i, j = i+1, j-1 {
+		cps[i], cps[j] = cps[j], cps[i]
+	}
+
+	return cps
+}
@@ -0,0 +1,40 @@
+package change
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// steps returns a series with unit-variance noise around the given levels, each lasting length items
+func steps(rnd *rand.Rand, length int, levels ...float64) []float64 {
+	var series []float64
+	for _, l := range levels {
+		for i := 0; i < length; i++ {
+			series = append(series, l+rnd.NormFloat64())
+		}
+	}
+	return series
+}
+
+func TestPELT(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	var tests = []struct {
+		series []float64
+		want   []int
+	}{
+		{steps(rnd, 100, 0), nil},
+		{steps(rnd, 100, 0, 5), []int{100}},
+		{steps(rnd, 100, 0, 5, 0, -5), []int{100, 200, 300}},
+		{[]float64{1, 2}, nil}, // shorter than minSegment
+	}
+
+	for _, tt := range tests {
+		penalty := 3 * math.Log(float64(len(tt.series)))
+		if got := PELT(tt.series, nil, penalty, 5); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("PELT()=%v, wanted %v", got, tt.want)
+		}
+	}
+}
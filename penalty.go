@@ -0,0 +1,98 @@
+package change
+
+import (
+	"math"
+	"sort"
+)
+
+// Penalty is an information criterion, which gives the penalty for adding a
+// change point to a segmentation.  The penalties assume the cost is twice the
+// negative log-likelihood, as for Normal and Poisson; for L2, which is only
+// proportional to it, the data should have unit variance.
+type Penalty int
+
+const (
+	// AIC is the Akaike information criterion.  It is the most lenient, and
+	// tends to over-segment long series.
+	AIC Penalty = iota
+
+	// BIC is the Bayesian (or Schwarz) information criterion.
+	BIC
+
+	// MBIC is the modified BIC of Zhang and Siegmund (2007), as approximated
+	// by the changepoint package for R.  It is the strictest of the three.
+	MBIC
+)
+
+func (p Penalty) String() string {
+	switch p {
+	case AIC:
+		return "AIC"
+	case BIC:
+		return "BIC"
+	case MBIC:
+		return "MBIC"
+	}
+	return "unknown"
+}
+
+// Value returns the penalty per change point for a series of n items, where
+// each change point changes params parameters of the model: 1 for a change in
+// the mean, or 2 for a change in the mean and variance.  It can be passed to
+// PELT or SegmentPenalized.
+func (p Penalty) Value(n, params int) float64 {
+	k := float64(params)
+	logn := math.Log(float64(n))
+
+	switch p {
+	case AIC:
+		return 2 * k
+	case BIC:
+		return k * logn
+	case MBIC:
+		return (k + 2) * logn
+	}
+	return math.NaN()
+}
+
+// SegmentPenalized finds multiple change points in series by binary
+// segmentation, as Segment, but decides how many change points to keep by
+// penalised cost rather than by significance testing: a segment is only
+// split if the split's score, the reduction in cost from the detector's Cost,
+// exceeds penalty.  The penalty is typically an information criterion, such as
+// BIC.Value(len(series), 1).
+//
+// The change points are returned in order, with Index as the offset into
+// series.  Their confidence is that of the detector's test, but they are not
+// filtered by MinConfidence.
+func (d *Detector) SegmentPenalized(series []float64, penalty float64) []*ChangePoint {
+	pd := *d
+	pd.MinConfidence = math.Inf(-1)
+	pd.Homogeneity = nil
+
+	var cps []*ChangePoint
+	pd.segmentPenalized(series, 0, penalty, &cps)
+
+	cps = pd.limit(cps)
+	sort.Slice(cps, func(i, j int) bool { return cps[i].Index < cps[j].Index })
+
+	return cps
+}
+
+func (d *Detector) segmentPenalized(series []float64, offset int, penalty float64, cps *[]*ChangePoint) {
+	s := d.scan(series)
+	if s.before.n == 0 || s.sb <= penalty {
+		return
+	}
+
+	cp := d.test(series, s)
+	if cp == nil {
+		return
+	}
+
+	cp.Index += offset
+	*cps = append(*cps, cp)
+
+	d.segmentPenalized(series[:s.index], offset, penalty, cps)
+	d.segmentPenalized(series[s.index:], offset+s.index, penalty, cps)
+}
@@ -0,0 +1,59 @@
+package change
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestPenaltyValue(t *testing.T) {
+	n := 1000
+	logn := math.Log(1000)
+
+	var tests = []struct {
+		p      Penalty
+		params int
+		want   float64
+	}{
+		{AIC, 1, 2},
+		{AIC, 2, 4},
+		{BIC, 1, logn},
+		{BIC, 2, 2 * logn},
+		{MBIC, 1, 3 * logn},
+	}
+
+	for _, tt := range tests {
+		if got := tt.p.Value(n, tt.params); math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("%v.Value(%d, %d)=%f, wanted %f", tt.p, n, tt.params, got, tt.want)
+		}
+	}
+}
+
+func TestSegmentPenalized(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	var tests = []struct {
+		series []float64
+		want   []int
+	}{
+		{steps(rnd, 100, 0), nil},
+		{steps(rnd, 100, 0, 3), []int{100}},
+		{steps(rnd, 100, 0, 3, 0, -3), []int{100, 200, 300}},
+	}
+
+	d := New(10, 0)
+
+	for _, tt := range tests {
+		cps := d.SegmentPenalized(tt.series, MBIC.Value(len(tt.series), 1))
+
+		var got []int
+		for _, cp := range cps {
+			got = append(got, cp.Index)
+		}
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SegmentPenalized()=%v, wanted %v", got, tt.want)
+		}
+	}
+}
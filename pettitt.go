@@ -0,0 +1,39 @@
+package change
+
+import "math"
+
+// Pettitt is the nonparametric test of Pettitt (1979) for a single change
+// point, widely used in hydrology and climatology.  It is a rank-based
+// statistic, closely related to the Mann-Whitney U test, evaluated at every
+// possible split.
+//
+// The reported statistic is K, the largest absolute value of the Mann-Whitney
+// statistic over the splits, and the confidence uses Pettitt's approximation
+// to its p-value, which is accurate for p-values below about 0.5.
+type Pettitt struct{}
+
+// Locate implements the Homogeneity interface
+func (Pettitt) Locate(window []float64) (int, float64, float64) {
+	n := len(window)
+	if n < 2 {
+		return 0, 0, 0
+	}
+
+	r, _ := ranks(window, nil)
+
+	// U_t = 2 * (sum of the ranks of the first t items) - t(n+1)
+	var k, sum float64
+	var idx int
+	for t := 1; t < n; t++ {
+		sum += r[t-1]
+		u := math.Abs(2*sum - float64(t)*float64(n+1))
+		if u > k {
+			k, idx = u, t
+		}
+	}
+
+	fn := float64(n)
+	p := 2 * math.Exp(-6*k*k/(fn*fn*fn+fn*fn))
+
+	return idx, k, math.Max(0, 1-p)
+}
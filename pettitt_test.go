@@ -0,0 +1,26 @@
+package change
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestPettitt(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	w := steps(rnd, 50, 0, 1.5)
+
+	idx, k, conf := Pettitt{}.Locate(w)
+	if idx < 45 || idx > 55 || k <= 0 || conf < 0.999 {
+		t.Errorf("Pettitt()=(%d, %f, %f), wanted change near 50", idx, k, conf)
+	}
+
+	d := Detector{Homogeneity: Pettitt{}, MinConfidence: 0.99}
+	if r := d.Check(w); r == nil || r.Index != idx || r.Difference < 1 {
+		t.Errorf("Check with Pettitt=%+v, wanted change at %d", r, idx)
+	}
+
+	if r := d.Check(steps(rnd, 100, 0)); r != nil {
+		t.Errorf("Check with Pettitt found change %+v in stable window", r)
+	}
+}
@@ -0,0 +1,69 @@
+package change
+
+import "math"
+
+// Test implements the Test interface for counts of events per interval,
+// comparing the Poisson rates before and after the change point.  It is
+// intended to be used with Poisson as the cost too:
+//
+//	d := change.Detector{Cost: change.Poisson{}, Test: change.Poisson{}}
+//
+// The reported statistic is twice the log of the likelihood ratio.  By
+// default the confidence is from its chi-square distribution with one degree
+// of freedom; if Exact is set, it is from the exact two-sided test that,
+// given the total count, the count before the change point is binomially
+// distributed in proportion to the number of intervals.
+func (p Poisson) Test(before, after []float64) (float64, float64) {
+	var s1, s2 float64
+	for _, v := range before {
+		s1 += v
+	}
+	for _, v := range after {
+		s2 += v
+	}
+
+	n1, n2 := float64(len(before)), float64(len(after))
+	rate := (s1 + s2) / (n1 + n2)
+
+	// the log-likelihood contribution of each side at its own rate relative to the pooled rate
+	ll := func(s, n float64) float64 {
+		if s == 0 {
+			return 0
+		}
+		return s * math.Log(s/n/rate)
+	}
+
+	var stat float64
+	if rate > 0 {
+		stat = 2 * (ll(s1, n1) + ll(s2, n2))
+	}
+
+	if !p.Exact {
+		return stat, chiSquareCDF(stat, 1)
+	}
+
+	return stat, 1 - binomialTwoSided(s1, s1+s2, n1/(n1+n2))
+}
+
+// binomialTwoSided returns the two-sided p-value of observing k successes in n trials with success probability p
+func binomialTwoSided(k, n, p float64) float64 {
+	if n == 0 {
+		return 1
+	}
+
+	lower := binomialCDF(k, n, p)
+	upper := 1 - binomialCDF(k-1, n, p)
+
+	return math.Min(1, 2*math.Min(lower, upper))
+}
+
+// binomialCDF returns the probability of at most k successes in n trials with success probability p
+func binomialCDF(k, n, p float64) float64 {
+	switch {
+	case k < 0:
+		return 0
+	case k >= n:
+		return 1
+	}
+	return betainc(n-k, k+1, 1-p)
+}
@@ -0,0 +1,57 @@
+package change
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestBinomialCDF(t *testing.T) {
+
+	var tests = []struct {
+		k, n, p, want float64
+	}{
+		{0, 2, 0.5, 0.25},
+		{1, 2, 0.5, 0.75},
+		{2, 2, 0.5, 1},
+		{3, 10, 0.3, 0.6496107184},
+	}
+
+	for _, tt := range tests {
+		if got := binomialCDF(tt.k, tt.n, tt.p); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("binomialCDF(%f, %f, %f)=%f, wanted %f", tt.k, tt.n, tt.p, got, tt.want)
+		}
+	}
+}
+
+// poissonCounts returns n Poisson distributed counts with the given rate
+func poissonCounts(rnd *rand.Rand, n int, rate float64) []float64 {
+	counts := make([]float64, n)
+	for i := range counts {
+		// Knuth's algorithm
+		var k float64
+		for p := rnd.Float64(); p > math.Exp(-rate); p *= rnd.Float64() {
+			k++
+		}
+		counts[i] = k
+	}
+	return counts
+}
+
+func TestPoissonTest(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// a low rate of events that triples at 100
+	w := append(poissonCounts(rnd, 100, 0.5), poissonCounts(rnd, 100, 1.5)...)
+
+	for _, exact := range []bool{false, true} {
+		d := Detector{MinSampleSize: 10, Cost: Poisson{}, Test: Poisson{Exact: exact}, MinConfidence: 0.999}
+		if r := d.Check(w); r == nil || r.Index < 90 || r.Index > 110 {
+			t.Errorf("Check with Poisson exact=%v=%+v, wanted change near 100", exact, r)
+		}
+
+		if r := d.Check(w[:100]); r != nil {
+			t.Errorf("Check with Poisson exact=%v found change %+v in stable window", exact, r)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package change
+
+// prefixBlockMin is the shortest series whose cumulative sums are computed in blocks
+const prefixBlockMin = 64
+
+// cumsumShifted sets dst[i] to the compensated sum of src[:i+1], less shift
+// from each item.
+//
+// A running sum is a chain of dependent additions, so to make use of
+// instruction-level parallelism and SIMD, long series are split into four
+// blocks whose sums are computed at the same time.  Each block's sums are then
+// offset by the total of the earlier blocks, which adds at most one rounding
+// to each sum.
+func cumsumShifted(dst, src []float64, shift float64) {
+	n := len(src)
+	dst = dst[:n]
+
+	if n < prefixBlockMin {
+		var k kahan
+		for i, v := range src {
+			k.add(v - shift)
+			dst[i] = k.value()
+		}
+		return
+	}
+
+	b := n / 4
+
+	// the final sum and compensation of each block
+	var state [8]float64
+	cumsumBlocks(dst, src, shift, b, &state)
+
+	// the last block continues to the end of the series
+	last := kahan{sum: state[3], c: state[7]}
+	for i := 4 * b; i < n; i++ {
+		last.add(src[i] - shift)
+		dst[i] = last.value()
+	}
+
+	var offset kahan
+	for k := 1; k < 4; k++ {
+		offset.add(state[k-1])
+		offset.add(state[k-1+4])
+
+		end := (k + 1) * b
+		if k == 3 {
+			end = n
+		}
+
+		v := offset.value()
+		for i := k * b; i < end; i++ {
+			dst[i] += v
+		}
+	}
+}
+
+// cumsumBlocksGeneric computes the compensated cumulative sums of each of the
+// four blocks of b items at the start of src independently, and stores the
+// final sums and then compensations of the blocks in state
+func cumsumBlocksGeneric(dst, src []float64, shift float64, b int, state *[8]float64) {
+	var k0, k1, k2, k3 kahan
+
+	s0, s1, s2, s3 := src[:b], src[b:2*b], src[2*b:3*b], src[3*b:4*b]
+	d0, d1, d2, d3 := dst[:b], dst[b:2*b], dst[2*b:3*b], dst[3*b:4*b]
+
+	for i := range s0 {
+		k0.add(s0[i] - shift)
+		k1.add(s1[i] - shift)
+		k2.add(s2[i] - shift)
+		k3.add(s3[i] - shift)
+
+		d0[i] = k0.value()
+		d1[i] = k1.value()
+		d2[i] = k2.value()
+		d3[i] = k3.value()
+	}
+
+	*state = [8]float64{k0.sum, k1.sum, k2.sum, k3.sum, k0.c, k1.c, k2.c, k3.c}
+}
@@ -0,0 +1,26 @@
+//go:build !purego
+// +build !purego
+
+package change
+
+// useAVX2 is whether the CPU and OS support AVX2
+var useAVX2 = hasAVX2()
+
+// cumsumBlocks is cumsumBlocksGeneric using SIMD: AVX2, with the four blocks
+// in one vector register, where the CPU supports it, and otherwise SSE2, with
+// two blocks in each.  b must be positive.
+func cumsumBlocks(dst, src []float64, shift float64, b int, state *[8]float64) {
+	if useAVX2 {
+		cumsumBlocksAVX2(dst, src, shift, b, state)
+		return
+	}
+	cumsumBlocksSSE2(dst, src, shift, b, state)
+}
+
+//go:noescape
+func cumsumBlocksAVX2(dst, src []float64, shift float64, b int, state *[8]float64)
+
+//go:noescape
+func cumsumBlocksSSE2(dst, src []float64, shift float64, b int, state *[8]float64)
+
+func hasAVX2() bool
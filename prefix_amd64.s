@@ -0,0 +1,185 @@
+//go:build !purego
+// +build !purego
+
+#include "textflag.h"
+
+// func cumsumBlocksSSE2(dst, src []float64, shift float64, b int, state *[8]float64)
+TEXT ·cumsumBlocksSSE2(SB), NOSPLIT, $0-72
+	MOVQ dst_base+0(FP), DI
+	MOVQ src_base+24(FP), SI
+	MOVQ b+56(FP), CX
+	MOVQ state+64(FP), DX
+
+	// R8 is the distance between blocks in bytes
+	MOVQ CX, R8
+	SHLQ $3, R8
+
+	MOVSD    shift+48(FP), X7
+	UNPCKLPD X7, X7
+
+	// X1 holds the sums of blocks 0 and 1, X9 of blocks 2 and 3, and X8
+	// and X10 their compensations
+	XORPS X1, X1
+	XORPS X8, X8
+	XORPS X9, X9
+	XORPS X10, X10
+
+loop:
+	LEAQ (SI)(R8*2), R9
+	LEAQ (DI)(R8*2), R10
+
+	MOVSD  (SI), X0
+	MOVHPD (SI)(R8*1), X0
+	MOVSD  (R9), X11
+	MOVHPD (R9)(R8*1), X11
+	SUBPD  X7, X0
+	SUBPD  X7, X11
+
+	// Knuth's TwoSum gives the same exact rounding error as the kahan
+	// type, without a branch: t = sum + v, bv = t - sum, bs = t - bv,
+	// c += (sum - bs) + (v - bv)
+	MOVAPD X1, X2
+	ADDPD  X0, X2
+	MOVAPD X9, X12
+	ADDPD  X11, X12
+
+	MOVAPD X2, X3
+	SUBPD  X1, X3
+	MOVAPD X12, X13
+	SUBPD  X9, X13
+
+	MOVAPD X2, X4
+	SUBPD  X3, X4
+	MOVAPD X12, X14
+	SUBPD  X13, X14
+
+	SUBPD X4, X1
+	SUBPD X3, X0
+	ADDPD X0, X1
+	ADDPD X1, X8
+	SUBPD X14, X9
+	SUBPD X13, X11
+	ADDPD X11, X9
+	ADDPD X9, X10
+
+	MOVAPD X2, X1
+	MOVAPD X12, X9
+
+	// dst = sum + c
+	MOVAPD X1, X5
+	ADDPD  X8, X5
+	MOVAPD X9, X15
+	ADDPD  X10, X15
+
+	MOVSD  X5, (DI)
+	MOVHPD X5, (DI)(R8*1)
+	MOVSD  X15, (R10)
+	MOVHPD X15, (R10)(R8*1)
+
+	ADDQ $8, SI
+	ADDQ $8, DI
+	DECQ CX
+	JNZ  loop
+
+	MOVSD  X1, 0(DX)
+	MOVHPD X1, 8(DX)
+	MOVSD  X9, 16(DX)
+	MOVHPD X9, 24(DX)
+	MOVSD  X8, 32(DX)
+	MOVHPD X8, 40(DX)
+	MOVSD  X10, 48(DX)
+	MOVHPD X10, 56(DX)
+	RET
+
+// func cumsumBlocksAVX2(dst, src []float64, shift float64, b int, state *[8]float64)
+TEXT ·cumsumBlocksAVX2(SB), NOSPLIT, $0-72
+	MOVQ dst_base+0(FP), DI
+	MOVQ src_base+24(FP), SI
+	MOVQ b+56(FP), CX
+	MOVQ state+64(FP), DX
+
+	// R8 is the distance between blocks in bytes
+	MOVQ CX, R8
+	SHLQ $3, R8
+
+	VBROADCASTSD shift+48(FP), Y7
+
+	// Y1 holds the sums of the four blocks, and Y8 their compensations
+	VXORPD Y1, Y1, Y1
+	VXORPD Y8, Y8, Y8
+
+avxloop:
+	LEAQ (SI)(R8*2), R9
+	LEAQ (DI)(R8*2), R10
+
+	VMOVSD      (SI), X0
+	VMOVHPD     (SI)(R8*1), X0, X0
+	VMOVSD      (R9), X11
+	VMOVHPD     (R9)(R8*1), X11, X11
+	VINSERTF128 $1, X11, Y0, Y0
+	VSUBPD      Y7, Y0, Y0
+
+	// TwoSum, as for SSE2
+	VADDPD Y0, Y1, Y2
+	VSUBPD Y1, Y2, Y3
+	VSUBPD Y3, Y2, Y4
+	VSUBPD Y4, Y1, Y1
+	VSUBPD Y3, Y0, Y0
+	VADDPD Y0, Y1, Y1
+	VADDPD Y1, Y8, Y8
+	VMOVAPD Y2, Y1
+
+	// dst = sum + c
+	VADDPD       Y8, Y1, Y5
+	VEXTRACTF128 $1, Y5, X6
+
+	VMOVSD  X5, (DI)
+	VMOVHPD X5, (DI)(R8*1)
+	VMOVSD  X6, (R10)
+	VMOVHPD X6, (R10)(R8*1)
+
+	ADDQ $8, SI
+	ADDQ $8, DI
+	DECQ CX
+	JNZ  avxloop
+
+	VMOVUPD Y1, 0(DX)
+	VMOVUPD Y8, 32(DX)
+	VZEROUPPER
+	RET
+
+// func hasAVX2() bool
+TEXT ·hasAVX2(SB), NOSPLIT, $0-1
+	XORL AX, AX
+	XORL CX, CX
+	CPUID
+	CMPL AX, $7
+	JB   noavx2
+
+	// OSXSAVE and AVX
+	MOVL $1, AX
+	XORL CX, CX
+	CPUID
+	ANDL $0x18000000, CX
+	CMPL CX, $0x18000000
+	JNE  noavx2
+
+	// the OS saves the XMM and YMM registers
+	XORL   CX, CX
+	XGETBV
+	ANDL   $6, AX
+	CMPL   AX, $6
+	JNE    noavx2
+
+	MOVL $7, AX
+	XORL CX, CX
+	CPUID
+	ANDL $0x20, BX
+	JZ   noavx2
+
+	MOVB $1, ret+0(FP)
+	RET
+
+noavx2:
+	MOVB $0, ret+0(FP)
+	RET
@@ -0,0 +1,44 @@
+//go:build !purego
+// +build !purego
+
+package change
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestCumsumBlocksAMD64(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	src := steps(rnd, 250, 1e9, 1e9+1)
+	b := len(src) / 4
+
+	var want [8]float64
+	wantSums := make([]float64, 4*b)
+	cumsumBlocksGeneric(wantSums, src, 1e9, b, &want)
+
+	impls := map[string]func([]float64, []float64, float64, int, *[8]float64){"SSE2": cumsumBlocksSSE2}
+	if useAVX2 {
+		impls["AVX2"] = cumsumBlocksAVX2
+	}
+
+	for name, f := range impls {
+		var got [8]float64
+		gotSums := make([]float64, 4*b)
+		f(gotSums, src, 1e9, b, &got)
+
+		if got != want {
+			t.Errorf("cumsumBlocks%s() state=%v, wanted %v", name, got, want)
+		}
+		for i := range wantSums {
+			if gotSums[i] != wantSums[i] {
+				t.Errorf("cumsumBlocks%s()[%d]=%v, wanted %v", name, i, gotSums[i], wantSums[i])
+				break
+			}
+		}
+	}
+
+	if !useAVX2 {
+		t.Logf("AVX2 is not supported, and was not tested")
+	}
+}
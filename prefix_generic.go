@@ -0,0 +1,8 @@
+//go:build !amd64 || purego
+// +build !amd64 purego
+
+package change
+
+func cumsumBlocks(dst, src []float64, shift float64, b int, state *[8]float64) {
+	cumsumBlocksGeneric(dst, src, shift, b, state)
+}
@@ -0,0 +1,85 @@
+package change
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestCumsumBlocks(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	var tests = [][]float64{
+		{1, 1e100, 1, -1e100, 1, 2, 3, 4},
+		steps(rnd, 250, 1e9, 1e9+1),
+		steps(rnd, 1000, 0),
+	}
+
+	for _, src := range tests {
+		b := len(src) / 4
+		for _, shift := range []float64{0, 1e9} {
+			var want, got [8]float64
+			wantSums := make([]float64, 4*b)
+			gotSums := make([]float64, 4*b)
+
+			cumsumBlocksGeneric(wantSums, src, shift, b, &want)
+			cumsumBlocks(gotSums, src, shift, b, &got)
+
+			// the rounding errors are exact, so the sums are identical
+			if got != want {
+				t.Errorf("cumsumBlocks(%d items, %v) state=%v, wanted %v", len(src), shift, got, want)
+			}
+			for i := range wantSums {
+				if gotSums[i] != wantSums[i] {
+					t.Errorf("cumsumBlocks(%d items, %v)[%d]=%v, wanted %v", len(src), shift, i, gotSums[i], wantSums[i])
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestCumsumShifted(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	for _, n := range []int{0, 1, 63, 64, 65, 1000, 1003} {
+		src := steps(rnd, n, 1e9)
+
+		got := make([]float64, n)
+		cumsumShifted(got, src, 1e9)
+
+		// a sequential compensated sum, which the blocks may differ from by a rounding
+		var k kahan
+		for i, v := range src {
+			k.add(v - 1e9)
+			if want := k.value(); math.Abs(got[i]-want) > 1e-12*math.Max(1, math.Abs(want)) {
+				t.Errorf("cumsumShifted(%d items)[%d]=%v, wanted %v", n, i, got[i], want)
+				break
+			}
+		}
+	}
+}
+
+func BenchmarkCumsumShifted(b *testing.B) {
+	src := steps(rand.New(rand.NewSource(1)), 100000, 0)
+	dst := make([]float64, len(src))
+
+	b.SetBytes(int64(8 * len(src)))
+	for i := 0; i < b.N; i++ {
+		cumsumShifted(dst, src, 1)
+	}
+}
+
+func BenchmarkCumsumSequential(b *testing.B) {
+	src := steps(rand.New(rand.NewSource(1)), 100000, 0)
+	dst := make([]float64, len(src))
+
+	b.SetBytes(int64(8 * len(src)))
+	for i := 0; i < b.N; i++ {
+		var k kahan
+		for j, v := range src {
+			k.add(v - 1)
+			dst[j] = k.value()
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package change
+
+import "math"
+
+// Proportion is the two-proportion z-test, for windows of Bernoulli trials
+// such as error indicators or conversions.  Each item is a trial, and any
+// non-zero item counts as a success.  The default between-class scatter is
+// already the right split score for such data, so only the test needs
+// changing:
+//
+//	d := change.Detector{Test: change.Proportion{}}
+//
+// The reported statistic is the z statistic of the difference in proportions.
+type Proportion struct {
+	// Correction applies Yates's continuity correction, which makes the test
+	// more conservative for small samples
+	Correction bool
+}
+
+// Test implements the Test interface
+func (p Proportion) Test(before, after []float64) (float64, float64) {
+	successes := func(xs []float64) float64 {
+		var c float64
+		for _, x := range xs {
+			if x != 0 {
+				c++
+			}
+		}
+		return c
+	}
+
+	n1, n2 := float64(len(before)), float64(len(after))
+	s1, s2 := successes(before), successes(after)
+	p1, p2 := s1/n1, s2/n2
+
+	pooled := (s1 + s2) / (n1 + n2)
+	se := math.Sqrt(pooled * (1 - pooled) * (1/n1 + 1/n2))
+	if se == 0 {
+		return 0, 0
+	}
+
+	diff := math.Abs(p2 - p1)
+	if p.Correction {
+		diff = math.Max(0, diff-0.5*(1/n1+1/n2))
+	}
+
+	z := math.Copysign(diff/se, p2-p1)
+
+	return z, math.Erf(diff / se / math.Sqrt2)
+}
@@ -0,0 +1,52 @@
+package change
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestProportion(t *testing.T) {
+	// 10/100 against 25/100 successes
+	before := make([]float64, 100)
+	after := make([]float64, 100)
+	for i := 0; i < 10; i++ {
+		before[i] = 1
+	}
+	for i := 0; i < 25; i++ {
+		after[i] = 1
+	}
+
+	z, conf := Proportion{}.Test(before, after)
+	if math.Abs(z-2.7915) > 1e-3 || math.Abs(conf-0.99475) > 1e-4 {
+		t.Errorf("Proportion()=(%f, %f), wanted (2.7915, 0.9948)", z, conf)
+	}
+
+	zc, confc := Proportion{Correction: true}.Test(before, after)
+	if zc >= z || confc >= conf {
+		t.Errorf("Proportion with correction=(%f, %f), wanted less than (%f, %f)", zc, confc, z, conf)
+	}
+}
+
+func TestProportionDetector(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// an error rate which rises from 2% to 8%
+	var w []float64
+	for i := 0; i < 1000; i++ {
+		rate := 0.02
+		if i >= 500 {
+			rate = 0.08
+		}
+		var v float64
+		if rnd.Float64() < rate {
+			v = 1
+		}
+		w = append(w, v)
+	}
+
+	d := Detector{MinSampleSize: 50, Test: Proportion{Correction: true}, MinConfidence: 0.999}
+	if r := d.Check(w); r == nil || r.Index < 450 || r.Index > 550 || r.Statistic <= 0 {
+		t.Errorf("Check with Proportion=%+v, wanted increase near 500", r)
+	}
+}
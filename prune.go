@@ -0,0 +1,86 @@
+package change
+
+import (
+	"math"
+	"sort"
+)
+
+// pruneBlock is the number of splits bounded together when pruning
+const pruneBlock = 64
+
+// pruneSlack allows for rounding in the bounds, so that a block which might
+// hold the best split is never pruned
+const pruneSlack = 1 + 1e-9
+
+// scanPruned is scanRange for the default split score, using branch and bound.
+//
+// The between-class scatter of the split at l can be written as
+//
+//	sb(l) = n * D(l)^2 / (l * (n-l))
+//
+// where D(l) is the sum of the first l items less l times the mean of the
+// window.  Within a block of splits, D can't move further from its values at
+// the ends of the block than the largest deviation of an item from the mean
+// allows, and l*(n-l) is smallest at one end of the block as it is concave.
+// This bounds sb over each block in constant time.  The blocks are scanned in
+// order of decreasing bound, until no remaining block can score higher than
+// the best split found.
+func (d *Detector) scanPruned(window, cumsum []float64, base float64, lo, hi int) (int, float64) {
+	if lo >= hi {
+		return 0, 0
+	}
+
+	n := len(window)
+	nf := float64(n)
+	mean := (cumsum[n-1] - base) / nf
+
+	// the items were shifted by the same amount as the sums
+	shift := window[0] - (cumsum[0] - base)
+
+	var maxdev float64
+	for _, v := range window[lo-1 : hi] {
+		if dev := math.Abs(v - shift - mean); dev > maxdev {
+			maxdev = dev
+		}
+	}
+
+	dl := func(l int) float64 { return cumsum[l-1] - base - float64(l)*mean }
+
+	type block struct {
+		lo, hi int
+		bound  float64
+	}
+
+	var blocks []block
+	for a := lo; a < hi; a += pruneBlock {
+		b := a + pruneBlock
+		if b > hi {
+			b = hi
+		}
+
+		// D can rise from one end and fall to the other for at most half the block
+		da, db := dl(a), dl(b-1)
+		maxd := math.Max(math.Abs(da), math.Abs(db)) + maxdev*float64(b-a)/2
+
+		minf := math.Min(float64(a)*(nf-float64(a)), float64(b-1)*(nf-float64(b-1)))
+		blocks = append(blocks, block{lo: a, hi: b, bound: nf * maxd * maxd / minf})
+	}
+
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i].bound > blocks[j].bound })
+
+	var maxsb float64
+	var maxsbIdx int
+	for _, b := range blocks {
+		if b.bound*pruneSlack < maxsb || b.bound == 0 {
+			break
+		}
+
+		// ties go to the earliest split, as in a sequential scan
+		idx, sb := d.scanRange(window, cumsum, base, b.lo, b.hi, nil)
+		if sb > maxsb || (sb == maxsb && sb > 0 && idx < maxsbIdx) {
+			maxsb, maxsbIdx = sb, idx
+		}
+	}
+
+	return maxsbIdx, maxsb
+}
@@ -0,0 +1,48 @@
+package change
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestPrune(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 200; i++ {
+		n := 60 + rnd.Intn(2000)
+		w := steps(rnd, n/3, 0, rnd.NormFloat64(), rnd.NormFloat64())
+		if i%10 == 0 {
+			// a tie between two equally good splits
+			w = []float64{0, 0, 0, 0, 1, 1, 1, 1, 0, 0, 0, 0}
+		}
+
+		for _, dir := range []Direction{AnyDirection, Increase, Decrease} {
+			d := Detector{MinSampleSize: 1 + rnd.Intn(40), Direction: dir}
+			want := d.scan(w)
+
+			d.Prune = true
+			if got := d.scan(w); got.index != want.index || got.sb != want.sb {
+				t.Fatalf("scan(Prune, %d items, %v)=%d sb=%f, wanted %d sb=%f", len(w), dir, got.index, got.sb, want.index, want.sb)
+			}
+		}
+	}
+}
+
+func BenchmarkPrune(b *testing.B) {
+	w := steps(rand.New(rand.NewSource(1)), 100000, 0, 5)
+
+	for _, prune := range []bool{false, true} {
+		d := Detector{MinConfidence: 0.99, Prune: prune}
+
+		name := "full"
+		if prune {
+			name = "pruned"
+		}
+
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				d.scan(w)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package change
+
+import (
+	"math"
+	"sort"
+)
+
+// Quantile detects a shift in a chosen quantile of the distribution, such as
+// the 95th or 99th percentile of latency, which can move while the mean
+// barely does.  It is both a Scorer and a Test: each side of a split is
+// compared by the fraction of its items above the given quantile of the whole
+// window, using a two-proportion test.  This is a generalisation of Mood's
+// median test.
+//
+// The reported statistic is the chi-square statistic of the test, with one
+// degree of freedom.  CheckQuantile uses it to find and report quantile
+// changes.
+type Quantile struct {
+	// Q is the quantile, between 0 and 1
+	Q float64
+}
+
+// Score implements the Scorer interface
+func (q Quantile) Score(before, after []float64) float64 {
+	stat, _ := q.Test(before, after)
+	return stat
+}
+
+// Test implements the Test interface
+func (q Quantile) Test(before, after []float64) (float64, float64) {
+	pooled := append(append([]float64(nil), before...), after...)
+	sort.Float64s(pooled)
+	threshold := quantile(pooled, q.Q)
+
+	above := func(xs []float64) float64 {
+		var c float64
+		for _, x := range xs {
+			if x > threshold {
+				c++
+			}
+		}
+		return c
+	}
+
+	n1, n2 := float64(len(before)), float64(len(after))
+	a1, a2 := above(before), above(after)
+
+	p := (a1 + a2) / (n1 + n2)
+	if p == 0 || p == 1 {
+		return 0, 0
+	}
+
+	z := (a2/n2 - a1/n1) / math.Sqrt(p*(1-p)*(1/n1+1/n2))
+	stat := z * z
+
+	return stat, chiSquareCDF(stat, 1)
+}
+
+// quantile returns the q-th quantile of sorted, interpolating linearly between items
+func quantile(sorted []float64, q float64) float64 {
+	if len(sorted) == 0 {
+		return math.NaN()
+	}
+
+	pos := q * float64(len(sorted)-1)
+	i := int(pos)
+	if i >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
+	}
+
+	frac := pos - float64(i)
+	return sorted[i] + frac*(sorted[i+1]-sorted[i])
+}
+
+// QuantileChangePoint is a potential change point found by CheckQuantile().
+type QuantileChangePoint struct {
+	ChangePoint
+
+	// Quantile is the quantile that was tested
+	Quantile float64
+
+	// BeforeQuantile is the value of the quantile before the change point
+	BeforeQuantile float64
+
+	// AfterQuantile is the value of the quantile after the change point
+	AfterQuantile float64
+}
+
+// CheckQuantile returns a potential change point in the q-th quantile of
+// window, using Quantile to score the splits and to test the best one.  The
+// detector's Test, Cost, and Scorer are not used.  The quantiles are of the
+// window after the detector's Transform, Outliers and Missing policy.
+func (d *Detector) CheckQuantile(window []float64, q float64) *QuantileChangePoint {
+	qd := *d
+	qd.Scorer = Quantile{Q: q}
+	qd.Test = Quantile{Q: q}
+
+	cp := qd.Check(window)
+	if cp == nil {
+		return nil
+	}
+
+	// the quantiles are of the series which was checked
+	p := d.prepare(window)
+	k := p.split(cp.Index)
+	before := append([]float64(nil), p.series[:k]...)
+	after := append([]float64(nil), p.series[k:]...)
+	sort.Float64s(before)
+	sort.Float64s(after)
+
+	return &QuantileChangePoint{
+		ChangePoint:    *cp,
+		Quantile:       q,
+		BeforeQuantile: quantile(before, q),
+		AfterQuantile:  quantile(after, q),
+	}
+}
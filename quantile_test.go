@@ -0,0 +1,72 @@
+package change
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestQuantileFunc(t *testing.T) {
+	sorted := []float64{1, 2, 3, 4, 5}
+
+	var tests = []struct {
+		q, want float64
+	}{
+		{0, 1},
+		{0.5, 3},
+		{0.625, 3.5},
+		{1, 5},
+	}
+
+	for _, tt := range tests {
+		if got := quantile(sorted, tt.q); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("quantile(%f)=%f, wanted %f", tt.q, got, tt.want)
+		}
+	}
+}
+
+func TestCheckQuantile(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// latencies where the tail gets much worse from 300, but the typical
+	// request is as fast as ever
+	var w []float64
+	for i := 0; i < 600; i++ {
+		v := 10 + rnd.ExpFloat64()
+		if i >= 300 && rnd.Float64() < 0.1 {
+			v += 20
+		}
+		w = append(w, v)
+	}
+
+	d := New(50, 0.999)
+
+	r := d.CheckQuantile(w, 0.95)
+	if r == nil || r.Index < 270 || r.Index > 330 {
+		t.Fatalf("CheckQuantile()=%+v, wanted change near 300", r)
+	}
+
+	if r.AfterQuantile < r.BeforeQuantile+10 {
+		t.Errorf("CheckQuantile() p95 before=%f after=%f, wanted a large increase", r.BeforeQuantile, r.AfterQuantile)
+	}
+
+	if r := d.CheckQuantile(w, 0.5); r != nil {
+		t.Errorf("CheckQuantile() found change %+v in the median", r)
+	}
+
+	// the quantiles are of the items which were checked, without the
+	// missing ones
+	var wn []float64
+	for i, v := range w {
+		if i%100 == 50 {
+			wn = append(wn, math.NaN())
+		}
+		wn = append(wn, v)
+	}
+	d.Missing = MissingSkip
+
+	rn := d.CheckQuantile(wn, 0.95)
+	if rn == nil || rn.BeforeQuantile != r.BeforeQuantile || rn.AfterQuantile != r.AfterQuantile {
+		t.Errorf("CheckQuantile() with missing items=%+v, wanted quantiles %f and %f", rn, r.BeforeQuantile, r.AfterQuantile)
+	}
+}
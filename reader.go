@@ -0,0 +1,130 @@
+package change
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrBadRecord is wrapped by the errors Decode returns for a CSV record which
+// doesn't hold an item.  Decoding can carry on with the next record.
+var ErrBadRecord = errors.New("change: bad record")
+
+// Format is the encoding of the items read by a Decoder
+type Format int
+
+const (
+	// Binary items are little-endian IEEE 754 float64s, with no framing
+	Binary Format = iota
+
+	// CSV items are one field of each record of comma-separated text
+	CSV
+)
+
+func (f Format) String() string {
+	switch f {
+	case Binary:
+		return "binary"
+	case CSV:
+		return "csv"
+	}
+	return "unknown"
+}
+
+// Decoder reads items from a stream, such as a file or socket
+type Decoder struct {
+	// Column is the field of each CSV record holding the item
+	Column int
+
+	// Header skips the first CSV record
+	Header bool
+
+	format Format
+	r      *bufio.Reader
+	csv    *csv.Reader
+	buf    [8]byte
+}
+
+// NewDecoder returns a decoder reading items in the given format from r
+func NewDecoder(r io.Reader, format Format) *Decoder {
+	return &Decoder{format: format, r: bufio.NewReader(r)}
+}
+
+// Decode returns the next item.  It returns io.EOF at the end of the stream,
+// and io.ErrUnexpectedEOF if the stream ends part way through a binary item.
+// An error wrapping ErrBadRecord only affects the current CSV record.
+func (dec *Decoder) Decode() (float64, error) {
+	switch dec.format {
+	case Binary:
+		if _, err := io.ReadFull(dec.r, dec.buf[:]); err != nil {
+			return 0, err
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(dec.buf[:])), nil
+
+	case CSV:
+		if dec.csv == nil {
+			dec.csv = csv.NewReader(dec.r)
+			dec.csv.FieldsPerRecord = -1
+			dec.csv.ReuseRecord = true
+			if dec.Header {
+				if _, err := dec.csv.Read(); err != nil {
+					return 0, badRecord(err)
+				}
+			}
+		}
+
+		record, err := dec.csv.Read()
+		if err != nil {
+			return 0, badRecord(err)
+		}
+
+		if dec.Column < 0 || dec.Column >= len(record) {
+			line, _ := dec.csv.FieldPos(0)
+			return 0, fmt.Errorf("%w: line %d has no column %d", ErrBadRecord, line, dec.Column)
+		}
+
+		v, err := strconv.ParseFloat(strings.TrimSpace(record[dec.Column]), 64)
+		if err != nil {
+			line, _ := dec.csv.FieldPos(dec.Column)
+			return 0, fmt.Errorf("%w: line %d: %w", ErrBadRecord, line, err)
+		}
+		return v, nil
+	}
+
+	return 0, fmt.Errorf("change: unknown format %v", dec.format)
+}
+
+// badRecord wraps err with ErrBadRecord if it is a CSV syntax error, rather
+// than an error reading the stream
+func badRecord(err error) error {
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return fmt.Errorf("%w: %w", ErrBadRecord, err)
+	}
+	return err
+}
+
+// Feed pushes every item decoded by dec to r, calling found with each change
+// point.  It returns nil at the end of the stream, or the first error
+// decoding it.
+func (r *Rolling) Feed(dec *Decoder, found func(*ChangePoint)) error {
+	for {
+		v, err := dec.Decode()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if cp := r.Push(v); cp != nil {
+			found(cp)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package change
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDecoder(t *testing.T) {
+	want := []float64{1, -2.5, 3e10, 0}
+
+	var bin bytes.Buffer
+	binary.Write(&bin, binary.LittleEndian, want)
+
+	text := "t,v\n0,1\n1, -2.5\n2,3e10\n3,0\n"
+
+	csvDec := NewDecoder(strings.NewReader(text), CSV)
+	csvDec.Column = 1
+	csvDec.Header = true
+
+	for _, dec := range []*Decoder{NewDecoder(&bin, Binary), csvDec} {
+		var got []float64
+		for {
+			v, err := dec.Decode()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("Decode(%v) err=%v", dec.format, err)
+			}
+			got = append(got, v)
+		}
+
+		if len(got) != len(want) {
+			t.Fatalf("Decode(%v)=%v, wanted %v", dec.format, got, want)
+		}
+		for i := range got {
+			if got[i] != want[i] {
+				t.Errorf("Decode(%v)=%v, wanted %v", dec.format, got, want)
+				break
+			}
+		}
+	}
+
+	if _, err := NewDecoder(bytes.NewReader([]byte{1, 2, 3}), Binary).Decode(); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Decode(partial item) err=%v, wanted %v", err, io.ErrUnexpectedEOF)
+	}
+
+	for _, tt := range []struct {
+		text   string
+		column int
+	}{
+		{"x\n", 0},     // not a number
+		{"1\n", 1},     // no such column
+		{"1,\"2\n", 1}, // unterminated quote
+		{"1,2\"\n", 1}, // bare quote
+	} {
+		dec := NewDecoder(strings.NewReader(tt.text), CSV)
+		dec.Column = tt.column
+		if _, err := dec.Decode(); !errors.Is(err, ErrBadRecord) {
+			t.Errorf("Decode(%q) err=%v, wanted %v", tt.text, err, ErrBadRecord)
+		}
+	}
+
+	dec := NewDecoder(strings.NewReader("1,2\"\n3,4\n"), CSV)
+	dec.Column = 1
+	dec.Decode()
+	if v, err := dec.Decode(); err != nil || v != 4 {
+		t.Errorf("Decode() after a bad record=%v, %v, wanted 4", v, err)
+	}
+}
+
+func TestFeed(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	var text strings.Builder
+	for _, v := range steps(rnd, 200, 0, 5) {
+		text.WriteString(strconv.FormatFloat(v, 'g', -1, 64) + "\n")
+	}
+
+	r := NewRolling(New(30, 0.99), 100)
+
+	var found bool
+	err := r.Feed(NewDecoder(strings.NewReader(text.String()), CSV), func(cp *ChangePoint) {
+		if cp.Index == 200 {
+			found = true
+		}
+	})
+
+	if err != nil || !found {
+		t.Errorf("Feed() err=%v found=%v, wanted a change at index 200", err, found)
+	}
+}
@@ -0,0 +1,153 @@
+package change
+
+import (
+	"math"
+	"sort"
+)
+
+// madScale converts the median absolute deviation to an estimate of the
+// standard deviation of normal data
+const madScale = 1.4826
+
+// Robust compares the locations of either side of a split using the median,
+// or a trimmed mean, with the scale estimated from the median absolute
+// deviation or the winsorized variance.  A single garbage item can move the
+// mean and standard deviation arbitrarily far, but barely moves these, so it
+// neither moves the change point nor fakes a difference.  It is both a Scorer
+// and a Test, and CheckRobust uses it to find and report changes in location.
+//
+// The reported statistic is the difference in locations divided by its
+// standard error, which is approximately normal.  As a Scorer it sorts both
+// sides of every split, so scanning a window of n items takes O(n² log n)
+// time, which limits it to windows of a few thousand items.
+type Robust struct {
+	// Trim is the fraction of items trimmed from each end of a segment
+	// before averaging, less than 0.5.  If zero, or outside [0, 0.5), the
+	// median is used, which is the limit of the trimmed mean.
+	Trim float64
+}
+
+// Score implements the Scorer interface
+func (r Robust) Score(before, after []float64) float64 {
+	z, _ := r.Test(before, after)
+	return math.Abs(z)
+}
+
+// Test implements the Test interface
+func (r Robust) Test(before, after []float64) (float64, float64) {
+	lb, sb := r.estimate(before)
+	la, sa := r.estimate(after)
+
+	se := math.Sqrt(sb*sb + sa*sa)
+	if se == 0 {
+		return 0, 0
+	}
+
+	z := (la - lb) / se
+	return z, math.Erf(math.Abs(z) / math.Sqrt2)
+}
+
+// estimate returns the location of xs and its standard error
+func (r Robust) estimate(xs []float64) (location, se float64) {
+	sorted := append([]float64(nil), xs...)
+	sort.Float64s(sorted)
+
+	n := float64(len(sorted))
+	if r.Trim <= 0 || r.Trim >= 0.5 {
+		// the median's variance is pi/2 times the mean's for normal data
+		sd := madScale * mad(sorted)
+		return quantile(sorted, 0.5), math.Sqrt(math.Pi/2) * sd / math.Sqrt(n)
+	}
+
+	g := int(r.Trim * n)
+	trimmed := sorted[g : len(sorted)-g]
+
+	// the winsorized items have the trimmed items replaced by the nearest kept one
+	var sum, sumsq float64
+	for _, x := range trimmed {
+		sum += x
+	}
+	location = sum / float64(len(trimmed))
+	if n < 2 {
+		return location, 0
+	}
+
+	for i, x := range sorted {
+		switch {
+		case i < g:
+			x = trimmed[0]
+		case i >= len(sorted)-g:
+			x = trimmed[len(trimmed)-1]
+		}
+		sumsq += (x - location) * (x - location)
+	}
+
+	winsorized := sumsq / (n - 1)
+	return location, math.Sqrt(winsorized) / ((1 - 2*float64(g)/n) * math.Sqrt(n))
+}
+
+// mad returns the median absolute deviation from the median of sorted
+func mad(sorted []float64) float64 {
+	median := quantile(sorted, 0.5)
+
+	dev := make([]float64, len(sorted))
+	for i, x := range sorted {
+		dev[i] = math.Abs(x - median)
+	}
+	sort.Float64s(dev)
+
+	return quantile(dev, 0.5)
+}
+
+// RobustChangePoint is a potential change point found by CheckRobust().  Its
+// Difference is the difference in locations, rather than in means.
+type RobustChangePoint struct {
+	ChangePoint
+
+	// BeforeLocation and AfterLocation are the median or trimmed mean of the
+	// items either side of the change point
+	BeforeLocation, AfterLocation float64
+
+	// BeforeScale and AfterScale are the standard deviations either side of
+	// the change point, estimated from the median absolute deviation
+	BeforeScale, AfterScale float64
+}
+
+// CheckRobust returns a potential change point in the location of window,
+// using Robust with the given trim to score the splits and to test the best
+// one.  The detector's Test, Cost, and Scorer are not used.  The locations and
+// scales are of the window after the detector's Transform, Outliers and
+// Missing policy.
+func (d *Detector) CheckRobust(window []float64, trim float64) *RobustChangePoint {
+	r := Robust{Trim: trim}
+
+	rd := *d
+	rd.Scorer = r
+	rd.Test = r
+
+	cp := rd.Check(window)
+	if cp == nil {
+		return nil
+	}
+
+	// the locations are of the series which was checked
+	p := d.prepare(window)
+	k := p.split(cp.Index)
+	before := append([]float64(nil), p.series[:k]...)
+	after := append([]float64(nil), p.series[k:]...)
+	lb, _ := r.estimate(before)
+	la, _ := r.estimate(after)
+	sort.Float64s(before)
+	sort.Float64s(after)
+
+	rcp := &RobustChangePoint{
+		ChangePoint:    *cp,
+		BeforeLocation: lb,
+		AfterLocation:  la,
+		BeforeScale:    madScale * mad(before),
+		AfterScale:     madScale * mad(after),
+	}
+	rcp.Difference = la - lb
+
+	return rcp
+}
@@ -0,0 +1,76 @@
+package change
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestRobust(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	w := steps(rnd, 100, 0, 2)
+
+	d := New(30, 0.99)
+	for _, trim := range []float64{0, 0.1} {
+		r := d.CheckRobust(w, trim)
+		if r == nil || r.Index < 95 || r.Index > 105 {
+			t.Errorf("CheckRobust(trim=%v)=%+v, wanted index 100", trim, r)
+			continue
+		}
+		if math.Abs(r.Difference-2) > 0.5 || r.Difference != r.AfterLocation-r.BeforeLocation {
+			t.Errorf("CheckRobust(trim=%v) Difference=%f, wanted about 2", trim, r.Difference)
+		}
+		if math.Abs(r.BeforeScale-1) > 0.3 || math.Abs(r.AfterScale-1) > 0.3 {
+			t.Errorf("CheckRobust(trim=%v) scales=%f, %f, wanted about 1", trim, r.BeforeScale, r.AfterScale)
+		}
+	}
+
+	// a single garbage item neither fakes a change nor moves a real one
+	flat := steps(rnd, 100, 0, 0)
+	flat[150] = 1e6
+
+	if r := d.CheckRobust(flat, 0); r != nil {
+		t.Errorf("CheckRobust() with an outlier=%+v, wanted nil", r)
+	}
+
+	w[60] = 1e6
+	if r := d.CheckRobust(w, 0); r == nil || r.Index < 95 || r.Index > 105 || math.Abs(r.Difference-2) > 0.5 {
+		t.Errorf("CheckRobust() with an outlier=%+v, wanted index 100", r)
+	}
+
+	// with normal data, the standard error is close to the mean's
+	_, se := Robust{Trim: 0.1}.estimate(steps(rnd, 5000, 0))
+	if want := 1 / math.Sqrt(5000); math.Abs(se-want) > 0.1*want {
+		t.Errorf("Robust{0.1} standard error=%f, wanted about %f", se, want)
+	}
+}
+
+func TestRobustTrim(t *testing.T) {
+	xs := []float64{1, 2, 3, 4, 100}
+	median, _ := Robust{}.estimate(xs)
+
+	// a trim outside [0, 0.5) is the median
+	for _, trim := range []float64{-0.1, 0.5, 0.7} {
+		if loc, _ := (Robust{Trim: trim}).estimate(xs); loc != median {
+			t.Errorf("Robust{%v}.estimate()=%v, wanted the median %v", trim, loc, median)
+		}
+	}
+}
+
+func TestCheckRobustMissing(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// the same window with a missing item, skipped by the detector
+	w := steps(rnd, 100, 0, 2)
+	wn := append(append(append([]float64(nil), w[:50]...), math.NaN()), w[50:]...)
+
+	d := New(30, 0.99)
+	want := d.CheckRobust(w, 0.1)
+
+	d.Missing = MissingSkip
+	r := d.CheckRobust(wn, 0.1)
+	if r == nil || want == nil || r.BeforeLocation != want.BeforeLocation || r.BeforeScale != want.BeforeScale {
+		t.Errorf("CheckRobust() with a missing item=%+v, wanted %+v", r, want)
+	}
+}
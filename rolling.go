@@ -0,0 +1,146 @@
+package change
+
+import "time"
+
+// Rolling checks a sliding window for a change point every time an item is
+// added.  Unlike Stream, which copies its window and checks it from scratch,
+// it maintains the cumulative sums of its items incrementally, so each check
+// only costs the scan over the split points.  Its buffers are rebased every
+// windowSize items, which is amortised to constant time per item.
+//
+// The detector's Homogeneity test, Transform, Outliers and Missing policy
+// can't use the cumulative sums; if any is set each window is checked from
+// scratch.
+type Rolling struct {
+	// Suppression holds back change points during a warm-up, and after each
+	// one reported
+	Suppression
+
+	detector   *Detector
+	windowSize int
+
+	// data holds up to 2*windowSize items, of which the window is the last
+	// windowSize, and cumsum[i] is the sum of data[:i+1] less shift
+	data   []float64
+	cumsum []float64
+	sum    kahan
+	shift  float64
+
+	// items is the number of items pushed
+	items int
+
+	// times[i%windowSize] is the time of the i'th item pushed, for the items
+	// in the window, once PushAt has been called
+	times []time.Time
+
+	suppressor suppressor
+}
+
+// DefaultWindowSize is the window size used by NewRolling if none is given
+const DefaultWindowSize = 4 * DefaultMinSampleSize
+
+// NewRolling returns a rolling detector for windows of windowSize items, using
+// d to check them.  If windowSize is not positive, DefaultWindowSize is used.
+func NewRolling(d *Detector, windowSize int) *Rolling {
+	if windowSize <= 0 {
+		windowSize = DefaultWindowSize
+	}
+
+	return &Rolling{
+		detector:   d,
+		windowSize: windowSize,
+		data:       make([]float64, 0, 2*windowSize),
+		cumsum:     make([]float64, 0, 2*windowSize),
+	}
+}
+
+// Push adds an item to the window and checks it, once it is full.  The
+// returned change point's Index is the number of items pushed before the
+// first item after the change.
+func (r *Rolling) Push(item float64) *ChangePoint {
+	if len(r.data) == cap(r.data) {
+		r.rebase()
+	}
+
+	if len(r.data) == 0 {
+		r.shift = item
+	}
+
+	r.sum.add(item - r.shift)
+	r.data = append(r.data, item)
+	r.cumsum = append(r.cumsum, r.sum.value())
+	r.items++
+
+	if len(r.data) < r.windowSize {
+		return nil
+	}
+
+	start := len(r.data) - r.windowSize
+	window := r.data[start:]
+
+	var cp *ChangePoint
+	if d := r.detector; d.Homogeneity != nil || d.Transform != nil || d.Outliers != (Outliers{}) || d.Missing != MissingReject {
+		cp = d.Check(window)
+	} else {
+		var base float64
+		if start > 0 {
+			base = r.cumsum[start-1]
+		}
+		cp = d.test(window, d.scanSums(window, r.cumsum[start:], base, nil))
+	}
+
+	if cp == nil {
+		return nil
+	}
+
+	cp.Index += r.items - r.windowSize
+
+	var latest time.Time
+	if r.times != nil {
+		cp.Time = r.times[cp.Index%r.windowSize]
+		latest = r.times[(r.items-1)%r.windowSize]
+	}
+
+	if !r.suppressor.allow(r.Suppression, r.items, latest) {
+		return nil
+	}
+
+	return cp
+}
+
+// PushAt is Push for an item with a timestamp.  The change point's Time is
+// the time of the first item after the change.
+func (r *Rolling) PushAt(t time.Time, item float64) *ChangePoint {
+	if r.times == nil {
+		r.times = make([]time.Time, r.windowSize)
+	}
+
+	r.times[r.items%r.windowSize] = t
+	r.suppressor.observe(t)
+	return r.Push(item)
+}
+
+// rebase moves the last windowSize-1 items to the start of the buffers, and
+// recomputes their sums from a new origin
+func (r *Rolling) rebase() {
+	keep := r.data[len(r.data)-r.windowSize+1:]
+	r.data = r.data[:copy(r.data, keep)]
+	r.cumsum = r.cumsum[:len(r.data)]
+
+	r.sum = kahan{}
+	if len(r.data) > 0 {
+		r.shift = origin(r.data)
+	}
+	for i, v := range r.data {
+		r.sum.add(v - r.shift)
+		r.cumsum[i] = r.sum.value()
+	}
+}
+
+// Window returns the current data window.  This should be treated as read-only
+func (r *Rolling) Window() []float64 {
+	if len(r.data) < r.windowSize {
+		return r.data
+	}
+	return r.data[len(r.data)-r.windowSize:]
+}
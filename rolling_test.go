@@ -0,0 +1,96 @@
+package change
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestRolling(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	series := steps(rnd, 150, 1e6, 1e6+2)
+
+	d := New(30, 0.999)
+	r := NewRolling(d, 100)
+
+	var found []int
+	for i, v := range series {
+		cp := r.Push(v)
+
+		// the incremental sums give the same result as checking from scratch
+		if i >= 99 {
+			want := d.Check(series[i-99 : i+1])
+			if want != nil {
+				want.Index += i - 99
+			}
+			if (cp == nil) != (want == nil) || (cp != nil && (cp.Index != want.Index || cp.Confidence != want.Confidence)) {
+				t.Fatalf("Rolling.Push(%d)=%+v, wanted %+v", i, cp, want)
+			}
+		}
+
+		if cp != nil {
+			found = append(found, cp.Index)
+		}
+	}
+
+	var ok bool
+	for _, idx := range found {
+		if idx >= 145 && idx <= 155 {
+			ok = true
+		}
+	}
+	if !ok {
+		t.Errorf("Rolling change points=%v, wanted about 150", found)
+	}
+}
+
+func TestRollingDefaultSize(t *testing.T) {
+	for _, size := range []int{0, -1} {
+		r := NewRolling(New(30, 0.999), size)
+		for i := 0; i < 3*DefaultWindowSize; i++ {
+			r.Push(float64(i % 2))
+		}
+		if got := len(r.Window()); got != DefaultWindowSize {
+			t.Errorf("NewRolling(%d) window=%d, wanted %d", size, got, DefaultWindowSize)
+		}
+	}
+}
+
+func BenchmarkRolling(b *testing.B) {
+	series := steps(rand.New(rand.NewSource(1)), 1000, 0)
+	r := NewRolling(New(30, 0.99), 500)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		r.Push(series[i%len(series)])
+	}
+}
+
+func TestRollingPushAt(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	w := steps(rnd, 200, 0, 5)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	r := NewRolling(New(30, 0.99), 100)
+
+	var found bool
+	for i, v := range w {
+		cp := r.PushAt(start.Add(time.Duration(i)*time.Minute), v)
+		if cp == nil {
+			continue
+		}
+
+		if want := start.Add(time.Duration(cp.Index) * time.Minute); !cp.Time.Equal(want) {
+			t.Errorf("PushAt() Time=%v for index %d, wanted %v", cp.Time, cp.Index, want)
+		}
+		if cp.Index == 200 {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Errorf("PushAt() found no change at index 200")
+	}
+}
@@ -0,0 +1,25 @@
+package change
+
+import "sync"
+
+// Scratch holds working space for checking windows, for use with CheckBuf.
+// The zero value is ready to use, and the buffers grow as needed.  A Scratch
+// must not be used by more than one goroutine at a time.
+type Scratch struct {
+	cumsum []float64
+}
+
+// floats returns a buffer of n floats, which is allocated if s is nil
+func (s *Scratch) floats(n int) []float64 {
+	if s == nil {
+		return make([]float64, n)
+	}
+	if cap(s.cumsum) < n {
+		s.cumsum = make([]float64, n)
+	}
+	return s.cumsum[:n]
+}
+
+// scratchPool holds the scratch buffers used when the caller doesn't provide
+// any, so that repeated checks reuse them instead of churning the heap
+var scratchPool = sync.Pool{New: func() interface{} { return new(Scratch) }}
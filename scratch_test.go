@@ -0,0 +1,62 @@
+package change
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestCheckBuf(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	flat := steps(rnd, 100, 0)
+	step := steps(rnd, 100, 0, 2)
+
+	d := New(30, 0.99)
+
+	var scratch Scratch
+
+	// the results are the same as Check
+	for _, w := range [][]float64{flat, step, step[:150]} {
+		want, got := d.Check(w), d.CheckBuf(w, &scratch)
+		if (want == nil) != (got == nil) || (want != nil && *want != *got) {
+			t.Errorf("CheckBuf()=%+v, wanted %+v", got, want)
+		}
+	}
+
+	if allocs := testing.AllocsPerRun(100, func() { d.CheckBuf(flat, &scratch) }); allocs != 0 {
+		t.Errorf("CheckBuf() allocations=%v, wanted 0", allocs)
+	}
+}
+
+func BenchmarkCheck(b *testing.B) {
+	w := steps(rand.New(rand.NewSource(1)), 500, 0)
+	d := New(30, 0.99)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		d.Check(w)
+	}
+}
+
+func BenchmarkCheckBuf(b *testing.B) {
+	w := steps(rand.New(rand.NewSource(1)), 500, 0)
+	d := New(30, 0.99)
+
+	var scratch Scratch
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		d.CheckBuf(w, &scratch)
+	}
+}
+
+func TestCheckPooled(t *testing.T) {
+	flat := steps(rand.New(rand.NewSource(1)), 100, 0)
+	d := New(30, 0.99)
+
+	// after the first call has filled the pool, Check doesn't allocate
+	d.Check(flat)
+	if allocs := testing.AllocsPerRun(100, func() { d.Check(flat) }); allocs > 0.1 {
+		t.Errorf("Check() allocations=%v, wanted 0", allocs)
+	}
+}
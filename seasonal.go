@@ -0,0 +1,91 @@
+package change
+
+import "math"
+
+// Deseasonalize removes a seasonal cycle of Period items from the window, such
+// as the daily cycle of hourly traffic, so that the cycle's peaks and troughs
+// aren't mistaken for changes.  The cycle is estimated by classical
+// decomposition, as in Decompose.  Level shifts are left in the window; only
+// the seasonal component is subtracted.
+type Deseasonalize struct {
+	// Period is the length of the cycle in items
+	Period int
+}
+
+// Apply implements the Transform interface
+func (s Deseasonalize) Apply(series []float64) []float64 {
+	_, seasonal, _ := Decompose(series, s.Period)
+
+	out := make([]float64, len(series))
+	for i, v := range series {
+		out[i] = v - seasonal[i]
+	}
+	return out
+}
+
+// Decompose splits series into trend, seasonal and residual components which
+// sum to it, for a cycle of period items.  The trend is the centred moving
+// average over one period, and is NaN within half a period of the ends of the
+// series.  The seasonal component is the mean difference from the trend at
+// each phase of the cycle, adjusted to sum to zero over a cycle.  The residual
+// is what remains, and is NaN where the trend is.  If the series is shorter
+// than two periods, or period is less than 2, the seasonal component is zero.
+func Decompose(series []float64, period int) (trend, seasonal, residual []float64) {
+	n := len(series)
+	trend = make([]float64, n)
+	seasonal = make([]float64, n)
+	residual = make([]float64, n)
+
+	for i := range trend {
+		trend[i] = math.NaN()
+	}
+
+	if period >= 2 && n >= 2*period {
+		movingAverage(series, period, trend)
+
+		sums := make([]float64, period)
+		counts := make([]float64, period)
+		for i, v := range series {
+			if !math.IsNaN(trend[i]) {
+				sums[i%period] += v - trend[i]
+				counts[i%period]++
+			}
+		}
+
+		var mean float64
+		for p := range sums {
+			sums[p] /= counts[p]
+			mean += sums[p]
+		}
+		mean /= float64(period)
+
+		for i := range seasonal {
+			seasonal[i] = sums[i%period] - mean
+		}
+	}
+
+	for i, v := range series {
+		residual[i] = v - trend[i] - seasonal[i]
+	}
+
+	return trend, seasonal, residual
+}
+
+// movingAverage sets trend[i] to the average over the period centred on i,
+// where it is defined.  An even period is centred by averaging over period+1
+// items with the ends given half weight.
+func movingAverage(series []float64, period int, trend []float64) {
+	half := period / 2
+	even := period%2 == 0
+
+	for i := half; i < len(series)-half; i++ {
+		var sum float64
+		for j := i - half; j <= i+half; j++ {
+			sum += series[j]
+		}
+		if even {
+			sum -= (series[i-half] + series[i+half]) / 2
+		}
+		trend[i] = sum / float64(period)
+	}
+}
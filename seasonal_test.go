@@ -0,0 +1,64 @@
+package change
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestDecompose(t *testing.T) {
+	// a trend plus an exact cycle of period 4
+	cycle := []float64{3, -1, -3, 1}
+	series := make([]float64, 40)
+	for i := range series {
+		series[i] = 0.5*float64(i) + cycle[i%4]
+	}
+
+	trend, seasonal, residual := Decompose(series, 4)
+
+	for i := range series {
+		if math.Abs(seasonal[i]-cycle[i%4]) > 1e-9 {
+			t.Errorf("Decompose() seasonal[%d]=%f, wanted %f", i, seasonal[i], cycle[i%4])
+		}
+
+		if i < 2 || i >= len(series)-2 {
+			if !math.IsNaN(trend[i]) || !math.IsNaN(residual[i]) {
+				t.Errorf("Decompose() trend[%d]=%f, wanted NaN at the ends", i, trend[i])
+			}
+			continue
+		}
+
+		if math.Abs(trend[i]-0.5*float64(i)) > 1e-9 || math.Abs(residual[i]) > 1e-9 {
+			t.Errorf("Decompose() trend[%d]=%f residual=%f, wanted %f and 0", i, trend[i], residual[i], 0.5*float64(i))
+		}
+	}
+
+	if _, seasonal, _ := Decompose(series[:7], 4); seasonal[0] != 0 {
+		t.Errorf("Decompose(less than two periods) seasonal=%v, wanted 0", seasonal)
+	}
+}
+
+func TestDeseasonalize(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// a strong daily cycle of hourly data, and a small level shift after 10 days
+	w := steps(rnd, 240, 0, 1.5)
+	for i := range w {
+		w[i] += 10 * math.Sin(2*math.Pi*float64(i)/24)
+	}
+
+	d := Detector{MinConfidence: 0.99, Transform: Deseasonalize{Period: 24}}
+	if r := d.Check(w); r == nil || r.Index < 230 || r.Index > 250 {
+		t.Errorf("Check(Deseasonalize)=%+v, wanted index 240", r)
+	}
+
+	// the cycle alone is not a change
+	flat := steps(rnd, 240, 0, 0)
+	for i := range flat {
+		flat[i] += 10 * math.Sin(2*math.Pi*float64(i)/24)
+	}
+
+	if r := d.Check(flat); r != nil {
+		t.Errorf("Check(Deseasonalize) of a cycle alone=%+v, wanted nil", r)
+	}
+}
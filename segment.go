@@ -0,0 +1,98 @@
+package change
+
+import "sort"
+
+// Segment finds multiple change points in series by binary segmentation: the
+// series is split at the change point found by Check, and each half is
+// checked again until no more significant change points are found.  The
+// change points are returned in order, with Index as the offset into series.
+func (d *Detector) Segment(series []float64) []*ChangePoint {
+	var cps []*ChangePoint
+	d.segment(series, 0, &cps)
+
+	cps = d.limit(cps)
+	sort.Slice(cps, func(i, j int) bool { return cps[i].Index < cps[j].Index })
+
+	return cps
+}
+
+// limit returns the MaxChangePoints change points with the highest scores, or
+// confidences for detectors without split scores, in no particular order
+func (d *Detector) limit(cps []*ChangePoint) []*ChangePoint {
+	if d.MaxChangePoints == 0 || len(cps) <= d.MaxChangePoints {
+		return cps
+	}
+
+	sort.Slice(cps, func(i, j int) bool {
+		if cps[i].Score != cps[j].Score {
+			return cps[i].Score > cps[j].Score
+		}
+		return cps[i].Confidence > cps[j].Confidence
+	})
+
+	return cps[:d.MaxChangePoints]
+}
+
+func (d *Detector) segment(series []float64, offset int, cps *[]*ChangePoint) {
+	cp := d.Check(series)
+	if cp == nil {
+		return
+	}
+
+	idx := cp.Index
+	cp.Index += offset
+	*cps = append(*cps, cp)
+
+	d.segment(series[:idx], offset, cps)
+	d.segment(series[idx:], offset+idx, cps)
+}
+
+// Regime is a stretch of a series between two change points.
+type Regime struct {
+	// Start is the offset into the series of the first item of the regime
+	Start int
+
+	// End is the offset into the series just after the last item of the regime
+	End int
+
+	// Stats is the statistics of the items in the regime
+	Stats Stats
+
+	// Change is the change point at the start of the regime, which gives
+	// its significance.  It is nil for the first regime.
+	Change *ChangePoint
+}
+
+// Segments segments series by binary segmentation, as Segment, and returns
+// the regimes between the change points.
+func (d *Detector) Segments(series []float64) []Regime {
+	return Regimes(series, d.Segment(series))
+}
+
+// Regimes returns the regimes of series between the change points cps, such
+// as those found by Segment, WildSegment or EDivisive, which must be in order.
+func Regimes(series []float64, cps []*ChangePoint) []Regime {
+	var regimes []Regime
+
+	start := 0
+	var change *ChangePoint
+	for i := 0; i <= len(cps); i++ {
+		end := len(series)
+		var cp *ChangePoint
+		if i < len(cps) {
+			cp = cps[i]
+			end = cp.Index
+		}
+
+		regimes = append(regimes, Regime{
+			Start:  start,
+			End:    end,
+			Stats:  sampleStats(series[start:end]),
+			Change: change,
+		})
+
+		start, change = end, cp
+	}
+
+	return regimes
+}
@@ -0,0 +1,94 @@
+package change
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestSegment(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	var tests = []struct {
+		series []float64
+		want   []int
+	}{
+		{steps(rnd, 100, 0), nil},
+		{steps(rnd, 100, 0, 5), []int{100}},
+		{steps(rnd, 100, 0, 5, 0, -5), []int{100, 200, 300}},
+	}
+
+	d := New(10, 0.9999)
+
+	for _, tt := range tests {
+		cps := d.Segment(tt.series)
+
+		var got []int
+		for _, cp := range cps {
+			got = append(got, cp.Index)
+		}
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Segment()=%v, wanted %v", got, tt.want)
+		}
+	}
+}
+
+func TestSegments(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	series := steps(rnd, 100, 0, 5, 0)
+
+	regimes := New(10, 0.9999).Segments(series)
+	if len(regimes) != 3 {
+		t.Fatalf("Segments()=%d regimes, wanted 3", len(regimes))
+	}
+
+	for i, r := range regimes {
+		if r.Start != 100*i || r.End != 100*(i+1) || r.Stats.Len() != 100 {
+			t.Errorf("Segments()[%d]=[%d,%d) with %d items, wanted [%d,%d)", i, r.Start, r.End, r.Stats.Len(), 100*i, 100*(i+1))
+		}
+
+		if want := []float64{0, 5, 0}[i]; math.Abs(r.Stats.Mean()-want) > 0.5 {
+			t.Errorf("Segments()[%d] mean=%f, wanted %f", i, r.Stats.Mean(), want)
+		}
+
+		if (r.Change == nil) != (i == 0) || (r.Change != nil && r.Change.Index != r.Start) {
+			t.Errorf("Segments()[%d].Change=%+v, wanted change point at %d", i, r.Change, r.Start)
+		}
+	}
+
+	if regimes := Regimes(series, nil); len(regimes) != 1 || regimes[0].End != len(series) {
+		t.Errorf("Regimes(nil)=%+v, wanted a single regime", regimes)
+	}
+}
+
+func TestMaxChangePoints(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// the change at 200 is the largest, then the one at 100
+	series := steps(rnd, 100, 0, 3, -5, -4)
+
+	var tests = []struct {
+		max  int
+		want []int
+	}{
+		{0, []int{100, 200, 300}},
+		{2, []int{100, 200}},
+		{1, []int{200}},
+	}
+
+	for _, tt := range tests {
+		d := Detector{MinSampleSize: 10, MinConfidence: 0.9999, MaxChangePoints: tt.max}
+
+		var got []int
+		for _, cp := range d.Segment(series) {
+			got = append(got, cp.Index)
+		}
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Segment(MaxChangePoints=%d)=%v, wanted %v", tt.max, got, tt.want)
+		}
+	}
+}
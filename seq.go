@@ -0,0 +1,34 @@
+//go:build go1.23
+
+package change
+
+import (
+	"iter"
+	"slices"
+	"time"
+)
+
+// DetectChangeSeq is d.Check for the items of seq, such as the values read
+// from a database cursor.  The whole sequence is checked as one window, so
+// it must be finite.
+func DetectChangeSeq(d *Detector, seq iter.Seq[float64]) *ChangePoint {
+	return d.Check(slices.Collect(seq))
+}
+
+// Changes pushes the timestamped items of seq to r with PushAt, and yields
+// each change point found along with its Time.  The sequence may be
+// unbounded; it is consumed as the changes are ranged over.
+func (r *Rolling) Changes(seq iter.Seq2[time.Time, float64]) iter.Seq2[time.Time, *ChangePoint] {
+	return func(yield func(time.Time, *ChangePoint) bool) {
+		for t, v := range seq {
+			cp := r.PushAt(t, v)
+			if cp == nil {
+				continue
+			}
+
+			if !yield(cp.Time, cp) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,54 @@
+//go:build go1.23
+
+package change
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDetectChangeSeq(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	w := steps(rnd, 100, 0, 5)
+	d := New(30, 0.99)
+
+	want := d.Check(w)
+	if got := DetectChangeSeq(d, slices.Values(w)); got == nil || want == nil || *got != *want {
+		t.Errorf("DetectChangeSeq()=%+v, wanted %+v", got, want)
+	}
+}
+
+func TestRollingChanges(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	w := steps(rnd, 200, 0, 5)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	seq := func(yield func(time.Time, float64) bool) {
+		for i, v := range w {
+			if !yield(start.Add(time.Duration(i)*time.Second), v) {
+				return
+			}
+		}
+	}
+
+	r := NewRolling(New(30, 0.99), 100)
+
+	var found bool
+	for at, cp := range r.Changes(seq) {
+		if want := start.Add(time.Duration(cp.Index) * time.Second); !at.Equal(want) {
+			t.Errorf("Changes() time=%v for index %d, wanted %v", at, cp.Index, want)
+		}
+		if cp.Index == 200 {
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		t.Errorf("Changes() found no change at index 200")
+	}
+}
@@ -0,0 +1,31 @@
+package change
+
+import "context"
+
+// Sink is notified of change events, such as to raise an alert.  A Manager
+// notifies its Sink of each event it finds.
+type Sink interface {
+	// Notify handles ev.  It should give up when ctx is done.
+	Notify(ctx context.Context, ev Event) error
+}
+
+// SinkFunc is a function which is a Sink
+type SinkFunc func(ctx context.Context, ev Event) error
+
+// Notify calls f(ctx, ev)
+func (f SinkFunc) Notify(ctx context.Context, ev Event) error { return f(ctx, ev) }
+
+// Sinks is a Sink which notifies each of its sinks in turn, and returns the
+// first error
+type Sinks []Sink
+
+// Notify notifies each sink, even if an earlier one returns an error
+func (s Sinks) Notify(ctx context.Context, ev Event) error {
+	var first error
+	for _, sink := range s {
+		if err := sink.Notify(ctx, ev); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
@@ -0,0 +1,77 @@
+package change
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestManagerSink(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	m := NewManager(func(string) *Rolling {
+		r := NewRolling(New(30, 0.99999), 100)
+		r.Cooldown = 1000
+		return r
+	})
+
+	var notified []Event
+	errSink := errors.New("sink failed")
+	m.Sink = Sinks{
+		SinkFunc(func(ctx context.Context, ev Event) error {
+			notified = append(notified, ev)
+			return errSink
+		}),
+		SinkFunc(func(ctx context.Context, ev Event) error {
+			notified = append(notified, ev)
+			return nil
+		}),
+	}
+
+	var failed []error
+	m.SinkError = func(ev Event, err error) { failed = append(failed, err) }
+
+	var events []*Event
+	for _, v := range steps(rnd, 200, 0, 5) {
+		if ev := m.Push("a", v); ev != nil {
+			events = append(events, ev)
+		}
+	}
+
+	if len(events) != 1 || len(notified) != 2 || notified[0] != *events[0] || notified[1] != *events[0] {
+		t.Fatalf("Push() events=%+v, notified=%+v, wanted both sinks notified of each event", events, notified)
+	}
+	if len(failed) != 1 || failed[0] != errSink {
+		t.Errorf("SinkError called with %v, wanted the first sink's error", failed)
+	}
+}
+
+func TestManagerSinkTimeout(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	m := NewManager(func(string) *Rolling {
+		r := NewRolling(New(30, 0.99999), 100)
+		r.Cooldown = 1000
+		return r
+	})
+	m.SinkTimeout = 10 * time.Millisecond
+
+	// the sink hangs until its context is done
+	m.Sink = SinkFunc(func(ctx context.Context, ev Event) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	var failed []error
+	m.SinkError = func(ev Event, err error) { failed = append(failed, err) }
+
+	for _, v := range steps(rnd, 200, 0, 5) {
+		m.Push("a", v)
+	}
+
+	if len(failed) != 1 || !errors.Is(failed[0], context.DeadlineExceeded) {
+		t.Errorf("SinkError called with %v, wanted %v", failed, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,241 @@
+package change
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// snapshotVersion is the version of the snapshot format
+const snapshotVersion = 1
+
+// ErrSnapshot is returned when a snapshot can't be restored
+var ErrSnapshot = errors.New("change: invalid snapshot")
+
+// streamState is the snapshot of a streaming detector's state.  Its
+// configuration, including the Detector, is not included, and must be the
+// same when it is restored.
+type streamState struct {
+	Version int
+
+	// WindowSize or Span is the size of the window, which must match
+	WindowSize int
+	Span       time.Duration
+
+	// Items is the number of items pushed
+	Items int
+
+	// Data and Times are the items in the window and their times, if any
+	Data  []float64
+	Times []time.Time
+
+	// Pending and PendingTimes are the items of a Stream which have not yet
+	// been added to its window
+	Pending      []float64
+	PendingTimes []time.Time
+
+	// the state of the warm-up and cooldown
+	First    time.Time
+	Started  bool
+	Reported bool
+	AtItems  int
+	At       time.Time
+}
+
+func (st *streamState) saveSuppressor(s *suppressor) {
+	st.First, st.Started = s.first, s.started
+	st.Reported, st.AtItems, st.At = s.reported, s.items, s.at
+}
+
+func (st *streamState) restoreSuppressor(s *suppressor) {
+	s.first, s.started = st.First, st.Started
+	s.reported, s.items, s.at = st.Reported, st.AtItems, st.At
+}
+
+func (st *streamState) marshal() ([]byte, error) {
+	st.Version = snapshotVersion
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(st); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// unmarshal decodes a snapshot of a detector with the given window size or
+// span, holding at most maxData items in its window
+func (st *streamState) unmarshal(data []byte, windowSize int, span time.Duration, maxData int) error {
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(st); err != nil {
+		return fmt.Errorf("%w: %v", ErrSnapshot, err)
+	}
+
+	switch {
+	case st.Version != snapshotVersion:
+		return fmt.Errorf("%w: version %d", ErrSnapshot, st.Version)
+	case st.WindowSize != windowSize || st.Span != span:
+		return fmt.Errorf("%w: window of %d items or %v, wanted %d or %v", ErrSnapshot, st.WindowSize, st.Span, windowSize, span)
+	case len(st.Data) > maxData || len(st.Data)+len(st.Pending) > st.Items:
+		return fmt.Errorf("%w: %d items in the window", ErrSnapshot, len(st.Data))
+	case st.Times != nil && len(st.Times) != len(st.Data):
+		return fmt.Errorf("%w: %d times for %d items", ErrSnapshot, len(st.Times), len(st.Data))
+	case st.PendingTimes != nil && len(st.PendingTimes) != len(st.Pending):
+		return fmt.Errorf("%w: %d times for %d pending items", ErrSnapshot, len(st.PendingTimes), len(st.Pending))
+	}
+
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, to checkpoint the
+// detector's window and counters so that a long-running monitor can survive
+// a restart.  Its configuration is not included.
+func (r *Rolling) MarshalBinary() ([]byte, error) {
+	st := streamState{
+		WindowSize: r.windowSize,
+		Items:      r.items,
+		Data:       r.Window(),
+	}
+
+	if r.times != nil {
+		st.Times = make([]time.Time, len(st.Data))
+		for i := range st.Times {
+			st.Times[i] = r.times[(r.items-len(st.Data)+i)%r.windowSize]
+		}
+	}
+
+	st.saveSuppressor(&r.suppressor)
+	return st.marshal()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, to restore a
+// checkpoint made by MarshalBinary.  The detector must have been created
+// with the same window size, and should have the same configuration.
+func (r *Rolling) UnmarshalBinary(data []byte) error {
+	var st streamState
+	if err := st.unmarshal(data, r.windowSize, 0, r.windowSize); err != nil {
+		return err
+	}
+
+	r.items = st.Items
+	r.data = append(r.data[:0], st.Data...)
+	r.cumsum = r.cumsum[:len(r.data)]
+
+	r.sum = kahan{}
+	r.shift = origin(r.data)
+	for i, v := range r.data {
+		r.sum.add(v - r.shift)
+		r.cumsum[i] = r.sum.value()
+	}
+
+	r.times = nil
+	if st.Times != nil {
+		r.times = make([]time.Time, r.windowSize)
+		for i, t := range st.Times {
+			r.times[(r.items-len(st.Times)+i)%r.windowSize] = t
+		}
+	}
+
+	r.suppressor = suppressor{}
+	st.restoreSuppressor(&r.suppressor)
+
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, to checkpoint the
+// stream's window, including the items not yet added to it.  Its
+// configuration is not included.
+func (s *Stream) MarshalBinary() ([]byte, error) {
+	st := streamState{
+		WindowSize: s.windowSize,
+		Items:      s.items,
+		Pending:    s.buffer[:s.bufidx],
+	}
+
+	// the window is zero until it has been filled
+	filled := s.items - s.bufidx
+	if filled > s.windowSize {
+		filled = s.windowSize
+	}
+	st.Data = s.data[s.windowSize-filled:]
+
+	if s.times != nil {
+		st.Times = s.times[s.windowSize-len(st.Data):]
+		st.PendingTimes = s.tbuffer[:s.bufidx]
+	}
+
+	st.saveSuppressor(&s.suppressor)
+	return st.marshal()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, to restore a
+// checkpoint made by MarshalBinary.  The stream must have been created with
+// the same window and block sizes, and should have the same configuration.
+func (s *Stream) UnmarshalBinary(data []byte) error {
+	var st streamState
+	if err := st.unmarshal(data, s.windowSize, 0, s.windowSize); err != nil {
+		return err
+	}
+	if len(st.Pending) >= s.blockSize {
+		return fmt.Errorf("%w: %d pending items for blocks of %d", ErrSnapshot, len(st.Pending), s.blockSize)
+	}
+
+	s.items = st.Items
+
+	for i := range s.data {
+		s.data[i] = 0
+	}
+	copy(s.data[s.windowSize-len(st.Data):], st.Data)
+	s.bufidx = copy(s.buffer, st.Pending)
+
+	s.times, s.tbuffer = nil, nil
+	if st.Times != nil || st.PendingTimes != nil {
+		s.times = make([]time.Time, s.windowSize)
+		s.tbuffer = make([]time.Time, s.blockSize)
+		copy(s.times[s.windowSize-len(st.Times):], st.Times)
+		copy(s.tbuffer, st.PendingTimes)
+	}
+
+	s.suppressor = suppressor{}
+	st.restoreSuppressor(&s.suppressor)
+
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, to checkpoint the
+// detector's window and counters.  Its configuration is not included.
+func (w *Timed) MarshalBinary() ([]byte, error) {
+	st := streamState{
+		Span:  w.span,
+		Items: w.items,
+		Data:  w.Window(),
+		Times: w.Times(),
+	}
+
+	st.saveSuppressor(&w.suppressor)
+	return st.marshal()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, to restore a
+// checkpoint made by MarshalBinary.  The detector must have been created
+// with the same span, and should have the same configuration.
+func (w *Timed) UnmarshalBinary(data []byte) error {
+	var st streamState
+	if err := st.unmarshal(data, 0, w.span, math.MaxInt); err != nil {
+		return err
+	}
+	if len(st.Times) != len(st.Data) {
+		return fmt.Errorf("%w: %d times for %d items", ErrSnapshot, len(st.Times), len(st.Data))
+	}
+
+	w.items = st.Items
+	w.data = append(w.data[:0], st.Data...)
+	w.times = append(w.times[:0], st.Times...)
+	w.start = 0
+
+	w.suppressor = suppressor{}
+	st.restoreSuppressor(&w.suppressor)
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package change
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSnapshot(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	w := steps(rnd, 150, 0, 5)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(i int) time.Time { return start.Add(time.Duration(i) * time.Second) }
+
+	// each detector is checkpointed part way through, restored into a new
+	// one, and must then report the same change points as the original
+	type streamer interface {
+		MarshalBinary() ([]byte, error)
+		UnmarshalBinary([]byte) error
+	}
+
+	var tests = []struct {
+		name string
+		new  func() streamer
+		push func(s streamer, i int) *ChangePoint
+	}{
+		{
+			"Rolling",
+			func() streamer { r := NewRolling(New(30, 0.9999), 100); r.Cooldown = 20; return r },
+			func(s streamer, i int) *ChangePoint { return s.(*Rolling).PushAt(at(i), w[i]) },
+		},
+		{
+			"Stream",
+			func() streamer { s := NewStream(100, 30, 7, 0.9999); s.Cooldown = 20; return s },
+			func(s streamer, i int) *ChangePoint { return s.(*Stream).PushAt(at(i), w[i]) },
+		},
+		{
+			"Timed",
+			func() streamer { return NewTimed(New(30, 0.9999), 100*time.Second) },
+			func(s streamer, i int) *ChangePoint { return s.(*Timed).Push(at(i), w[i]) },
+		},
+	}
+
+	for _, tt := range tests {
+		for _, split := range []int{10, 140, 161} {
+			orig, restored := tt.new(), tt.new()
+
+			for i := 0; i < split; i++ {
+				tt.push(orig, i)
+			}
+
+			snap, err := orig.MarshalBinary()
+			if err != nil {
+				t.Fatalf("%s.MarshalBinary() err=%v", tt.name, err)
+			}
+			if err := restored.UnmarshalBinary(snap); err != nil {
+				t.Fatalf("%s.UnmarshalBinary() err=%v", tt.name, err)
+			}
+
+			for i := split; i < len(w); i++ {
+				want, got := tt.push(orig, i), tt.push(restored, i)
+
+				// the restored sums are rebased, which can change the last bits of the score
+				if got != nil && want != nil && math.Abs(got.Score-want.Score) <= 1e-12*want.Score {
+					got.Score = want.Score
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("%s restored at %d pushed item %d=%+v, wanted %+v", tt.name, split, i, got, want)
+					break
+				}
+			}
+		}
+	}
+
+	snap, _ := NewRolling(New(30, 0.99), 100).MarshalBinary()
+	if err := NewRolling(New(30, 0.99), 50).UnmarshalBinary(snap); !errors.Is(err, ErrSnapshot) {
+		t.Errorf("UnmarshalBinary(other window size) err=%v, wanted %v", err, ErrSnapshot)
+	}
+	if err := NewRolling(New(30, 0.99), 100).UnmarshalBinary([]byte("junk")); !errors.Is(err, ErrSnapshot) {
+		t.Errorf("UnmarshalBinary(junk) err=%v, wanted %v", err, ErrSnapshot)
+	}
+}
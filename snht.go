@@ -0,0 +1,148 @@
+package change
+
+import (
+	"container/list"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+// DefaultSimulations is the number of simulated series used to estimate critical values if none is given
+const DefaultSimulations = 2000
+
+// SNHT is the Standard Normal Homogeneity Test of Alexandersson (1986) for a
+// single shift in the mean.  The series is standardised, and for each split
+// the statistic is T(k) = k*z1^2 + (n-k)*z2^2, where z1 and z2 are the mean
+// standardised values on either side.
+//
+// The reported statistic is T0, the largest T(k).  Its distribution has no
+// closed form, so its critical values depend on the length of the series and
+// are usually read from tables.  Instead, the confidence is estimated by
+// simulating series of normally distributed noise of the same length; the
+// simulations for each length are done once and cached.
+type SNHT struct {
+	// Simulations is the number of simulated series used to estimate the
+	// confidence, which is at most Simulations/(Simulations+1).  If zero,
+	// DefaultSimulations is used.
+	Simulations int
+}
+
+// Locate implements the Homogeneity interface
+func (s SNHT) Locate(window []float64) (int, float64, float64) {
+	idx, t0 := snht(window)
+	if idx == 0 {
+		return 0, 0, 0
+	}
+
+	sims := s.Simulations
+	if sims == 0 {
+		sims = DefaultSimulations
+	}
+
+	null := simulatedNull("snht", len(window), sims, func(w []float64) float64 {
+		_, t := snht(w)
+		return t
+	})
+
+	return idx, t0, nullConfidence(null, t0)
+}
+
+// snht returns the split of window with the largest SNHT statistic, and the statistic
+func snht(window []float64) (int, float64) {
+	st := sampleStats(window)
+	sd := st.Stddev()
+	n := len(window)
+	if sd == 0 || n < 2 {
+		return 0, 0
+	}
+
+	var total float64
+	for _, v := range window {
+		total += (v - st.mean) / sd
+	}
+
+	var t0, sum float64
+	var idx int
+	for k := 1; k < n; k++ {
+		sum += (window[k-1] - st.mean) / sd
+		z1 := sum / float64(k)
+		z2 := (total - sum) / float64(n-k)
+		if t := float64(k)*z1*z1 + float64(n-k)*z2*z2; t > t0 {
+			t0, idx = t, k
+		}
+	}
+
+	return idx, t0
+}
+
+// nullCacheSize is the most null distributions kept, so that windows of
+// many different lengths, such as those of a Timed detector, don't grow the
+// cache without limit
+const nullCacheSize = 32
+
+var (
+	nullMu    sync.Mutex
+	nullCache = make(map[nullKey]*list.Element)
+
+	// nullLRU holds the cached *nullEntry values, most recently used first
+	nullLRU = list.New()
+)
+
+type nullKey struct {
+	name string
+	n    int
+	sims int
+}
+
+type nullEntry struct {
+	key  nullKey
+	null []float64
+}
+
+// simulatedNull returns the sorted values of stat over sims series of n
+// standard normal items.  The series are generated from a fixed seed, and the
+// results of the most recent nullCacheSize calls cached by name, n, and sims.
+func simulatedNull(name string, n, sims int, stat func([]float64) float64) []float64 {
+	key := nullKey{name, n, sims}
+
+	nullMu.Lock()
+	if e, ok := nullCache[key]; ok {
+		nullLRU.MoveToFront(e)
+		nullMu.Unlock()
+		return e.Value.(*nullEntry).null
+	}
+	nullMu.Unlock()
+
+	rnd := rand.New(rand.NewSource(1))
+	w := make([]float64, n)
+	null := make([]float64, sims)
+	for i := range null {
+		for j := range w {
+			w[j] = rnd.NormFloat64()
+		}
+		null[i] = stat(w)
+	}
+	sort.Float64s(null)
+
+	nullMu.Lock()
+	defer nullMu.Unlock()
+
+	// another goroutine may have simulated it meanwhile
+	if _, ok := nullCache[key]; !ok {
+		nullCache[key] = nullLRU.PushFront(&nullEntry{key: key, null: null})
+		if nullLRU.Len() > nullCacheSize {
+			oldest := nullLRU.Remove(nullLRU.Back()).(*nullEntry)
+			delete(nullCache, oldest.key)
+		}
+	}
+
+	return null
+}
+
+// nullConfidence returns the fraction of the sorted null distribution below
+// observed, with the usual correction so that it is never exactly 1
+func nullConfidence(null []float64, observed float64) float64 {
+	below := sort.SearchFloat64s(null, observed)
+	return math.Max(0, float64(below)/float64(len(null)+1))
+}
@@ -0,0 +1,61 @@
+package change
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestSNHT(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	w := steps(rnd, 50, 0, 1.5)
+
+	idx, t0, conf := SNHT{}.Locate(w)
+	if idx < 45 || idx > 55 || t0 <= 0 || conf < 0.999 {
+		t.Errorf("SNHT()=(%d, %f, %f), wanted change near 50", idx, t0, conf)
+	}
+
+	d := Detector{Homogeneity: SNHT{}, MinConfidence: 0.99}
+	if r := d.Check(steps(rnd, 100, 0)); r != nil {
+		t.Errorf("Check with SNHT found change %+v in stable window", r)
+	}
+}
+
+func TestSNHTCritical(t *testing.T) {
+	// the 95% critical value for n=100 from the tables of Khaliq and Ouarda (2007) is about 9.15
+	null := simulatedNull("snht", 100, DefaultSimulations, func(w []float64) float64 {
+		_, t := snht(w)
+		return t
+	})
+
+	if c := quantile(null, 0.95); c < 8.7 || c > 9.6 {
+		t.Errorf("SNHT 95%% critical value for n=100=%f, wanted about 9.15", c)
+	}
+}
+
+func TestNullCache(t *testing.T) {
+	stat := func(w []float64) float64 { return w[0] }
+
+	first := simulatedNull("test", 2, 10, stat)
+	for n := 3; n < 3+2*nullCacheSize; n++ {
+		simulatedNull("test", n, 10, stat)
+	}
+
+	nullMu.Lock()
+	size, entries := len(nullCache), nullLRU.Len()
+	_, kept := nullCache[nullKey{"test", 2, 10}]
+	nullMu.Unlock()
+
+	if size > nullCacheSize || entries != size {
+		t.Errorf("null cache holds %d distributions (%d in the LRU list), wanted at most %d", size, entries, nullCacheSize)
+	}
+	if kept {
+		t.Errorf("null cache kept the least recently used distribution")
+	}
+
+	// an evicted distribution is simulated again from the same seed
+	if again := simulatedNull("test", 2, 10, stat); !reflect.DeepEqual(again, first) {
+		t.Errorf("simulatedNull() after eviction=%v, wanted %v", again, first)
+	}
+}
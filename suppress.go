@@ -0,0 +1,65 @@
+package change
+
+import "time"
+
+// Suppression configures when a streaming detector holds back the change
+// points it finds.  The zero value reports every one.
+type Suppression struct {
+	// Cooldown is the number of items after a change point is reported
+	// during which no more are reported, so that one change isn't reported
+	// again and again as the window slides across it.
+	Cooldown int
+
+	// CooldownTime is the time after a change point is reported during which
+	// no more are reported.  It only applies to items with times, such as
+	// those given to PushAt.
+	CooldownTime time.Duration
+
+	// WarmUp is the number of items which must be pushed before any change
+	// point is reported, so that startup transients, such as cold caches,
+	// don't trigger alerts.  The items are still added to the window.
+	WarmUp int
+
+	// WarmUpTime is the time after the first item during which no change
+	// point is reported.  Like CooldownTime, it only applies to items with
+	// times.
+	WarmUpTime time.Duration
+}
+
+// suppressor applies a Suppression.  The times are those of the items given
+// to PushAt, or zero for Push, and the times in the Suppression are ignored
+// without them.
+type suppressor struct {
+	// first is the time of the first item
+	first   time.Time
+	started bool
+
+	// items and at are the number of items pushed and the time of the
+	// latest item when the last change point was reported
+	reported bool
+	items    int
+	at       time.Time
+}
+
+// observe records the time of an item
+func (s *suppressor) observe(t time.Time) {
+	if !s.started {
+		s.first, s.started = t, true
+	}
+}
+
+// allow reports whether a change point found after items have been pushed,
+// the latest at time t, may be reported.  If so, the cooldown restarts.
+func (s *suppressor) allow(cfg Suppression, items int, t time.Time) bool {
+	if items <= cfg.WarmUp || (s.started && !t.IsZero() && t.Sub(s.first) < cfg.WarmUpTime) {
+		return false
+	}
+
+	if s.reported && (items-s.items <= cfg.Cooldown || (!t.IsZero() && !s.at.IsZero() && t.Sub(s.at) < cfg.CooldownTime)) {
+		return false
+	}
+
+	s.reported = true
+	s.items, s.at = items, t
+	return true
+}
@@ -0,0 +1,133 @@
+package change
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestCooldown(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	w := steps(rnd, 200, 0, 5)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	count := func(r *Rolling) (n int) {
+		for i, v := range w {
+			if r.PushAt(start.Add(time.Duration(i)*time.Second), v) != nil {
+				n++
+			}
+		}
+		return n
+	}
+
+	all := count(NewRolling(New(30, 0.99), 100))
+	if all < 10 {
+		t.Fatalf("Rolling reported %d change points, wanted many as the window slides", all)
+	}
+
+	// the window only holds the change for 140 items
+	r := NewRolling(New(30, 0.99), 100)
+	r.Cooldown = 200
+	if n := count(r); n != 1 {
+		t.Errorf("Rolling with Cooldown=200 reported %d change points, wanted 1", n)
+	}
+
+	r = NewRolling(New(30, 0.99), 100)
+	r.CooldownTime = 50 * time.Second
+	if n := count(r); n < 2 || n >= all {
+		t.Errorf("Rolling with CooldownTime=50s reported %d change points, wanted fewer than %d", n, all)
+	}
+
+	s := NewStream(100, 30, 10, 0.99)
+	s.Cooldown = 200
+	var n int
+	for _, v := range w {
+		if s.Push(v) != nil {
+			n++
+		}
+	}
+	if n != 1 {
+		t.Errorf("Stream with Cooldown=200 reported %d change points, wanted 1", n)
+	}
+
+	tw := NewTimed(New(30, 0.99), 100*time.Second)
+	tw.CooldownTime = time.Hour
+	n = 0
+	for i, v := range w {
+		if tw.Push(start.Add(time.Duration(i)*time.Second), v) != nil {
+			n++
+		}
+	}
+	if n != 1 {
+		t.Errorf("Timed with CooldownTime=1h reported %d change points, wanted 1", n)
+	}
+}
+
+func TestWarmUp(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// a startup transient, and a real change later on
+	w := steps(rnd, 200, 10, 0, 0, 5)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	first := func(r *Rolling) int {
+		for i, v := range w {
+			if cp := r.PushAt(start.Add(time.Duration(i)*time.Second), v); cp != nil {
+				return cp.Index
+			}
+		}
+		return -1
+	}
+
+	if idx := first(NewRolling(New(30, 0.99999), 100)); idx < 170 || idx > 230 {
+		t.Fatalf("Rolling first change point=%d, wanted the transient at 200", idx)
+	}
+
+	r := NewRolling(New(30, 0.99999), 100)
+	r.WarmUp = 400
+	if idx := first(r); idx < 590 || idx > 610 {
+		t.Errorf("Rolling with WarmUp=400 first change point=%d, wanted 600", idx)
+	}
+
+	r = NewRolling(New(30, 0.99999), 100)
+	r.WarmUpTime = 400 * time.Second
+	if idx := first(r); idx < 590 || idx > 610 {
+		t.Errorf("Rolling with WarmUpTime=400s first change point=%d, wanted 600", idx)
+	}
+
+	tw := NewTimed(New(30, 0.99999), 100*time.Second)
+	tw.WarmUp = 400
+	for i, v := range w {
+		if cp := tw.Push(start.Add(time.Duration(i)*time.Second), v); cp != nil {
+			if cp.Index < 590 || cp.Index > 610 {
+				t.Errorf("Timed with WarmUp=400 first change point=%d, wanted 600", cp.Index)
+			}
+			break
+		}
+	}
+}
+
+func TestSuppressionUntimed(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	w := steps(rnd, 200, 0, 5)
+
+	count := func(r *Rolling) (n int) {
+		for _, v := range w {
+			if r.Push(v) != nil {
+				n++
+			}
+		}
+		return n
+	}
+
+	all := count(NewRolling(New(30, 0.99), 100))
+
+	// items pushed without times aren't held back by the times
+	r := NewRolling(New(30, 0.99), 100)
+	r.WarmUpTime = time.Hour
+	r.CooldownTime = time.Hour
+	if n := count(r); n != all {
+		t.Errorf("Rolling.Push with WarmUpTime and CooldownTime reported %d change points, wanted %d", n, all)
+	}
+}
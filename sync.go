@@ -0,0 +1,138 @@
+package change
+
+import (
+	"sync"
+	"time"
+)
+
+// SyncRolling is a Rolling detector which is safe for concurrent use, so that
+// many producer goroutines can push to the same stream without locking it
+// themselves.  The order of items pushed concurrently is the order in which
+// they acquire the lock.
+type SyncRolling struct {
+	mu sync.Mutex
+	r  *Rolling
+}
+
+// NewSyncRolling returns a concurrency-safe rolling detector for windows of
+// windowSize items, using d to check them.  The Rolling's options, such as
+// its Suppression, may be set through Do.
+func NewSyncRolling(d *Detector, windowSize int) *SyncRolling {
+	return &SyncRolling{r: NewRolling(d, windowSize)}
+}
+
+// Push is Rolling.Push
+func (s *SyncRolling) Push(item float64) *ChangePoint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.r.Push(item)
+}
+
+// PushAt is Rolling.PushAt
+func (s *SyncRolling) PushAt(t time.Time, item float64) *ChangePoint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.r.PushAt(t, item)
+}
+
+// Window returns a copy of the current data window
+func (s *SyncRolling) Window() []float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]float64(nil), s.r.Window()...)
+}
+
+// Do calls f with the underlying detector while holding the lock, to
+// configure or inspect it
+func (s *SyncRolling) Do(f func(r *Rolling)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f(s.r)
+}
+
+// SyncStream is a Stream which is safe for concurrent use, like SyncRolling
+type SyncStream struct {
+	mu sync.Mutex
+	s  *Stream
+}
+
+// NewSyncStream returns a concurrency-safe stream detector, with the
+// arguments of NewStream
+func NewSyncStream(windowSize int, minSample int, blockSize int, confidence float64) *SyncStream {
+	return &SyncStream{s: NewStream(windowSize, minSample, blockSize, confidence)}
+}
+
+// Push is Stream.Push
+func (s *SyncStream) Push(item float64) *ChangePoint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.s.Push(item)
+}
+
+// PushAt is Stream.PushAt
+func (s *SyncStream) PushAt(t time.Time, item float64) *ChangePoint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.s.PushAt(t, item)
+}
+
+// Window returns a copy of the current data window
+func (s *SyncStream) Window() []float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]float64(nil), s.s.Window()...)
+}
+
+// Do calls f with the underlying detector while holding the lock
+func (s *SyncStream) Do(f func(s *Stream)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f(s.s)
+}
+
+// SyncTimed is a Timed detector which is safe for concurrent use.  Items
+// pushed concurrently should still have non-decreasing times in the order
+// they acquire the lock, which is easiest if each takes its time once it
+// holds it, in Do.
+type SyncTimed struct {
+	mu sync.Mutex
+	w  *Timed
+}
+
+// NewSyncTimed returns a concurrency-safe detector for windows holding the
+// items from the last span of time, using d to check them
+func NewSyncTimed(d *Detector, span time.Duration) *SyncTimed {
+	return &SyncTimed{w: NewTimed(d, span)}
+}
+
+// Push is Timed.Push
+func (s *SyncTimed) Push(t time.Time, item float64) *ChangePoint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.w.Push(t, item)
+}
+
+// Window returns a copy of the items in the current window
+func (s *SyncTimed) Window() []float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]float64(nil), s.w.Window()...)
+}
+
+// Do calls f with the underlying detector while holding the lock
+func (s *SyncTimed) Do(f func(w *Timed)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f(s.w)
+}
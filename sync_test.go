@@ -0,0 +1,106 @@
+package change
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSyncRolling(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	w := steps(rnd, 500, 0, 5)
+
+	s := NewSyncRolling(New(30, 0.99999), 1000)
+	s.Do(func(r *Rolling) { r.Cooldown = 1000 })
+
+	// the producers push the items in blocks, each in order
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var found []*ChangePoint
+	for p := 0; p < 4; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for _, v := range w[p*250 : (p+1)*250] {
+				if cp := s.Push(v); cp != nil {
+					mu.Lock()
+					found = append(found, cp)
+					mu.Unlock()
+				}
+			}
+		}(p)
+	}
+	wg.Wait()
+
+	var n int
+	s.Do(func(r *Rolling) { n = r.items })
+	if n != len(w) || len(s.Window()) != len(w) {
+		t.Errorf("SyncRolling pushed %d items, window %d, wanted %d", n, len(s.Window()), len(w))
+	}
+
+	// the items are interleaved, so the window holds a mix of the levels
+	if len(found) > 1 {
+		t.Errorf("SyncRolling found %d change points with a cooldown, wanted at most 1", len(found))
+	}
+}
+
+func TestSyncStreamTimed(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	w := steps(rnd, 500, 0)
+
+	ss := NewSyncStream(100, 30, 10, 0.99)
+	st := NewSyncTimed(New(30, 0.99), time.Hour)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var wg sync.WaitGroup
+	for p := 0; p < 4; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for _, v := range w[p*125 : (p+1)*125] {
+				ss.Push(v)
+
+				// the times are taken under the lock, so they don't decrease
+				st.Do(func(tw *Timed) { tw.Push(start.Add(time.Duration(tw.items)*time.Second), v) })
+			}
+		}(p)
+	}
+	wg.Wait()
+
+	var n int
+	ss.Do(func(s *Stream) { n = s.items })
+	if n != len(w) || len(ss.Window()) != 100 {
+		t.Errorf("SyncStream pushed %d items, window %d, wanted %d and 100", n, len(ss.Window()), len(w))
+	}
+	if got := len(st.Window()); got != len(w) {
+		t.Errorf("SyncTimed window=%d items, wanted %d", got, len(w))
+	}
+}
+
+func TestManagerConcurrent(t *testing.T) {
+	m := NewManager(func(string) *Rolling { return NewRolling(New(30, 0.99), 100) })
+
+	var wg sync.WaitGroup
+	for p := 0; p < 8; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			rnd := rand.New(rand.NewSource(int64(p)))
+			for i := 0; i < 1000; i++ {
+				m.Push(fmt.Sprintf("series%d", rnd.Intn(20)), rnd.NormFloat64())
+			}
+		}(p)
+	}
+	wg.Wait()
+
+	var total int
+	for _, key := range m.Keys() {
+		total += m.Detector(key).items
+	}
+	if total != 8000 {
+		t.Errorf("Manager pushed %d items, wanted 8000", total)
+	}
+}
@@ -0,0 +1,156 @@
+package change
+
+import "math"
+
+// betainc returns the regularized incomplete beta function I_x(a, b)
+func betainc(a, b, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+
+	lab, _ := math.Lgamma(a + b)
+	la, _ := math.Lgamma(a)
+	lb, _ := math.Lgamma(b)
+	bt := math.Exp(lab - la - lb + a*math.Log(x) + b*math.Log1p(-x))
+
+	// the continued fraction converges rapidly only on this side of the
+	// mean; use the symmetry relation for the other
+	if x < (a+1)/(a+b+2) {
+		return bt * betacf(a, b, x) / a
+	}
+
+	return 1 - bt*betacf(b, a, 1-x)/b
+}
+
+// betacf evaluates the continued fraction for the incomplete beta function
+// using the modified Lentz's method
+func betacf(a, b, x float64) float64 {
+	const (
+		maxIter = 300
+		eps     = 3e-16
+		fpmin   = 1e-300
+	)
+
+	qab, qap, qam := a+b, a+1, a-1
+
+	c := 1.0
+	d := 1 - qab*x/qap
+	if math.Abs(d) < fpmin {
+		d = fpmin
+	}
+	d = 1 / d
+	h := d
+
+	for m := 1; m <= maxIter; m++ {
+		fm := float64(m)
+		m2 := 2 * fm
+
+		// even step
+		aa := fm * (b - fm) * x / ((qam + m2) * (a + m2))
+		d = 1 + aa*d
+		if math.Abs(d) < fpmin {
+			d = fpmin
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < fpmin {
+			c = fpmin
+		}
+		d = 1 / d
+		h *= d * c
+
+		// odd step
+		aa = -(a + fm) * (qab + fm) * x / ((a + m2) * (qap + m2))
+		d = 1 + aa*d
+		if math.Abs(d) < fpmin {
+			d = fpmin
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < fpmin {
+			c = fpmin
+		}
+		d = 1 / d
+		del := d * c
+		h *= del
+
+		if math.Abs(del-1) < eps {
+			break
+		}
+	}
+
+	return h
+}
+
+// studentTCDF returns the cumulative distribution function of Student's t-distribution with df degrees of freedom
+func studentTCDF(t, df float64) float64 {
+	if math.IsInf(t, 0) {
+		if t > 0 {
+			return 1
+		}
+		return 0
+	}
+
+	tail := 0.5 * betainc(df/2, 0.5, df/(df+t*t))
+	if t > 0 {
+		return 1 - tail
+	}
+	return tail
+}
+
+// studentTQuantile returns the value t such that studentTCDF(t, df) == p
+func studentTQuantile(p, df float64) float64 {
+	switch {
+	case p <= 0:
+		return math.Inf(-1)
+	case p >= 1:
+		return math.Inf(1)
+	case p < 0.5:
+		return -studentTQuantile(1-p, df)
+	case p == 0.5:
+		return 0
+	}
+
+	// bracket the root and bisect; the CDF is monotonic so this always converges
+	lo, hi := 0.0, 1.0
+	for studentTCDF(hi, df) < p {
+		lo, hi = hi, hi*2
+	}
+
+	for i := 0; i < 200 && hi-lo > 1e-12*hi; i++ {
+		mid := (lo + hi) / 2
+		if studentTCDF(mid, df) < p {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+
+	return (lo + hi) / 2
+}
+
+// welch performs Welch's unequal variances t-test.  It returns the t
+// statistic, the Welch-Satterthwaite degrees of freedom, and the two-sided
+// confidence that the means of the two samples differ.
+func welch(xs, ys Stats) (t, df, conf float64) {
+	n1, n2 := float64(xs.Len()), float64(ys.Len())
+	v1, v2 := xs.Var()/n1, ys.Var()/n2
+
+	diff := xs.Mean() - ys.Mean()
+	se := math.Sqrt(v1 + v2)
+
+	if se == 0 {
+		// no variance in either sample, so any difference is certain
+		if diff == 0 {
+			return 0, n1 + n2 - 2, 0
+		}
+		return math.Copysign(math.Inf(1), diff), n1 + n2 - 2, 1
+	}
+
+	t = diff / se
+	df = (v1 + v2) * (v1 + v2) / (v1*v1/(n1-1) + v2*v2/(n2-1))
+	conf = 1 - betainc(df/2, 0.5, df/(df+t*t))
+
+	return t, df, conf
+}
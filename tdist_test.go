@@ -0,0 +1,53 @@
+package change
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStudentT(t *testing.T) {
+
+	var tests = []struct {
+		t, df, p float64
+	}{
+		{0, 5, 0.5},
+		{1, 1, 0.75}, // Cauchy
+		{-1, 1, 0.25},
+		{2.228138851986274, 10, 0.975},
+		{2.570581835636314, 5, 0.975},
+		{3.169272672616951, 10, 0.995},
+		{1.959963984540054, 1e9, 0.975}, // approaches the normal distribution
+		{12.70620473617471, 1, 0.975},
+	}
+
+	for _, tt := range tests {
+		if p := studentTCDF(tt.t, tt.df); math.Abs(p-tt.p) > 1e-7 {
+			t.Errorf("studentTCDF(%f, %f)=%f, wanted %f", tt.t, tt.df, p, tt.p)
+		}
+
+		if q := studentTQuantile(tt.p, tt.df); math.Abs(q-tt.t) > 1e-6 {
+			t.Errorf("studentTQuantile(%f, %f)=%f, wanted %f", tt.p, tt.df, q, tt.t)
+		}
+	}
+}
+
+func TestWelch(t *testing.T) {
+	xs := Stats{mean: 20, variance: 4, n: 10}
+	ys := Stats{mean: 22, variance: 16, n: 20}
+
+	tstat, df, conf := welch(xs, ys)
+
+	// t = -2/sqrt(0.4+0.8)
+	if math.Abs(tstat-(-1.8257418583505538)) > 1e-9 {
+		t.Errorf("welch t=%f, wanted -1.825742", tstat)
+	}
+
+	// df = 1.44 / (0.16/9 + 0.64/19)
+	if math.Abs(df-27.9818) > 1e-4 {
+		t.Errorf("welch df=%f, wanted 27.9818", df)
+	}
+
+	if want := 1 - 2*studentTCDF(tstat, df); math.Abs(conf-want) > 1e-9 {
+		t.Errorf("welch conf=%f, wanted %f", conf, want)
+	}
+}
@@ -0,0 +1,158 @@
+package change
+
+import "time"
+
+// Timed checks a window holding the items pushed within a span of time, such
+// as the last 15 minutes, rather than a fixed number of items.  Irregularly
+// sampled series keep a window of the same duration, and old items expire by
+// age.  The window is checked every time an item is added, once it holds
+// enough items for the detector's MinSampleSize on either side of a split.
+type Timed struct {
+	// TimeWeighted reports the statistics before and after a change point,
+	// and the difference between their means, weighted by the time each item
+	// covers, as in TimeWeightedStats.  The change point is still found and
+	// tested, and its Confidence computed, with the items weighted equally,
+	// but it is only reported if the weighted difference also meets the
+	// detector's Direction, MinDifference and MinPercentChange.
+	TimeWeighted bool
+
+	// Suppression holds back change points during a warm-up, and after each
+	// one reported
+	Suppression
+
+	detector *Detector
+	span     time.Duration
+
+	// data and times hold the items, of which the window is those from start
+	data  []float64
+	times []time.Time
+	start int
+
+	// items is the number of items pushed
+	items int
+
+	suppressor suppressor
+}
+
+// NewTimed returns a detector for windows holding the items from the last
+// span of time, using d to check them
+func NewTimed(d *Detector, span time.Duration) *Timed {
+	return &Timed{detector: d, span: span}
+}
+
+// Push adds an item with its timestamp to the window, expires the items more
+// than span older than it, and checks the window.  The timestamps should not
+// decrease.  The returned change point's Index is the number of items pushed
+// before the first item after the change, and its Time is that item's time.
+func (w *Timed) Push(t time.Time, item float64) *ChangePoint {
+	w.data = append(w.data, item)
+	w.times = append(w.times, t)
+	w.items++
+	w.suppressor.observe(t)
+
+	cutoff := t.Add(-w.span)
+	for w.start < len(w.times) && !w.times[w.start].After(cutoff) {
+		w.start++
+	}
+
+	// reclaim the space of expired items once they are most of the buffer
+	if w.start > len(w.data)/2 {
+		w.data = w.data[:copy(w.data, w.data[w.start:])]
+		w.times = w.times[:copy(w.times, w.times[w.start:])]
+		w.start = 0
+	}
+
+	window := w.data[w.start:]
+	if len(window) < 2*w.detector.minSampleSize() {
+		return nil
+	}
+
+	cp := w.detector.Check(window)
+	if cp == nil {
+		return nil
+	}
+
+	times := w.times[w.start:]
+	if w.TimeWeighted && !w.weight(cp, window, times) {
+		return nil
+	}
+
+	if !w.suppressor.allow(w.Suppression, w.items, t) {
+		return nil
+	}
+
+	cp.Time = times[cp.Index]
+	cp.Index += w.items - len(window)
+
+	return cp
+}
+
+// weight replaces the statistics of cp with the time-weighted ones of the
+// window as it was checked, and reports whether the change is still reportable
+func (w *Timed) weight(cp *ChangePoint, window []float64, times []time.Time) bool {
+	p := w.detector.prepare(window)
+
+	ptimes := make([]time.Time, len(p.series))
+	for i := range ptimes {
+		ptimes[i] = times[p.index(i)]
+	}
+
+	k := p.split(cp.Index)
+	cp.Before = TimeWeightedStats(p.series[:k], ptimes[:k])
+	cp.After = TimeWeightedStats(p.series[k:], ptimes[k:])
+	cp.Difference = cp.After.Mean() - cp.Before.Mean()
+
+	return w.detector.reportable(cp)
+}
+
+// Window returns the items in the current window.  This should be treated as read-only
+func (w *Timed) Window() []float64 { return w.data[w.start:] }
+
+// Times returns the times of the items in the current window.  This should be treated as read-only
+func (w *Timed) Times() []time.Time { return w.times[w.start:] }
+
+// TimeWeightedStats returns the statistics of irregularly sampled items, each
+// weighted by the time it covers: half the interval since the item before it
+// plus half the interval until the item after it.  The first and last items
+// cover the whole interval to their only neighbour.  A burst of items close
+// together then counts for no more than a single item over the same time.  The
+// variance is scaled as for the same number of equally weighted items, and
+// items with identical times are weighted equally.
+func TimeWeightedStats(items []float64, times []time.Time) Stats {
+	n := len(items)
+	if n < 2 || !times[n-1].After(times[0]) {
+		return sampleStats(items)
+	}
+
+	weight := func(i int) float64 {
+		var w time.Duration
+		switch {
+		case i == 0:
+			w = 2 * times[1].Sub(times[0])
+		case i == n-1:
+			w = 2 * times[n-1].Sub(times[n-2])
+		default:
+			w = times[i+1].Sub(times[i-1])
+		}
+		return w.Seconds()
+	}
+
+	// West's weighted form of Welford's algorithm
+	var s Stats
+	var total, sqdiff float64
+	for i, x := range items {
+		w := weight(i)
+		if w <= 0 {
+			continue
+		}
+		total += w
+		delta := x - s.mean
+		s.mean += delta * w / total
+		sqdiff += w * delta * (x - s.mean)
+	}
+
+	s.n = n
+	s.variance = sqdiff / total * float64(n) / float64(n-1)
+
+	return s
+}
@@ -0,0 +1,171 @@
+package change
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestTimed(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	w := steps(rnd, 200, 0, 5)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// samples at irregular intervals of up to 10 seconds
+	at := make([]time.Time, len(w))
+	next := start
+	for i := range at {
+		at[i] = next
+		next = next.Add(time.Duration(1+rnd.Intn(10)) * time.Second)
+	}
+
+	tw := NewTimed(New(30, 0.99), 15*time.Minute)
+
+	var found bool
+	for i, v := range w {
+		cp := tw.Push(at[i], v)
+
+		times := tw.Times()
+		if len(times) != len(tw.Window()) {
+			t.Fatalf("len(Times())=%d, wanted len(Window())=%d", len(times), len(tw.Window()))
+		}
+		if span := at[i].Sub(times[0]); span >= 15*time.Minute {
+			t.Fatalf("Window() spans %v, wanted less than 15m", span)
+		}
+
+		if cp == nil {
+			continue
+		}
+		if !cp.Time.Equal(at[cp.Index]) {
+			t.Errorf("Push() Time=%v for index %d, wanted %v", cp.Time, cp.Index, at[cp.Index])
+		}
+		if cp.Index == 200 {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Errorf("Push() found no change at index 200")
+	}
+
+	// the same change point is found with time-weighted statistics
+	weighted := NewTimed(New(30, 0.99), 15*time.Minute)
+	weighted.TimeWeighted = true
+	for i, v := range w {
+		cp := weighted.Push(at[i], v)
+		if cp == nil || cp.Index != 200 {
+			continue
+		}
+
+		n := len(weighted.Window())
+		offset := i + 1 - n
+		before := TimeWeightedStats(w[offset:200], at[offset:200])
+		if cp.Before != before || cp.Difference != cp.After.Mean()-before.Mean() {
+			t.Errorf("Push(TimeWeighted) Before=%+v Difference=%v, wanted %+v", cp.Before, cp.Difference, before)
+		}
+		break
+	}
+
+	// a window that never holds enough items is not checked
+	sparse := NewTimed(New(30, 0.99), time.Minute)
+	for i, v := range w {
+		if cp := sparse.Push(start.Add(time.Duration(i)*10*time.Second), v); cp != nil {
+			t.Fatalf("Push() with 6 items in the window=%+v, wanted nil", cp)
+		}
+	}
+}
+
+func TestTimedWeightedReportable(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// a short burst of high items, then sparse items barely above the
+	// baseline: the unweighted difference is large, the weighted one small
+	var w []float64
+	var at []time.Time
+	now := start
+	add := func(n int, level float64, every time.Duration) {
+		for i := 0; i < n; i++ {
+			w = append(w, level+0.1*rnd.NormFloat64())
+			at = append(at, now)
+			now = now.Add(every)
+		}
+	}
+	add(100, 0, 10*time.Second)
+	add(100, 10, 100*time.Millisecond)
+	add(60, 1, 10*time.Second)
+
+	d := New(30, 0.99)
+	d.MinDifference = 4
+
+	tw := NewTimed(d, time.Hour)
+	tw.TimeWeighted = true
+
+	var found int
+	for i, v := range w {
+		cp := tw.Push(at[i], v)
+		if cp == nil {
+			continue
+		}
+		found++
+		if cp.Difference < d.MinDifference {
+			t.Fatalf("Push(TimeWeighted) at %d=%+v, wanted a difference of at least %v", i, cp, d.MinDifference)
+		}
+	}
+	if found == 0 {
+		t.Errorf("Push(TimeWeighted) found no change points, wanted the burst")
+	}
+
+	// missing items are left out of the weighted statistics, as they are
+	// out of the test
+	d = New(30, 0.99)
+	d.Missing = MissingSkip
+	tw = NewTimed(d, time.Hour)
+	tw.TimeWeighted = true
+
+	w[50] = math.NaN()
+	for i, v := range w[:200] {
+		if cp := tw.Push(at[i], v); cp != nil && (math.IsNaN(cp.Before.Mean()) || math.IsNaN(cp.Difference)) {
+			t.Fatalf("Push(TimeWeighted) with a missing item=%+v, wanted no NaN", cp)
+		}
+	}
+}
+
+func TestTimeWeightedStats(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(secs ...int) []time.Time {
+		ts := make([]time.Time, len(secs))
+		for i, s := range secs {
+			ts[i] = start.Add(time.Duration(s) * time.Second)
+		}
+		return ts
+	}
+
+	var tests = []struct {
+		items []float64
+		times []time.Time
+		mean  float64
+	}{
+		// evenly spaced items are weighted equally, except the ends
+		{[]float64{1, 2, 3, 4}, at(0, 10, 20, 30), 2.5},
+		// the burst of 10s covers 203 of the 597 weighted seconds, not 4 of the 6 items
+		{[]float64{0, 10, 10, 10, 10, 0}, at(0, 100, 101, 102, 103, 200), 10 * 203.0 / 597},
+		// equal times fall back to equal weights
+		{[]float64{1, 2, 3, 6}, at(5, 5, 5, 5), 3},
+	}
+
+	for _, tt := range tests {
+		s := TimeWeightedStats(tt.items, tt.times)
+		if math.Abs(s.Mean()-tt.mean) > 1e-12 || s.Len() != len(tt.items) {
+			t.Errorf("TimeWeightedStats(%v)=%+v, wanted mean %v", tt.items, s, tt.mean)
+		}
+	}
+
+	// evenly spaced items have their usual variance, apart from the ends
+	s := TimeWeightedStats([]float64{1, 3, 1, 3, 1, 3}, at(0, 1, 2, 3, 4, 5))
+	if want := sampleStats([]float64{1, 3, 1, 3, 1, 3}).Var(); math.Abs(s.Var()-want) > 1e-12 {
+		t.Errorf("TimeWeightedStats() variance=%v, wanted %v", s.Var(), want)
+	}
+}
@@ -0,0 +1,125 @@
+package change
+
+import (
+	"math"
+	"sort"
+)
+
+// Transform preprocesses a window before it is checked.
+type Transform interface {
+	// Apply returns the transformed window, without modifying series.  It
+	// may drop items from the start of the window, but not elsewhere.
+	Apply(series []float64) []float64
+}
+
+// Chain applies its transforms in order.
+type Chain []Transform
+
+// Apply implements the Transform interface
+func (c Chain) Apply(series []float64) []float64 {
+	for _, t := range c {
+		series = t.Apply(series)
+	}
+	return series
+}
+
+// Log takes the natural logarithm of each item, so that multiplicative
+// changes such as a doubling of latency become shifts in the mean.  Items
+// which are not positive become NaN, to be handled by the Missing policy.
+type Log struct{}
+
+// Apply implements the Transform interface
+func (Log) Apply(series []float64) []float64 {
+	out := make([]float64, len(series))
+	for i, v := range series {
+		if v > 0 {
+			out[i] = math.Log(v)
+		} else {
+			out[i] = math.NaN()
+		}
+	}
+	return out
+}
+
+// Diff takes the differences between items Lag apart, so that a change in
+// the trend of the series becomes a shift in the mean.  The first Lag items
+// have no difference and are dropped.
+type Diff struct {
+	// Lag is the distance between the items differenced.  If zero, 1 is used.
+	Lag int
+}
+
+// Apply implements the Transform interface
+func (d Diff) Apply(series []float64) []float64 {
+	lag := d.Lag
+	if lag < 1 {
+		lag = 1
+	}
+	if len(series) <= lag {
+		return nil
+	}
+
+	out := make([]float64, len(series)-lag)
+	for i := range out {
+		out[i] = series[i+lag] - series[i]
+	}
+	return out
+}
+
+// ZScore standardises the window to zero mean and unit variance, so that
+// scores and differences are in units of the window's standard deviation.
+// A window with no variance is only centred.
+type ZScore struct{}
+
+// Apply implements the Transform interface
+func (ZScore) Apply(series []float64) []float64 {
+	s := sampleStats(series)
+
+	sd := s.Stddev()
+	if sd == 0 {
+		sd = 1
+	}
+
+	out := make([]float64, len(series))
+	for i, v := range series {
+		out[i] = (v - s.Mean()) / sd
+	}
+	return out
+}
+
+// Winsorize replaces the items below the Lower quantile of the window with
+// that quantile, and likewise the items above the Upper quantile, limiting
+// the influence of outliers without dropping them.
+type Winsorize struct {
+	// Lower and Upper are the quantiles, between 0 and 1, at which the
+	// window is clipped.  If both are zero, 0.05 and 0.95 are used.
+	Lower, Upper float64
+}
+
+// Apply implements the Transform interface
+func (w Winsorize) Apply(series []float64) []float64 {
+	lower, upper := w.Lower, w.Upper
+	if lower == 0 && upper == 0 {
+		lower, upper = 0.05, 0.95
+	}
+
+	sorted := append([]float64(nil), series...)
+	sort.Float64s(sorted)
+
+	return Clamp{Min: quantile(sorted, lower), Max: quantile(sorted, upper)}.Apply(series)
+}
+
+// Clamp limits the items to between Min and Max, such as to cap impossible
+// readings from a sensor.
+type Clamp struct {
+	Min, Max float64
+}
+
+// Apply implements the Transform interface
+func (c Clamp) Apply(series []float64) []float64 {
+	out := make([]float64, len(series))
+	for i, v := range series {
+		out[i] = math.Max(c.Min, math.Min(c.Max, v))
+	}
+	return out
+}
@@ -0,0 +1,92 @@
+package change
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestTransforms(t *testing.T) {
+	series := []float64{1, 2, 4, 8, 100}
+
+	var tests = []struct {
+		t    Transform
+		want []float64
+	}{
+		{Log{}, []float64{0, math.Log(2), math.Log(4), math.Log(8), math.Log(100)}},
+		{Diff{}, []float64{1, 2, 4, 92}},
+		{Diff{Lag: 2}, []float64{3, 6, 96}},
+		{Clamp{Min: 2, Max: 8}, []float64{2, 2, 4, 8, 8}},
+		{Winsorize{Lower: 0.25, Upper: 0.75}, []float64{2, 2, 4, 8, 8}},
+		{Chain{Diff{}, Clamp{Min: 0, Max: 3}}, []float64{1, 2, 3, 3}},
+		{ZScore{}, []float64{-0.5101, -0.4869, -0.4406, -0.3478, 1.7854}},
+	}
+
+	for _, tt := range tests {
+		got := tt.t.Apply(series)
+		if len(got) != len(tt.want) {
+			t.Errorf("%T.Apply()=%v, wanted %v", tt.t, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if math.Abs(got[i]-tt.want[i]) > 1e-4 {
+				t.Errorf("%T.Apply()=%v, wanted %v", tt.t, got, tt.want)
+				break
+			}
+		}
+	}
+
+	if got := (Log{}).Apply([]float64{0, -1}); !math.IsNaN(got[0]) || !math.IsNaN(got[1]) {
+		t.Errorf("Log.Apply(0, -1)=%v, wanted NaN", got)
+	}
+
+	if got := (Diff{}).Apply([]float64{1}); len(got) != 0 {
+		t.Errorf("Diff.Apply(1 item)=%v, wanted none", got)
+	}
+}
+
+func TestDetectorTransform(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// latency that doubles, with noise proportional to its level
+	w := steps(rnd, 100, 0, 0)
+	for i := range w {
+		level := 10.0
+		if i >= 100 {
+			level = 20
+		}
+		w[i] = level * math.Exp(0.3*w[i])
+	}
+
+	d := Detector{MinConfidence: 0.99, Transform: Log{}}
+	if r := d.Check(w); r == nil || r.Index < 95 || r.Index > 105 {
+		t.Errorf("Check(Log)=%+v, wanted index 100", r)
+	} else if math.Abs(r.Difference-math.Log(2)) > 0.15 {
+		t.Errorf("Check(Log) Difference=%f, wanted about log 2", r.Difference)
+	}
+
+	// a change in slope is a change in the mean of the differences, whose
+	// indices are offset by the dropped item
+	ramp := make([]float64, 200)
+	for i := range ramp {
+		ramp[i] = float64(i) + 0.1*rnd.NormFloat64()
+		if i >= 100 {
+			ramp[i] = 100 + 3*float64(i-100) + 0.1*rnd.NormFloat64()
+		}
+	}
+
+	d = Detector{MinConfidence: 0.99, Transform: Diff{}}
+	if r := d.Check(ramp); r == nil || r.Index < 98 || r.Index > 102 {
+		t.Errorf("Check(Diff)=%+v, wanted index 100", r)
+	}
+
+	// the window is not modified
+	before := append([]float64(nil), w...)
+	d = Detector{MinConfidence: 0.99, Transform: Chain{Log{}, ZScore{}}}
+	d.Check(w)
+	for i := range w {
+		if w[i] != before[i] {
+			t.Fatalf("Check(Chain) modified the window at %d", i)
+		}
+	}
+}
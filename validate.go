@@ -0,0 +1,65 @@
+package change
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var (
+	// ErrShortWindow is returned when a window is too short to contain a
+	// change point with more than MinSampleSize items on either side
+	ErrShortWindow = errors.New("change: window too short")
+
+	// ErrNotFinite is returned when a window contains NaN or infinite values,
+	// and the detector's Missing policy is MissingReject
+	ErrNotFinite = errors.New("change: window contains NaN or Inf")
+
+	// ErrConfig is returned when the detector's configuration is invalid
+	ErrConfig = errors.New("change: invalid detector configuration")
+)
+
+// Validate returns an error describing why window cannot be checked by the
+// detector, or nil if it can.  Check does not validate its input, and returns
+// meaningless results for invalid windows.  Missing values are allowed if the
+// detector's Missing policy handles them, but skipped values do not count
+// towards the window's length.  The errors wrap ErrShortWindow, ErrNotFinite
+// or ErrConfig.
+func (d *Detector) Validate(window []float64) error {
+	if d.MinSampleSize < 0 {
+		return fmt.Errorf("%w: MinSampleSize %d is negative", ErrConfig, d.MinSampleSize)
+	}
+
+	if d.MinConfidence >= 1 || math.IsNaN(d.MinConfidence) {
+		return fmt.Errorf("%w: MinConfidence %v is not less than 1", ErrConfig, d.MinConfidence)
+	}
+
+	var n int
+	for i, v := range window {
+		if !missing(v) {
+			n++
+		} else if d.Missing == MissingReject {
+			return fmt.Errorf("%w: item %d is %v", ErrNotFinite, i, v)
+		}
+	}
+
+	if d.Missing == MissingInterpolate && n > 0 {
+		n = len(window)
+	}
+
+	// the variances either side of a split need two items each, so the
+	// smallest window has one more item on each side than MinSampleSize
+	if m := d.minSampleSize(); n < 2*m+2 {
+		return fmt.Errorf("%w: %d items, need at least %d for MinSampleSize %d", ErrShortWindow, n, 2*m+2, m)
+	}
+
+	return nil
+}
+
+// CheckValid is Check, but returns an error instead if the window is invalid, as described by Validate.
+func (d *Detector) CheckValid(window []float64) (*ChangePoint, error) {
+	if err := d.Validate(window); err != nil {
+		return nil, err
+	}
+	return d.Check(window), nil
+}
@@ -0,0 +1,46 @@
+package change
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	nan := steps(rnd, 50, 0, 2)
+	nan[10] = math.NaN()
+
+	inf := steps(rnd, 50, 0, 2)
+	inf[70] = math.Inf(-1)
+
+	var tests = []struct {
+		d      Detector
+		window []float64
+		err    error
+	}{
+		{Detector{MinConfidence: 0.99}, steps(rnd, 50, 0, 2), nil},
+		{Detector{MinConfidence: 0.99}, steps(rnd, 59, 0), ErrShortWindow},
+		{Detector{MinSampleSize: 5, MinConfidence: 0.99}, steps(rnd, 12, 0), nil},
+		{Detector{MinSampleSize: 5, MinConfidence: 0.99}, steps(rnd, 11, 0), ErrShortWindow},
+		{Detector{MinSampleSize: 1, MinConfidence: 0.99}, steps(rnd, 3, 0), ErrShortWindow},
+		{Detector{MinConfidence: 0.99}, nan, ErrNotFinite},
+		{Detector{MinConfidence: 0.99}, inf, ErrNotFinite},
+		{Detector{MinSampleSize: -1}, steps(rnd, 50, 0), ErrConfig},
+		{Detector{MinConfidence: 1}, steps(rnd, 50, 0, 2), ErrConfig},
+	}
+
+	for _, tt := range tests {
+		err := tt.d.Validate(tt.window)
+		if !errors.Is(err, tt.err) || (err == nil) != (tt.err == nil) {
+			t.Errorf("Validate(%d items)=%v, wanted %v", len(tt.window), err, tt.err)
+		}
+
+		cp, err2 := tt.d.CheckValid(tt.window)
+		if !errors.Is(err2, tt.err) || (err2 == nil) != (tt.err == nil) || (err2 != nil && cp != nil) {
+			t.Errorf("CheckValid(%d items)=%v, %v, wanted error %v", len(tt.window), cp, err2, tt.err)
+		}
+	}
+}
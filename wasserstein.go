@@ -0,0 +1,59 @@
+package change
+
+import (
+	"math"
+	"sort"
+)
+
+// Wasserstein scores splits by the 1-D Wasserstein (earth mover's) distance
+// between the empirical distributions before and after the split: the area
+// between their distribution functions.  Unlike the difference in means, it
+// is robust for multimodal data, where a change can move probability mass
+// between modes without moving the mean.
+//
+// The distance is scaled by sqrt(n1*n2/(n1+n2)), as for the
+// Kolmogorov-Smirnov statistic, so that splits near the edges of the window
+// are not favoured by their noise.  Each score takes O(n log n) time.
+type Wasserstein struct{}
+
+// Score implements the Scorer interface
+func (Wasserstein) Score(before, after []float64) float64 {
+	n1, n2 := float64(len(before)), float64(len(after))
+	return math.Sqrt(n1*n2/(n1+n2)) * wasserstein(before, after)
+}
+
+// wasserstein returns the 1-D Wasserstein distance between the empirical distributions of xs and ys
+func wasserstein(xs, ys []float64) float64 {
+	x := append([]float64(nil), xs...)
+	y := append([]float64(nil), ys...)
+	sort.Float64s(x)
+	sort.Float64s(y)
+
+	n1, n2 := float64(len(x)), float64(len(y))
+
+	// integrate |F1 - F2| between consecutive points of the merged samples
+	var dist, prev float64
+	var i, j int
+	for i < len(x) || j < len(y) {
+		var v float64
+		if j == len(y) || (i < len(x) && x[i] <= y[j]) {
+			v = x[i]
+		} else {
+			v = y[j]
+		}
+
+		if i+j > 0 {
+			dist += math.Abs(float64(i)/n1-float64(j)/n2) * (v - prev)
+		}
+		prev = v
+
+		for i < len(x) && x[i] == v {
+			i++
+		}
+		for j < len(y) && y[j] == v {
+			j++
+		}
+	}
+
+	return dist
+}
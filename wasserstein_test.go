@@ -0,0 +1,46 @@
+package change
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestWassersteinDistance(t *testing.T) {
+
+	var tests = []struct {
+		xs, ys []float64
+		want   float64
+	}{
+		{[]float64{1, 2, 3}, []float64{1, 2, 3}, 0},
+		{[]float64{1, 2, 3}, []float64{2, 3, 4}, 1}, // shifted by one
+		{[]float64{0}, []float64{5}, 5},
+		{[]float64{0, 0, 10, 10}, []float64{5, 5}, 5},
+	}
+
+	for _, tt := range tests {
+		if got := wasserstein(tt.xs, tt.ys); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("wasserstein(%v, %v)=%f, wanted %f", tt.xs, tt.ys, got, tt.want)
+		}
+	}
+}
+
+func TestWasserstein(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// two modes which move apart from +/-1 to +/-3, keeping the mean the
+	// same: the mean-based scatter is useless here
+	var w []float64
+	for i := 0; i < 200; i++ {
+		mode := float64(i%2*2 - 1)
+		if i >= 120 {
+			mode = 3 * mode
+		}
+		w = append(w, mode+0.3*rnd.NormFloat64())
+	}
+
+	d := Detector{MinSampleSize: 10, Scorer: Wasserstein{}, Test: KolmogorovSmirnov{}, MinConfidence: 0.99}
+	if r := d.Check(w); r == nil || r.Index < 115 || r.Index > 125 {
+		t.Errorf("Check with Wasserstein=%+v, wanted change near 120", r)
+	}
+}
@@ -0,0 +1,80 @@
+package change
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// WildSegment finds multiple change points in series by wild binary
+// segmentation (Fryzlewicz, 2014).  Binary segmentation only looks at the
+// best split of each whole segment, which can hide change points that are
+// close together.  Instead, the given number of random sub-intervals are
+// drawn from the series, and each segment is split at the best split found in
+// any interval that fits inside it, as long as it is significant.  More draws
+// give better accuracy at the cost of more computation.  As the best of many
+// intervals is tested, MinConfidence should be set higher than for a single
+// Check to keep the false positive rate down.
+//
+// The intervals are drawn using rnd; if it is nil, a fixed seed is used so
+// results are reproducible.  The change points are returned in order, with
+// Index as the offset into series.
+func (d *Detector) WildSegment(series []float64, draws int, rnd *rand.Rand) []*ChangePoint {
+	if rnd == nil {
+		rnd = rand.New(rand.NewSource(1))
+	}
+
+	minSampleSize := d.minSampleSize()
+
+	n := len(series)
+	minLen := 2 * minSampleSize
+
+	var intervals [][2]int
+	if n > minLen {
+		for i := 0; i < draws; i++ {
+			s, e := rnd.Intn(n+1), rnd.Intn(n+1)
+			if s > e {
+				s, e = e, s
+			}
+			if e-s >= minLen {
+				intervals = append(intervals, [2]int{s, e})
+			}
+		}
+	}
+
+	var cps []*ChangePoint
+	d.wildSegment(series, 0, n, intervals, &cps)
+
+	cps = d.limit(cps)
+	sort.Slice(cps, func(i, j int) bool { return cps[i].Index < cps[j].Index })
+
+	return cps
+}
+
+func (d *Detector) wildSegment(series []float64, lo, hi int, intervals [][2]int, cps *[]*ChangePoint) {
+
+	// the segment itself is always a candidate, as in plain binary segmentation
+	bestLo, bestHi := lo, hi
+	best := d.scan(series[lo:hi])
+
+	for _, iv := range intervals {
+		if iv[0] < lo || iv[1] > hi {
+			continue
+		}
+
+		if s := d.scan(series[iv[0]:iv[1]]); s.sb > best.sb {
+			best, bestLo, bestHi = s, iv[0], iv[1]
+		}
+	}
+
+	cp := d.test(series[bestLo:bestHi], best)
+	if cp == nil {
+		return
+	}
+
+	idx := bestLo + cp.Index
+	cp.Index = idx
+	*cps = append(*cps, cp)
+
+	d.wildSegment(series, lo, idx, intervals, cps)
+	d.wildSegment(series, idx, hi, intervals, cps)
+}
@@ -0,0 +1,40 @@
+package change
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestWildSegment(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// a short spike in the middle of a long flat series is hard for binary
+	// segmentation, as the best split of the whole series is weak
+	var spike []float64
+	spike = append(spike, steps(rnd, 200, 0)...)
+	spike = append(spike, steps(rnd, 20, 3)...)
+	spike = append(spike, steps(rnd, 200, 0)...)
+
+	var tests = []struct {
+		series []float64
+		want   []int
+	}{
+		{steps(rnd, 100, 0), nil},
+		{steps(rnd, 100, 0, 5, 0, -5), []int{100, 200, 300}},
+		{spike, []int{200, 220}},
+	}
+
+	d := New(10, 0.999999)
+
+	for _, tt := range tests {
+		var got []int
+		for _, cp := range d.WildSegment(tt.series, 500, nil) {
+			got = append(got, cp.Index)
+		}
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("WildSegment()=%v, wanted %v", got, tt.want)
+		}
+	}
+}